@@ -0,0 +1,91 @@
+package caches
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/palantir/stacktrace"
+)
+
+var _ ItemPriceDataSource = (*HtmlDataSource)(nil)
+
+// Reads prices off any HTML page, given a URL template and a CSS selector for the price; the last match wins
+type HtmlDataSource struct {
+	ScrapeRecorder
+	config PriceSourceConfig
+}
+
+func init() {
+	RegisterPriceSourceType("html", NewHtmlDataSource)
+}
+
+func NewHtmlDataSource(config PriceSourceConfig) (ItemPriceDataSource, error) {
+	if config.UrlTemplate == "" || config.Selector == "" {
+		return nil, stacktrace.NewError("HTML price source %q needs both a urlTemplate and a selector", config.Name)
+	}
+	return &HtmlDataSource{config: config}, nil
+}
+
+func (ds *HtmlDataSource) Name() string {
+	return ds.config.Name
+}
+
+func (ds *HtmlDataSource) FilePath() string {
+	return ds.config.CacheFilePath()
+}
+
+func (ds *HtmlDataSource) Price(itemName string) (float64, error) {
+	if slices.Contains(bannedItems, itemName) {
+		return 0.0, stacktrace.NewError(fmt.Sprintf("item %q was a banned item", itemName))
+	}
+
+	url := ds.config.Url(itemName)
+	start := time.Now()
+	price, res, err := ds.scrapePrice(url, itemName)
+	ds.logScrape(helpers.NewScrapeRecord(ds.Name(), itemName, url, start, res, price, err))
+	return price, err
+}
+
+func (ds *HtmlDataSource) scrapePrice(url string, itemName string) (float64, *http.Response, error) {
+	res, err := ds.get(url)
+	if err != nil {
+		return 0.0, nil, stacktrace.Propagate(err, "failed to reach %s", ds.Name())
+	}
+	defer res.Body.Close()
+
+	bodyCopy, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0.0, res, stacktrace.Propagate(err, "failed to read response body")
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyCopy))
+	if err != nil {
+		return 0.0, res, stacktrace.Propagate(err, "failed to parse HTML response from %s", ds.Name())
+	}
+
+	price := 0.0
+	doc.Find(ds.config.Selector).Each(func(index int, item *goquery.Selection) {
+		currPrice, err := parsePriceText(item.Text())
+		if err == nil {
+			price = currPrice
+		}
+	})
+	if price == 0.0 {
+		class := helpers.ClassifyUnparseableBody(bodyCopy)
+		ds.recordOutcome(res, class)
+		return 0.0, res, stacktrace.Propagate(&helpers.ResponseError{
+			Url:        url,
+			UserAgent:  helpers.RequestUserAgent(res),
+			StatusCode: res.StatusCode,
+			Class:      class,
+		}, "failed to retrieve price for %q from %s", itemName, ds.Name())
+	}
+	ds.recordOutcome(res, helpers.ResponseOk)
+	return price, res, nil
+}
@@ -39,10 +39,9 @@ func ItemPriceCacheInstance(dataSource ItemPriceDataSource) (ItemPriceCache, err
 	var err error
 	realCacheInstance := &RealItemPriceCache{
 		retryPolicy: helpers.RetryPolicy[float64]{
-			Backoff: func(retryCount int) int {
-				return 500
-			},
-			MaxTries: 3,
+			Backoff:      helpers.ExponentialBackoff(500, 30_000),
+			MaxTries:     4,
+			MaxBackoffMs: 30_000,
 		},
 		dataSource:    dataSource,
 		failedItems:   map[string]any{},
@@ -70,6 +69,7 @@ func (c *RealItemPriceCache) generateExpiry() {
 }
 
 func (c *RealItemPriceCache) Price(itemName string) float64 {
+	itemName = helpers.ItemAliasesInstance().Canonical(itemName)
 	if itemName == "nothing" {
 		return 0.0
 	}
@@ -89,7 +89,7 @@ func (c *RealItemPriceCache) Price(itemName string) float64 {
 
 	price, err := c.retryPolicy.Execute(func() (float64, error) {
 		return c.dataSource.Price(itemName)
-	}, fmt.Sprintf("Getting %s from %s", itemName, constants.ItemPriceDataSource.String()))
+	}, fmt.Sprintf("Getting %s from %s", itemName, c.dataSource.Name()))
 
 	if err != nil {
 		slog.Error(fmt.Sprintf("%+v", err))
@@ -119,6 +119,13 @@ func (c *RealItemPriceCache) flushToFile() error {
 }
 
 func (c *RealItemPriceCache) Close() error {
+	userAgents := helpers.UserAgentSelectorInstance()
+	if recorder, ok := c.dataSource.(interface{ scrapeRecorder() *ScrapeRecorder }); ok {
+		userAgents = recorder.scrapeRecorder().userAgents()
+	}
+	if err := userAgents.Save(); err != nil {
+		slog.Warn(fmt.Sprintf("Failed to save user agent statistics: %s", err))
+	}
 	return c.flushToFile()
 }
 
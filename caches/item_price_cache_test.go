@@ -2,11 +2,30 @@ package caches
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
 )
 
+// Keeps a test's requests out of the real scrape telemetry and user agent statistics
+func recordScrapesIn(t *testing.T, dataSource ItemPriceDataSource) ItemPriceDataSource {
+	recorder, ok := dataSource.(interface{ scrapeRecorder() *ScrapeRecorder })
+	if !ok {
+		t.Fatalf("%T doesn't record its scrapes", dataSource)
+	}
+	folder := t.TempDir()
+	recorder.scrapeRecorder().Telemetry = helpers.NewScrapeTelemetry(filepath.Join(folder, "telemetry.jsonl"))
+	recorder.scrapeRecorder().UserAgents = helpers.NewUserAgentSelector(filepath.Join(folder, "user_agent_stats.txt"))
+	return dataSource
+}
+
 func TestSaveToFile(t *testing.T) {
-	dataSource := NewJellyNeoDataSource()
+	dataSource, err := NewJellyNeoDataSource()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	recordScrapesIn(t, dataSource)
 	target, err := ItemPriceCacheInstance(dataSource)
 	if err != nil {
 		t.Fatalf("%s", err)
@@ -1,6 +1,182 @@
 package caches
 
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/palantir/stacktrace"
+)
+
 type ItemPriceDataSource interface {
+	Name() string
 	Price(itemName string) (float64, error)
 	FilePath() string
 }
+
+// Everything a price source needs to know about itself. Built-in sources only need Name and Type; the generic
+// "json" and "html" types are driven entirely by UrlTemplate plus PricePath/Selector.
+type PriceSourceConfig struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	UrlTemplate string `json:"urlTemplate,omitempty"`
+	PricePath   string `json:"pricePath,omitempty"`
+	Selector    string `json:"selector,omitempty"`
+	CacheFile   string `json:"cacheFile,omitempty"`
+}
+
+func (c PriceSourceConfig) CacheFilePath() string {
+	cacheFile := c.CacheFile
+	if cacheFile == "" {
+		cacheFile = fmt.Sprintf(constants.ItemPriceCacheFileNameTemplate, strings.ToLower(strings.ReplaceAll(c.Name, " ", "_")))
+	}
+	return constants.CombineRelativeFolderAndFilename(constants.DataFolder, cacheFile)
+}
+
+// Substitutes {item} (query-escaped) and {itemPath} (path-escaped) into the URL template
+func (c PriceSourceConfig) Url(itemName string) string {
+	return strings.NewReplacer(
+		"{item}", url.QueryEscape(itemName),
+		"{itemPath}", url.PathEscape(itemName),
+	).Replace(c.UrlTemplate)
+}
+
+type PriceSourcesFile struct {
+	// Name of the source to use; falls back to constants.ItemPriceDataSource
+	Use     string              `json:"use,omitempty"`
+	Sources []PriceSourceConfig `json:"sources"`
+}
+
+type PriceSourceFactory func(config PriceSourceConfig) (ItemPriceDataSource, error)
+
+var (
+	priceSourceMutex     = &sync.Mutex{}
+	priceSourceFactories = map[string]PriceSourceFactory{}
+	priceSourceConfigs   = map[string]PriceSourceConfig{}
+)
+
+// Registers a kind of price source, e.g. "json"; configs refer to it through their Type
+func RegisterPriceSourceType(typeName string, factory PriceSourceFactory) {
+	priceSourceMutex.Lock()
+	defer priceSourceMutex.Unlock()
+	priceSourceFactories[strings.ToLower(typeName)] = factory
+}
+
+// Registers a named, ready-to-use price source
+func RegisterPriceSource(config PriceSourceConfig) {
+	priceSourceMutex.Lock()
+	defer priceSourceMutex.Unlock()
+	priceSourceConfigs[strings.ToLower(config.Name)] = config
+}
+
+func PriceSourceNames() []string {
+	priceSourceMutex.Lock()
+	defer priceSourceMutex.Unlock()
+	names := helpers.Map(helpers.Values(priceSourceConfigs), func(config PriceSourceConfig) string {
+		return config.Name
+	})
+	slices.Sort(names)
+	return names
+}
+
+func NewPriceSource(config PriceSourceConfig) (ItemPriceDataSource, error) {
+	priceSourceMutex.Lock()
+	factory, exists := priceSourceFactories[strings.ToLower(config.Type)]
+	priceSourceMutex.Unlock()
+	if !exists {
+		return nil, stacktrace.NewError("price source %q has unknown type %q", config.Name, config.Type)
+	}
+	return factory(config)
+}
+
+func PriceSourceByName(name string) (ItemPriceDataSource, error) {
+	priceSourceMutex.Lock()
+	config, exists := priceSourceConfigs[strings.ToLower(name)]
+	priceSourceMutex.Unlock()
+	if !exists {
+		return nil, stacktrace.NewError("no price source named %q has been registered; the registered sources are %s", name, strings.Join(PriceSourceNames(), ", "))
+	}
+	return NewPriceSource(config)
+}
+
+// Registers the sources declared in the price sources file and returns the name of the source to use
+func LoadPriceSources(filePath string) (string, error) {
+	if !helpers.IsFileExists(filePath) {
+		return constants.ItemPriceDataSource, nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "failed to read price sources file: %s", filePath)
+	}
+
+	sourcesFile := PriceSourcesFile{}
+	if err = json.Unmarshal(content, &sourcesFile); err != nil {
+		return "", stacktrace.Propagate(err, "failed to parse price sources file: %s", filePath)
+	}
+
+	for _, config := range sourcesFile.Sources {
+		if config.Name == "" || config.Type == "" {
+			return "", stacktrace.NewError("every price source in %s needs a name and a type", filePath)
+		}
+		slog.Debug(fmt.Sprintf("Registered %q price source %q", config.Type, config.Name))
+		RegisterPriceSource(config)
+	}
+
+	return helpers.When(sourcesFile.Use == "", constants.ItemPriceDataSource, sourcesFile.Use), nil
+}
+
+// Strips the " NP" suffix and thousands separators off a price as displayed on a page
+func parsePriceText(text string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(text, " NP", ""), ",", "")), 64)
+}
+
+// Where a price source logs its requests and which user agents it makes them with: the shared telemetry file and
+// user agent statistics, unless a test gives it its own
+type ScrapeRecorder struct {
+	Telemetry  *helpers.ScrapeTelemetry
+	UserAgents *helpers.UserAgentSelector
+}
+
+func (r *ScrapeRecorder) scrapeRecorder() *ScrapeRecorder {
+	return r
+}
+
+func (r *ScrapeRecorder) telemetry() *helpers.ScrapeTelemetry {
+	if r.Telemetry != nil {
+		return r.Telemetry
+	}
+	return helpers.ScrapeTelemetryInstance()
+}
+
+func (r *ScrapeRecorder) logScrape(record helpers.ScrapeRecord) {
+	if err := r.telemetry().Log(record); err != nil {
+		// Telemetry is best-effort; never fail a price lookup over it
+		slog.Warn(fmt.Sprintf("Failed to log scrape telemetry: %s", err))
+	}
+}
+
+func (r *ScrapeRecorder) userAgents() *helpers.UserAgentSelector {
+	if r.UserAgents != nil {
+		return r.UserAgents
+	}
+	return helpers.UserAgentSelectorInstance()
+}
+
+func (r *ScrapeRecorder) get(url string) (*http.Response, error) {
+	return helpers.HumanlikeGetWith(r.userAgents(), url)
+}
+
+// Records how a response turned out against the user agent that made it
+func (r *ScrapeRecorder) recordOutcome(res *http.Response, class helpers.ResponseClass) {
+	r.userAgents().Record(helpers.RequestUserAgent(res), class)
+}
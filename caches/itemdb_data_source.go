@@ -1,23 +1,46 @@
 package caches
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"slices"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/palantir/stacktrace"
 )
 
 var _ ItemPriceDataSource = (*ItemDBDataSource)(nil)
 
-type ItemDBDataSource struct{}
+type ItemDBDataSource struct {
+	ScrapeRecorder
+	config PriceSourceConfig
+}
+
+func init() {
+	RegisterPriceSourceType("itemdb", func(config PriceSourceConfig) (ItemPriceDataSource, error) {
+		return &ItemDBDataSource{config: config}, nil
+	})
+	RegisterPriceSource(PriceSourceConfig{
+		Name: "ItemDB",
+		Type: "itemdb",
+	})
+}
 
-func NewItemDBDataSource() ItemPriceDataSource {
-	return &ItemDBDataSource{}
+func NewItemDBDataSource() (ItemPriceDataSource, error) {
+	dataSource, err := PriceSourceByName("ItemDB")
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to create the ItemDB price source")
+	}
+	return dataSource, nil
+}
+
+func (ds *ItemDBDataSource) Name() string {
+	return ds.config.Name
 }
 
 func normalisedItemDBItemName(itemName string) string {
@@ -33,7 +56,7 @@ func itemDBPriceUrl(itemName string) string {
 }
 
 func (ds *ItemDBDataSource) FilePath() string {
-	return constants.CombineRelativeFolderAndFilename(constants.DataFolder, constants.ItemDBItemPriceCacheFile)
+	return ds.config.CacheFilePath()
 }
 
 func (ds *ItemDBDataSource) Price(itemName string) (float64, error) {
@@ -41,25 +64,47 @@ func (ds *ItemDBDataSource) Price(itemName string) (float64, error) {
 		return 0.0, stacktrace.NewError(fmt.Sprintf("item %q was banned from search", itemName))
 	}
 
-	res, err := http.Get(itemDBPriceUrl(itemName))
+	url := itemDBPriceUrl(itemName)
+	start := time.Now()
+	price, res, err := ds.scrapePrice(url, itemName)
+	ds.logScrape(helpers.NewScrapeRecord(ds.Name(), itemName, url, start, res, price, err))
+	return price, err
+}
+
+func (ds *ItemDBDataSource) scrapePrice(url string, itemName string) (float64, *http.Response, error) {
+	res, err := ds.get(url)
 	if err != nil {
-		return 0.0, stacktrace.Propagate(err, "failed to reach ItemDB")
+		return 0.0, nil, stacktrace.Propagate(err, "failed to reach ItemDB")
 	}
 	defer res.Body.Close()
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+
+	bodyCopy, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0.0, res, stacktrace.Propagate(err, "failed to read response body")
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bodyCopy))
 	if err != nil {
-		return 0.0, stacktrace.Propagate(err, "failed to parse HTML response from ItemDB")
+		return 0.0, res, stacktrace.Propagate(err, "failed to parse HTML response from ItemDB")
 	}
 	price := 0.0
 	doc.Find(".chakra-stat__number").Each(func(index int, item *goquery.Selection) {
-		curr_price, err := strconv.ParseFloat(strings.ReplaceAll(strings.ReplaceAll(item.Text(), " NP", ""), ",", ""), 64)
+		curr_price, err := parsePriceText(item.Text())
 		if err == nil {
 			price = curr_price
 		}
 	})
 	if price == 0.0 {
-		return price, stacktrace.NewError(fmt.Sprintf("Failed to retrieve price for %q from ItemDB!", itemName))
+		class := helpers.ClassifyUnparseableBody(bodyCopy)
+		ds.recordOutcome(res, class)
+		return price, res, stacktrace.Propagate(&helpers.ResponseError{
+			Url:        url,
+			UserAgent:  helpers.RequestUserAgent(res),
+			StatusCode: res.StatusCode,
+			Class:      class,
+		}, "Failed to retrieve price for %q from ItemDB!", itemName)
 	}
+	ds.recordOutcome(res, helpers.ResponseOk)
 
-	return price, nil
+	return price, res, nil
 }
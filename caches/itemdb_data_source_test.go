@@ -6,7 +6,11 @@ import (
 
 func TestItemDBPrice(t *testing.T) {
 	itemName := "Green Apple"
-	target := NewItemDBDataSource()
+	target, err := NewItemDBDataSource()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	recordScrapesIn(t, target)
 	price, err := target.Price(itemName)
 
 	if err != nil || price <= 0 {
@@ -4,13 +4,13 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"slices"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/palantir/stacktrace"
 )
@@ -18,14 +18,34 @@ import (
 var _ ItemPriceDataSource = (*JellyNeoDataSource)(nil)
 
 type JellyNeoDataSource struct {
+	ScrapeRecorder
+	config PriceSourceConfig
 }
 
-func NewJellyNeoDataSource() ItemPriceDataSource {
-	return &JellyNeoDataSource{}
+func init() {
+	RegisterPriceSourceType("jellyneo", func(config PriceSourceConfig) (ItemPriceDataSource, error) {
+		return &JellyNeoDataSource{config: config}, nil
+	})
+	RegisterPriceSource(PriceSourceConfig{
+		Name: "JellyNeo",
+		Type: "jellyneo",
+	})
+}
+
+func NewJellyNeoDataSource() (ItemPriceDataSource, error) {
+	dataSource, err := PriceSourceByName("JellyNeo")
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to create the JellyNeo price source")
+	}
+	return dataSource, nil
+}
+
+func (ds *JellyNeoDataSource) Name() string {
+	return ds.config.Name
 }
 
 func (ds *JellyNeoDataSource) FilePath() string {
-	return constants.CombineRelativeFolderAndFilename(constants.DataFolder, constants.JellyNeoItemPriceCacheFile)
+	return ds.config.CacheFilePath()
 }
 
 func normalisedJellyNeoItemName(itemName string) string {
@@ -44,33 +64,48 @@ func (ds *JellyNeoDataSource) Price(itemName string) (float64, error) {
 	url := jellyNeoPriceUrl(itemName)
 	slog.Debug(fmt.Sprintf(`Calling "%s" for price`, url))
 
-	res, err := helpers.HumanlikeGet(url)
+	start := time.Now()
+	price, res, err := ds.scrapePrice(url, itemName)
+	ds.logScrape(helpers.NewScrapeRecord(ds.Name(), itemName, url, start, res, price, err))
+	return price, err
+}
+
+func (ds *JellyNeoDataSource) scrapePrice(url string, itemName string) (float64, *http.Response, error) {
+	res, err := ds.get(url)
 	if err != nil {
-		return 0.0, stacktrace.Propagate(err, "failed to reach JellyNeo")
+		return 0.0, nil, stacktrace.Propagate(err, "failed to reach JellyNeo")
 	}
 	defer res.Body.Close()
 
 	bodyCopy, err := io.ReadAll(res.Body)
 	if err != nil {
-		return 0.0, stacktrace.Propagate(err, "failed to read response body")
+		return 0.0, res, stacktrace.Propagate(err, "failed to read response body")
 	}
 
 	reader := strings.NewReader(string(bodyCopy))
 	doc, err := goquery.NewDocumentFromReader(reader)
 	if err != nil {
-		return 0.0, stacktrace.Propagate(err, "failed to create reader from response body")
+		return 0.0, res, stacktrace.Propagate(err, "failed to create reader from response body")
 	}
 
 	price := 0.0
 	doc.Find(".price-history-link").Each(func(index int, item *goquery.Selection) {
-		currPrice, err := strconv.ParseFloat(strings.ReplaceAll(strings.ReplaceAll(item.Text(), " NP", ""), ",", ""), 64)
+		currPrice, err := parsePriceText(item.Text())
 		if err == nil {
 			price = currPrice
 		}
 	})
 	if price == 0.0 {
 		slog.Debug(fmt.Sprintf(`Response from JellyNeo for %s: %s`, url, string(bodyCopy)))
-		return 0.0, stacktrace.NewError(fmt.Sprintf("failed to retrieve price for %q from JellyNeo", itemName))
+		class := helpers.ClassifyUnparseableBody(bodyCopy)
+		ds.recordOutcome(res, class)
+		return 0.0, res, stacktrace.Propagate(&helpers.ResponseError{
+			Url:        url,
+			UserAgent:  helpers.RequestUserAgent(res),
+			StatusCode: res.StatusCode,
+			Class:      class,
+		}, "failed to retrieve price for %q from JellyNeo", itemName)
 	}
-	return price, nil
+	ds.recordOutcome(res, helpers.ResponseOk)
+	return price, res, nil
 }
@@ -14,7 +14,11 @@ func TestMain(m *testing.M) {
 
 func TestJellyNeoPrice(t *testing.T) {
 	itemName := "Green Apple"
-	target := NewJellyNeoDataSource()
+	target, err := NewJellyNeoDataSource()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	recordScrapesIn(t, target)
 	price, err := target.Price(itemName)
 
 	if err != nil || price <= 0 {
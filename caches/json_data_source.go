@@ -0,0 +1,98 @@
+package caches
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/palantir/stacktrace"
+)
+
+var _ ItemPriceDataSource = (*JsonDataSource)(nil)
+
+// Reads prices from any JSON API, given a URL template and a JSONPath to the price
+type JsonDataSource struct {
+	ScrapeRecorder
+	config PriceSourceConfig
+}
+
+func init() {
+	RegisterPriceSourceType("json", NewJsonDataSource)
+}
+
+func NewJsonDataSource(config PriceSourceConfig) (ItemPriceDataSource, error) {
+	if config.UrlTemplate == "" || config.PricePath == "" {
+		return nil, stacktrace.NewError("JSON price source %q needs both a urlTemplate and a pricePath", config.Name)
+	}
+	return &JsonDataSource{config: config}, nil
+}
+
+func (ds *JsonDataSource) Name() string {
+	return ds.config.Name
+}
+
+func (ds *JsonDataSource) FilePath() string {
+	return ds.config.CacheFilePath()
+}
+
+func (ds *JsonDataSource) Price(itemName string) (float64, error) {
+	if slices.Contains(bannedItems, itemName) {
+		return 0.0, stacktrace.NewError(fmt.Sprintf("item %q was a banned item", itemName))
+	}
+
+	url := ds.config.Url(itemName)
+	start := time.Now()
+	price, res, err := ds.scrapePrice(url, itemName)
+	ds.logScrape(helpers.NewScrapeRecord(ds.Name(), itemName, url, start, res, price, err))
+	return price, err
+}
+
+func priceFromJson(value any) (float64, error) {
+	switch price := value.(type) {
+	case float64:
+		return price, nil
+	case string:
+		return parsePriceText(price)
+	default:
+		return 0.0, fmt.Errorf("expected a number or a string but found %T", value)
+	}
+}
+
+func (ds *JsonDataSource) scrapePrice(url string, itemName string) (float64, *http.Response, error) {
+	res, err := ds.get(url)
+	if err != nil {
+		return 0.0, nil, stacktrace.Propagate(err, "failed to reach %s", ds.Name())
+	}
+	defer res.Body.Close()
+
+	bodyCopy, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0.0, res, stacktrace.Propagate(err, "failed to read response body")
+	}
+
+	var document any
+	price := 0.0
+	if err = json.Unmarshal(bodyCopy, &document); err == nil {
+		var value any
+		if value, err = helpers.JsonPathLookup(document, ds.config.PricePath); err == nil {
+			price, err = priceFromJson(value)
+		}
+	}
+	if err != nil || price <= 0.0 {
+		class := helpers.ClassifyUnparseableBody(bodyCopy)
+		ds.recordOutcome(res, class)
+		return 0.0, res, stacktrace.Propagate(&helpers.ResponseError{
+			Url:        url,
+			UserAgent:  helpers.RequestUserAgent(res),
+			StatusCode: res.StatusCode,
+			Class:      class,
+			Cause:      err,
+		}, "failed to retrieve price for %q from %s at %s", itemName, ds.Name(), ds.config.PricePath)
+	}
+	ds.recordOutcome(res, helpers.ResponseOk)
+	return price, res, nil
+}
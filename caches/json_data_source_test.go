@@ -0,0 +1,52 @@
+package caches
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJsonDataSourceFromPriceSourcesFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "Green Apple" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"prices": [{"value": "1,234 NP"}, {"value": 1}]}}`)
+	}))
+	defer server.Close()
+
+	sourcesFilePath := filepath.Join(t.TempDir(), "sources.json")
+	err := os.WriteFile(sourcesFilePath, []byte(fmt.Sprintf(`{
+		"use": "Stand-in",
+		"sources": [{"name": "Stand-in", "type": "json", "urlTemplate": "%s/price?name={item}", "pricePath": "$.data.prices[0]['value']"}]
+	}`, server.URL)), 0755)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	sourceName, err := LoadPriceSources(sourcesFilePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	target, err := PriceSourceByName(sourceName)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	recordScrapesIn(t, target)
+
+	price, err := target.Price("Green Apple")
+	if err != nil || price != 1234 {
+		t.Fatalf("Expected the stand-in price of 1234 NP, but got %f (%v)", price, err)
+	}
+	if filepath.Base(target.FilePath()) != "neopets_stand-in_item_price_cache.txt" {
+		t.Fatalf("Unexpected cache file name %q", filepath.Base(target.FilePath()))
+	}
+
+	if _, err = target.Price("Red Apple"); err == nil {
+		t.Fatalf("Expected a missing item to fail")
+	}
+}
@@ -0,0 +1,122 @@
+package caches
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+)
+
+type PriceAdjustmentKind string
+
+const (
+	// Replaces the price
+	SetPrice PriceAdjustmentKind = "set"
+	// Adds to the price, or takes away from it if negative
+	AddToPrice PriceAdjustmentKind = "add"
+	// Changes the price by a fraction of itself, e.g. -0.5 halves it
+	ScalePrice PriceAdjustmentKind = "scale"
+)
+
+// A change to the price of an item, or of every item in an item group
+type PriceAdjustment struct {
+	Target string
+	Kind   PriceAdjustmentKind
+	Amount float64
+}
+
+// Parses "<item or group>=<change>", where the change is a price (2000000), an amount to add or take away (+5000,
+// -5000) or a percentage to change it by (-50%, +20%)
+func ParsePriceAdjustment(raw string) (PriceAdjustment, error) {
+	index := strings.LastIndex(raw, "=")
+	if index == -1 {
+		return PriceAdjustment{}, fmt.Errorf("expected a price adjustment like \"Nerkmids=-50%%\" but got %q", raw)
+	}
+	adjustment := PriceAdjustment{Target: helpers.CollapseWhitespace(raw[:index])}
+	if adjustment.Target == "" {
+		return PriceAdjustment{}, fmt.Errorf("price adjustment %q doesn't say which item or group it's for", raw)
+	}
+
+	change := strings.ReplaceAll(strings.TrimSpace(raw[index+1:]), ",", "")
+	switch {
+	case strings.HasSuffix(change, "%"):
+		adjustment.Kind = ScalePrice
+		change = strings.TrimSuffix(change, "%")
+	case strings.HasPrefix(change, "+") || strings.HasPrefix(change, "-"):
+		adjustment.Kind = AddToPrice
+	default:
+		adjustment.Kind = SetPrice
+	}
+	amount, err := strconv.ParseFloat(change, 64)
+	if err != nil {
+		return PriceAdjustment{}, fmt.Errorf("price adjustment %q has an invalid change %q", raw, raw[index+1:])
+	}
+	adjustment.Amount = helpers.When(adjustment.Kind == ScalePrice, amount/100, amount)
+	return adjustment, nil
+}
+
+func (a PriceAdjustment) apply(price float64) float64 {
+	switch a.Kind {
+	case SetPrice:
+		return a.Amount
+	case AddToPrice:
+		return max(0, price+a.Amount)
+	case ScalePrice:
+		return max(0, price*(1+a.Amount))
+	default:
+		return price
+	}
+}
+
+func (a PriceAdjustment) String() string {
+	switch a.Kind {
+	case SetPrice:
+		return fmt.Sprintf("%s at %s NP", a.Target, helpers.FormatFloat(a.Amount))
+	case AddToPrice:
+		return fmt.Sprintf("%s %s%s NP", a.Target, helpers.When(a.Amount < 0, "-", "+"), helpers.FormatFloat(max(a.Amount, -a.Amount)))
+	case ScalePrice:
+		return fmt.Sprintf("%s %s%s%%", a.Target, helpers.When(a.Amount < 0, "-", "+"), helpers.FormatPercentage(max(a.Amount, -a.Amount)))
+	default:
+		return a.Target
+	}
+}
+
+// Prices from another cache with adjustments applied in order on top, so scenarios never reach the real prices
+type ScenarioItemPriceCache struct {
+	ItemPriceCache
+	Adjustments []PriceAdjustment
+	itemGroups  *helpers.ItemGroups
+}
+
+var _ ItemPriceCache = (*ScenarioItemPriceCache)(nil)
+
+func NewScenarioItemPriceCache(itemPriceCache ItemPriceCache, adjustments []PriceAdjustment, itemGroups *helpers.ItemGroups) *ScenarioItemPriceCache {
+	return &ScenarioItemPriceCache{
+		ItemPriceCache: itemPriceCache,
+		Adjustments:    adjustments,
+		itemGroups:     itemGroups,
+	}
+}
+
+func (c *ScenarioItemPriceCache) isAdjusted(adjustment PriceAdjustment, itemName string) bool {
+	if group, isGroup := c.itemGroups.Group(adjustment.Target); isGroup {
+		return group.Contains(itemName)
+	}
+	return strings.EqualFold(helpers.ItemAliasesInstance().Canonical(adjustment.Target), itemName)
+}
+
+func (c *ScenarioItemPriceCache) Price(itemName string) float64 {
+	price := c.ItemPriceCache.Price(itemName)
+	for _, adjustment := range c.Adjustments {
+		if c.isAdjusted(adjustment, itemName) {
+			price = adjustment.apply(price)
+		}
+	}
+	return price
+}
+
+// The underlying cache is closed by whoever owns it
+func (c *ScenarioItemPriceCache) Close() error {
+	return nil
+}
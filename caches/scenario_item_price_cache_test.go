@@ -0,0 +1,61 @@
+package caches
+
+import (
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+)
+
+type fixedPrices map[string]float64
+
+func (p fixedPrices) Price(itemName string) float64 {
+	return p[itemName]
+}
+
+func (p fixedPrices) Close() error {
+	return nil
+}
+
+func TestParsePriceAdjustment(t *testing.T) {
+	expected := map[string]PriceAdjustment{
+		"Nerkmids=-50%":                {Target: "Nerkmids", Kind: ScalePrice, Amount: -0.5},
+		"Kasuki Lu Plushie=2,000,000":  {Target: "Kasuki Lu Plushie", Kind: SetPrice, Amount: 2_000_000},
+		" Lu  Codestone = +1000":       {Target: "Lu Codestone", Kind: AddToPrice, Amount: 1_000},
+		"Cursed Wand of Shadow=-2,500": {Target: "Cursed Wand of Shadow", Kind: AddToPrice, Amount: -2_500},
+	}
+	for raw, adjustment := range expected {
+		parsed, err := ParsePriceAdjustment(raw)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if parsed != adjustment {
+			t.Fatalf("Expected %q to parse to %+v, but got %+v", raw, adjustment, parsed)
+		}
+	}
+
+	for _, raw := range []string{"Nerkmids", "=50%", "Nerkmids=cheap"} {
+		if _, err := ParsePriceAdjustment(raw); err == nil {
+			t.Fatalf("Expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestScenarioAdjustsGroupsAndItemsInOrder(t *testing.T) {
+	prices := fixedPrices{"Lu Codestone": 8_000, "Har Codestone": 6_000, "Robot Muffin": 100}
+	groups := &helpers.ItemGroups{Groups: []*helpers.ItemGroup{helpers.NewItemGroup("Brown Codestones", "Lu Codestone", "Har Codestone")}}
+	target := NewScenarioItemPriceCache(prices, []PriceAdjustment{
+		{Target: "brown codestones", Kind: ScalePrice, Amount: -0.5},
+		{Target: "Lu Codestone", Kind: AddToPrice, Amount: 1_000},
+		{Target: "Robot Muffin", Kind: AddToPrice, Amount: -1_000},
+	}, groups)
+
+	expected := map[string]float64{"Lu Codestone": 5_000, "Har Codestone": 3_000, "Robot Muffin": 0}
+	for itemName, price := range expected {
+		if target.Price(itemName) != price {
+			t.Fatalf("Expected %s to cost %f, but got %f", itemName, price, target.Price(itemName))
+		}
+	}
+	if prices["Lu Codestone"] != 8_000 {
+		t.Fatalf("Expected the underlying prices to be untouched")
+	}
+}
@@ -7,107 +7,90 @@ import (
 	"strings"
 )
 
-type ItemPriceDataSourceType int
-
-func (i ItemPriceDataSourceType) String() string {
-	switch i {
-	case JellyNeo:
-		return "JellyNeo"
-	case ItemDB:
-		return "ItemDB"
-	default:
-		return "?"
-	}
-}
-
 const (
-	Unknown ItemPriceDataSourceType = iota
-	JellyNeo
-	ItemDB
-)
-
-const (
-	ItemPriceDataSource            = JellyNeo
+	ItemPriceDataSource            = "JellyNeo"
+	PriceSourcesFileName           = "neopets_price_sources.json"
 	DataFolder                     = "./../data/"
-	ItemDBItemPriceCacheFile       = "neopets_itemdb_item_price_cache.txt"
-	JellyNeoItemPriceCacheFile     = "neopets_jellyneo_item_price_cache.txt"
+	ItemPriceCacheFileNameTemplate = "neopets_%s_item_price_cache.txt"
+	UserAgentStatsFile             = "neopets_user_agent_stats.txt"
+	ScrapeTelemetryFile            = "neopets_scrape_telemetry.jsonl"
+	ItemAliasesFileName            = "neopets_item_aliases.txt"
+	BattledomeCatalogueFileName    = "neopets_battledome_catalogue.json"
+	ItemGroupsFileName             = "neopets_battledome_item_groups.txt"
+	ValuationProfilesFileName      = "neopets_valuation_profiles.json"
+	DropDataIndexFileName          = "neopets_battledome_drop_data_index.gob"
 	ItemWeightsFileName            = "neopets_battledome_item_weights.txt"
 	ItemDropRatesFileNameTemplate  = "neopets_battledome_item_drop_rates_%s_%d.txt"
-	GeneratedDropsFileNameTemplate = "neopets_battledome_generated_items_%s_%d.txt"
+	GeneratedDropsFileNamePrefix   = "neopets_battledome_generated_items_"
+	GeneratedDropsFileNameTemplate = GeneratedDropsFileNamePrefix + "%s_%s.txt"
 	DataExpiryTimeLayout           = "2006-01-02 15:04:05.000000"
 	TimeLayout                     = "2006/01/02 15:04:05"
+	DropDataFileNameLayout         = "2006_01_02.txt"
 	BattledomeDropsFolder          = "./../battledome_drop_data/"
 	FloatFormatLayout              = "#,###."
 	PercentageFormatLayout         = "#,###.##"
 	NumberOfItemsToPrint           = 15
 	BattledomeDropsPerDay          = 15
 	NumberOfItemsToGenerate        = 100_000_000
+	GeneratedDropsSeed             = 1
+	// Bump whenever the generator changes what it produces, so cached generated drops are regenerated
+	GeneratedDropsGeneratorVersion = 2
 	SignificanceLevel              = 0.05
 	NumberOfBootstrapSamples       = 100_000
+	// Profit intervals are the same from run to run unless --seed is given
+	BootstrapSeed = 1
+	// One of "day", "percentile", "bca" or "percentile-t"; --bootstrap overrides it
+	BootstrapMethod = "day"
+	// One of "clopper-pearson", "wilson", "agresti-coull", "jeffreys" or "mid-p"; --interval overrides it
+	BinomialIntervalMethod = "clopper-pearson"
+	DaysPerSeason          = 30
+	// The most valuable fraction of drops whose share of profit is shown by risk
+	TopDropsFraction        = 0.01
+	NumberOfHistogramBins   = 20
+	HistogramBarMaxLength   = 40
+	NumberOfGoalSimulations = 10_000
+	// Goal simulations that take longer than this count as never finishing
+	MaxGoalDays = 3_650
+	// Recorded fights with fewer drops than this are left out of goal plans unless --min-samples is given
+	GoalMinimumSamples = 150
+	GoalSeed           = 1
+	// One of "observed" or "datamined"; --source overrides it
+	GoalDropRates = "datamined"
+	// Shortlisted by recommend unless --top is given
+	RecommendationShortlistSize = 5
+	// Recorded fights with fewer drops than this aren't recommended unless --min-samples is given
+	RecommendationMinimumSamples = 150
+	// One of "mean", "lower" or "posterior"; --risk overrides it
+	RecommendationCriterion = "lower"
 
 	FilterArena                                  = ""
 	NumberOfDropsToPrint                         = 3
 	ShouldIgnoreChallengerDropsInArenaComparison = true
+	// Duplicate drop files are logged as warnings unless this is set
+	ShouldFailOnDuplicateDropFiles = false
+	// Bump whenever the drop data parser changes how files are read, so the index is rebuilt
+	DropDataIndexVersion = 1
+
+	// Items are valued at their market price unless --valuation names another profile
+	MarketValuationProfile   = "market"
+	TrainingValuationProfile = "training"
 )
 
 var (
-	BrownCodestones = []string{
-		"Bri Codestone",
-		"Eo Codestone",
-		"Har Codestone",
-		"Lu Codestone",
-		"Main Codestone",
-		"Mau Codestone",
-		"Orn Codestone",
-		"Tai-Kai Codestone",
-		"Vo Codestone",
-		"Zei Codestone",
-	}
-	RedCodestones = []string{
-		"Cui Codestone",
-		"Kew Codestone",
-		"Mag Codestone",
-		"Sho Codestone",
-		"Vux Codestone",
-		"Zed Codestone",
-	}
-	Arenas = []string{
-		"Cosmic Dome",
-		"Neocola Centre",
-		"Central Arena",
-		"Dome of the Deep",
-		"Rattling Cauldron",
-		"Pango Palladium",
-		"Frost Arena",
-		"Ugga Dome",
-	}
-	// Additional prizes not covered by the initial drop estimations
-	// e.g. Central Arena had Nerkmids added to their drop pool
-	AdditionalArenaSpecificDrops = map[string]map[string]any{
-		"Cosmic Dome":    {},
-		"Neocola Centre": {},
-		"Central Arena": {
-			"Nimmo Battle Cry":        nil,
-			"Aluminium Nerkmid":       nil,
-			"Basic Golden Nerkmid":    nil,
-			"Copper Nerkmid":          nil,
-			"Golden Nerkmid X":        nil,
-			"Golden Nerkmid XX":       nil,
-			"Good Nerkmid":            nil,
-			"Lesser Nerkmid":          nil,
-			"Magical Golden Nerkmid":  nil,
-			"Normal Golden Nerkmid":   nil,
-			"Normal Platinum Nerkmid": nil,
-			"Platinum Nerkmid X":      nil,
-			"Platinum Nerkmid XX":     nil,
-			"Ultimate Nerkmid":        nil,
-			"Ultra Golden Nerkmid":    nil,
-		},
-		"Dome of the Deep":  {},
-		"Rattling Cauldron": {},
-		"Pango Palladium":   {},
-		"Frost Arena":       {},
-		"Ugga Dome":         {},
+	// Shown by risk alongside the median
+	RiskPercentiles = []float64{0.05, 0.25, 0.75, 0.95}
+	// Days to an item's first drop are shown by dry at these percentiles
+	DryStreakPercentiles = []float64{0.5, 0.75, 0.9, 0.95, 0.99}
+	// Days to finish a wishlist are shown by goal at these percentiles
+	GoalPercentiles = []float64{0.5, 0.9}
+	// Compared by coverage unless --days or --rates are given
+	CoverageDays      = []int{10, 30, 100, 365}
+	CoverageDropRates = []float64{0.001, 0.005, 0.01, 0.05}
+	// The item groups whose stones each training school asks for, valued at the cost of the lessons they replace
+	// under the training valuation profile
+	TrainingSchoolCodestoneGroups = map[string]string{
+		"Mystery Island Training School": "Brown Codestones",
+		"Secret Ninja Training School":   "Red Codestones",
 	}
 )
 
@@ -129,6 +112,42 @@ func DropRatesFilePath(arena string) string {
 	return CombineRelativeFolderAndFilename(DataFolder, fmt.Sprintf(ItemDropRatesFileNameTemplate, strings.ReplaceAll(arena, " ", "_"), NumberOfItemsToGenerate))
 }
 
-func GeneratedDropsFilePath(arena string) string {
-	return CombineRelativeFolderAndFilename(DataFolder, fmt.Sprintf(GeneratedDropsFileNameTemplate, strings.ReplaceAll(arena, " ", "_"), NumberOfItemsToGenerate))
+func GeneratedDropsFileName(arena string, key string) string {
+	return fmt.Sprintf(GeneratedDropsFileNameTemplate, strings.ReplaceAll(arena, " ", "_"), key)
+}
+
+func GeneratedDropsFilePath(arena string, key string) string {
+	return CombineRelativeFolderAndFilename(DataFolder, GeneratedDropsFileName(arena, key))
+}
+
+func UserAgentStatsFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, UserAgentStatsFile)
+}
+
+func ScrapeTelemetryFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, ScrapeTelemetryFile)
+}
+
+func PriceSourcesFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, PriceSourcesFileName)
+}
+
+func ItemAliasesFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, ItemAliasesFileName)
+}
+
+func DropDataIndexFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, DropDataIndexFileName)
+}
+
+func BattledomeCatalogueFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, BattledomeCatalogueFileName)
+}
+
+func ItemGroupsFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, ItemGroupsFileName)
+}
+
+func ValuationProfilesFilePath() string {
+	return CombineRelativeFolderAndFilename(DataFolder, ValuationProfilesFileName)
 }
@@ -0,0 +1,149 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/palantir/stacktrace"
+)
+
+type CatalogueChallenger struct {
+	Name string `json:"name"`
+	// Overrides the catalogue's difficulties if set
+	Difficulties []string `json:"difficulties,omitempty"`
+	// What it takes to be able to fight the challenger, if anything
+	Unlock string `json:"unlock,omitempty"`
+}
+
+// A prize that was added to an arena after the item weights were datamined
+type CatalogueDrop struct {
+	Item string `json:"item"`
+	// As YYYY-MM-DD; empty if it isn't known
+	Added string `json:"added,omitempty"`
+}
+
+type CatalogueArena struct {
+	Name        string                `json:"name"`
+	Challengers []CatalogueChallenger `json:"challengers"`
+	ExtraDrops  []CatalogueDrop       `json:"extraDrops,omitempty"`
+}
+
+// The arenas and challengers in the Battledome, read from a data file so that new ones can be added without
+// recompiling
+type BattledomeCatalogue struct {
+	// The difficulties every challenger can be fought at, unless they say otherwise
+	Difficulties []string         `json:"difficulties"`
+	Arenas       []CatalogueArena `json:"arenas"`
+}
+
+var (
+	battledomeCatalogueOnce     = &sync.Once{}
+	battledomeCatalogueInstance *BattledomeCatalogue
+)
+
+func BattledomeCatalogueInstance() *BattledomeCatalogue {
+	battledomeCatalogueOnce.Do(func() {
+		catalogue, err := NewBattledomeCatalogue(constants.BattledomeCatalogueFilePath())
+		if err != nil {
+			panic(stacktrace.Propagate(err, "failed to load the battledome catalogue"))
+		}
+		battledomeCatalogueInstance = catalogue
+	})
+	return battledomeCatalogueInstance
+}
+
+func NewBattledomeCatalogue(filePath string) (*BattledomeCatalogue, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read %q", filePath)
+	}
+	catalogue := &BattledomeCatalogue{}
+	if err := json.Unmarshal(content, catalogue); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse %q as a battledome catalogue", filePath)
+	}
+
+	for _, arena := range catalogue.Arenas {
+		if arena.Name == "" {
+			return nil, fmt.Errorf("%s has an arena with no name", filePath)
+		}
+		for _, drop := range arena.ExtraDrops {
+			if drop.Added == "" {
+				continue
+			}
+			if _, err := time.Parse(time.DateOnly, drop.Added); err != nil {
+				return nil, stacktrace.Propagate(err, "the date %q that %q was added to %s should be YYYY-MM-DD", drop.Added, drop.Item, arena.Name)
+			}
+		}
+	}
+	return catalogue, nil
+}
+
+// In the order they appear in the file
+func (c *BattledomeCatalogue) ArenaNames() []string {
+	return Map(c.Arenas, func(arena CatalogueArena) string {
+		return arena.Name
+	})
+}
+
+func (c *BattledomeCatalogue) Arena(name string) (*CatalogueArena, bool) {
+	index := slices.IndexFunc(c.Arenas, func(arena CatalogueArena) bool {
+		return arena.Name == name
+	})
+	if index == -1 {
+		return nil, false
+	}
+	return &c.Arenas[index], true
+}
+
+func (c *BattledomeCatalogue) ChallengerNames(arena string) []string {
+	catalogueArena, exists := c.Arena(arena)
+	if !exists {
+		return []string{}
+	}
+	return Map(catalogueArena.Challengers, func(challenger CatalogueChallenger) string {
+		return challenger.Name
+	})
+}
+
+func (c *BattledomeCatalogue) Challenger(arena string, name string) (*CatalogueChallenger, bool) {
+	catalogueArena, exists := c.Arena(arena)
+	if !exists {
+		return nil, false
+	}
+	index := slices.IndexFunc(catalogueArena.Challengers, func(challenger CatalogueChallenger) bool {
+		return challenger.Name == name
+	})
+	if index == -1 {
+		return nil, false
+	}
+	return &catalogueArena.Challengers[index], true
+}
+
+func (c *BattledomeCatalogue) DifficultyNames(arena string, challenger string) []string {
+	catalogueChallenger, exists := c.Challenger(arena, challenger)
+	if exists && len(catalogueChallenger.Difficulties) > 0 {
+		return catalogueChallenger.Difficulties
+	}
+	return c.Difficulties
+}
+
+func (c *BattledomeCatalogue) IsExtraDrop(arena string, itemName string) bool {
+	catalogueArena, exists := c.Arena(arena)
+	return exists && slices.ContainsFunc(catalogueArena.ExtraDrops, func(drop CatalogueDrop) bool {
+		return drop.Item == itemName
+	})
+}
+
+// Across every arena
+func (c *BattledomeCatalogue) ExtraDropNames() []string {
+	return FlatMap(c.Arenas, func(arena CatalogueArena) []string {
+		return Map(arena.ExtraDrops, func(drop CatalogueDrop) string {
+			return drop.Item
+		})
+	})
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBattledomeCatalogueChecksExtraDropDates(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "catalogue.json")
+	content := `{"difficulties": ["Mighty"], "arenas": [{"name": "Central Arena", "challengers": [{"name": "Kasuki Lu", "unlock": "Beating the Flaming Meerca"}], "extraDrops": [{"item": "Nimmo Battle Cry", "added": "01/01/2025"}]}]}`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write the catalogue: %s", err)
+	}
+	if _, err := NewBattledomeCatalogue(filePath); err == nil || !strings.Contains(err.Error(), "should be YYYY-MM-DD") {
+		t.Fatalf("Expected the badly written date to be refused, but got %v", err)
+	}
+
+	content = strings.Replace(content, "01/01/2025", "2025-01-01", 1)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write the catalogue: %s", err)
+	}
+	catalogue, err := NewBattledomeCatalogue(filePath)
+	if err != nil {
+		t.Fatalf("Failed to load the catalogue: %s", err)
+	}
+	challenger, exists := catalogue.Challenger("Central Arena", "Kasuki Lu")
+	if !exists || challenger.Unlock != "Beating the Flaming Meerca" || catalogue.Arenas[0].ExtraDrops[0].Added != "2025-01-01" {
+		t.Fatalf("Expected the unlock and the date added to be read, but got %+v", catalogue.Arenas[0])
+	}
+}
@@ -146,6 +146,14 @@ func Values[K comparable, V any](m map[K]V) []V {
 	return values
 }
 
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := []K{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 func LazyWhen[T any](pred bool, ifTrue func() T, ifFalse func() T) T {
 	if pred {
 		return ifTrue()
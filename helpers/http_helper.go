@@ -1,13 +1,86 @@
 package helpers
 
 import (
+	"bytes"
 	"fmt"
 	"log/slog"
-	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
+type ResponseClass int
+
+const (
+	ResponseOk ResponseClass = iota
+	ResponseBlocked
+	ResponseRateLimited
+	ResponseNotFound
+	ResponseMarkupChanged
+	ResponseServerError
+	ResponseNetworkError
+	// Anything else, e.g. a bad request; asking again won't help
+	ResponseClientError
+)
+
+func (c ResponseClass) String() string {
+	switch c {
+	case ResponseOk:
+		return "ok"
+	case ResponseBlocked:
+		return "blocked"
+	case ResponseRateLimited:
+		return "rate-limited"
+	case ResponseNotFound:
+		return "not-found"
+	case ResponseMarkupChanged:
+		return "markup-changed"
+	case ResponseServerError:
+		return "server-error"
+	case ResponseNetworkError:
+		return "network-error"
+	case ResponseClientError:
+		return "client-error"
+	default:
+		return "?"
+	}
+}
+
+// Whether another attempt at the same request could reasonably succeed
+func (c ResponseClass) IsRetryable() bool {
+	switch c {
+	case ResponseBlocked, ResponseRateLimited, ResponseServerError, ResponseNetworkError:
+		return true
+	default:
+		return false
+	}
+}
+
+// Whether the response says something about how the site treats the user agent, as opposed to the item
+func (c ResponseClass) IsUserAgentFault() bool {
+	return c == ResponseBlocked || c == ResponseRateLimited
+}
+
+type ResponseError struct {
+	Url        string
+	UserAgent  string
+	StatusCode int
+	Class      ResponseClass
+	RetryAfter time.Duration
+	Cause      error
+}
+
+func (e *ResponseError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("request to %q was %s: %s", e.Url, e.Class, e.Cause)
+	}
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("request to %q was %s (status %d, retry after %s)", e.Url, e.Class, e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("request to %q was %s (status %d)", e.Url, e.Class, e.StatusCode)
+}
+
 var (
 	// Generated from https://iplogger.org/useragents/
 	USER_AGENTS = []string{
@@ -99,11 +172,95 @@ var (
 	}
 )
 
-func userAgent() string {
-	return strings.Split(USER_AGENTS[rand.IntN(len(USER_AGENTS))], "|")[1]
+var (
+	// Phrases that show up on anti-bot interstitials served with a 200
+	blockedPageMarkers = [][]byte{
+		[]byte("cf-challenge"),
+		[]byte("Attention Required!"),
+		[]byte("Just a moment..."),
+		[]byte("captcha"),
+		[]byte("Access denied"),
+	}
+)
+
+// Accepts either delta-seconds or an HTTP date, as per RFC 9110
+func ParseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	retryAt, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if retryAt.Before(now) {
+		return 0, true
+	}
+	return retryAt.Sub(now), true
+}
+
+func ClassifyStatus(statusCode int) ResponseClass {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return ResponseOk
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		return ResponseNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ResponseRateLimited
+	case statusCode == http.StatusForbidden || statusCode == http.StatusUnauthorized:
+		return ResponseBlocked
+	case statusCode == http.StatusServiceUnavailable:
+		// Cloudflare and friends use 503 for both outages and challenges
+		return ResponseRateLimited
+	case statusCode >= 500:
+		return ResponseServerError
+	default:
+		// Redirects are followed, so this is a 4xx we don't otherwise handle or a status we never expect
+		return ResponseClientError
+	}
+}
+
+func IsBlockedPage(body []byte) bool {
+	for _, marker := range blockedPageMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Classifies a successfully-received page whose price could not be found
+func ClassifyUnparseableBody(body []byte) ResponseClass {
+	if IsBlockedPage(body) {
+		return ResponseBlocked
+	}
+	return ResponseMarkupChanged
 }
 
+func RequestUserAgent(res *http.Response) string {
+	if res == nil || res.Request == nil {
+		return ""
+	}
+	return res.Request.Header.Get("User-Agent")
+}
+
+// Performs a GET with a user agent picked by the shared UserAgentSelector
 func HumanlikeGet(url string) (*http.Response, error) {
+	return HumanlikeGetWith(UserAgentSelectorInstance(), url)
+}
+
+// Performs a GET with a user agent picked by selector.
+// Non-2xx responses are closed and returned as a *ResponseError; 2xx responses are returned as-is and the caller
+// is expected to record the outcome against the selector once it knows whether the body was usable.
+func HumanlikeGetWith(selector *UserAgentSelector, url string) (*http.Response, error) {
 	client := &http.Client{
 		Transport: &http.Transport{},
 	}
@@ -113,8 +270,36 @@ func HumanlikeGet(url string) (*http.Response, error) {
 		return nil, err
 	}
 
-	userAgent := userAgent()
+	userAgent := selector.Select()
 	req.Header.Set("User-Agent", userAgent)
 	slog.Debug(fmt.Sprintf("[%s] Using User-Agent: %q", url, userAgent))
-	return client.Do(req)
+	res, err := client.Do(req)
+	if err != nil {
+		selector.Record(userAgent, ResponseNetworkError)
+		return nil, &ResponseError{
+			Url:       url,
+			UserAgent: userAgent,
+			Class:     ResponseNetworkError,
+			Cause:     err,
+		}
+	}
+
+	class := ClassifyStatus(res.StatusCode)
+	if class == ResponseOk {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+	responseErr := &ResponseError{
+		Url:        url,
+		UserAgent:  userAgent,
+		StatusCode: res.StatusCode,
+		Class:      class,
+	}
+	if retryAfter, ok := ParseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+		responseErr.RetryAfter = retryAfter
+	}
+	selector.Record(userAgent, class)
+	slog.Debug(responseErr.Error())
+	return nil, responseErr
 }
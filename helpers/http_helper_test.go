@@ -0,0 +1,123 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	delay, ok := ParseRetryAfter("120", now)
+	if !ok || delay != 2*time.Minute {
+		t.Fatalf("Expected delta-seconds Retry-After to parse as 2m, but got %s (ok: %t)", delay, ok)
+	}
+
+	delay, ok = ParseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	if !ok || delay != 30*time.Second {
+		t.Fatalf("Expected HTTP-date Retry-After to parse as 30s, but got %s (ok: %t)", delay, ok)
+	}
+
+	if _, ok = ParseRetryAfter("soon", now); ok {
+		t.Fatalf("Expected an unparseable Retry-After to be rejected")
+	}
+}
+
+func TestHumanlikeGetClassifiesRateLimiting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	res, err := HumanlikeGetWith(NewUserAgentSelector(filepath.Join(t.TempDir(), "user_agent_stats.txt")), server.URL)
+	if res != nil {
+		t.Fatalf("Expected no response to be returned for a rate-limited request")
+	}
+	responseErr, ok := err.(*ResponseError)
+	if !ok {
+		t.Fatalf("Expected a *ResponseError, but got %T: %s", err, err)
+	}
+	if responseErr.Class != ResponseRateLimited || responseErr.RetryAfter != 7*time.Second {
+		t.Fatalf("Expected rate-limited with a 7s Retry-After, but got %s with %s", responseErr.Class, responseErr.RetryAfter)
+	}
+}
+
+func TestRetryPolicyDoesNotRetryNotFound(t *testing.T) {
+	attempts := 0
+	policy := RetryPolicy[int]{
+		Backoff:  func(int) int { return 0 },
+		MaxTries: 5,
+	}
+	_, err := policy.Execute(func() (int, error) {
+		attempts++
+		return 0, stacktrace.Propagate(&ResponseError{Class: ResponseNotFound}, "wrapped")
+	}, "test")
+	if err == nil || attempts != 1 {
+		t.Fatalf("Expected a single attempt for a not-found response, but there were %d", attempts)
+	}
+}
+
+func TestRetryPolicyGivesUpOnLongRetryAfter(t *testing.T) {
+	attempts := 0
+	policy := RetryPolicy[int]{
+		Backoff:      func(int) int { return 0 },
+		MaxTries:     5,
+		MaxBackoffMs: 30_000,
+	}
+	_, err := policy.Execute(func() (int, error) {
+		attempts++
+		return 0, &ResponseError{Class: ResponseRateLimited, RetryAfter: 24 * time.Hour}
+	}, "test")
+	if err == nil || attempts != 1 {
+		t.Fatalf("Expected a single attempt when asked to wait a day, but there were %d", attempts)
+	}
+}
+
+func TestClassifyStatusDoesNotRetryClientErrors(t *testing.T) {
+	for _, statusCode := range []int{http.StatusBadRequest, http.StatusMethodNotAllowed, http.StatusNotModified} {
+		if class := ClassifyStatus(statusCode); class != ResponseClientError || class.IsRetryable() {
+			t.Fatalf("Expected status %d to be a client error that isn't retried, but got %s", statusCode, class)
+		}
+	}
+	if class := ClassifyStatus(http.StatusBadGateway); class != ResponseServerError {
+		t.Fatalf("Expected status 502 to be a server error, but got %s", class)
+	}
+}
+
+func TestUserAgentSelectorPrefersWorkingAgents(t *testing.T) {
+	selector := NewUserAgentSelector(filepath.Join(t.TempDir(), "stats.txt"))
+	records := selector.Records()
+	good := records[0].UserAgent
+	for _, record := range records {
+		for range 50 {
+			selector.Record(record.UserAgent, When(record.UserAgent == good, ResponseOk, ResponseBlocked))
+		}
+	}
+
+	picks := 0
+	for range 100 {
+		if selector.Select() == good {
+			picks++
+		}
+	}
+	if picks < 90 {
+		t.Fatalf("Expected the only working user agent to be picked almost every time, but it was picked %d/100 times", picks)
+	}
+
+	if err := selector.Save(); err != nil {
+		t.Fatalf("%s", err)
+	}
+	reloaded := NewUserAgentSelector(selector.filePath)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if reloaded.Records()[0].UserAgent != good || reloaded.Records()[0].Successes != 50 {
+		t.Fatalf("Expected statistics to survive a save/load round trip")
+	}
+}
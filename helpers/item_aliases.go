@@ -0,0 +1,150 @@
+package helpers
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/palantir/stacktrace"
+)
+
+// Maps the spellings that turn up in hand-typed drop files (odd casing, stray whitespace, old item names) onto one
+// canonical item name.
+type ItemAliases struct {
+	mutex    sync.RWMutex
+	filePath string
+	// Both keyed on the lower-cased, whitespace-collapsed name
+	aliases        map[string]string
+	canonicalNames map[string]string
+}
+
+var (
+	itemAliasesOnce     = &sync.Once{}
+	itemAliasesInstance *ItemAliases
+)
+
+func ItemAliasesInstance() *ItemAliases {
+	itemAliasesOnce.Do(func() {
+		// Every source of canonical names is registered here, before anything is looked up, so that a name is always
+		// canonicalised the same way no matter which part of the program asks first
+		itemAliasesInstance = NewItemAliases(constants.ItemAliasesFilePath())
+		itemAliasesInstance.AddCanonicalNames(ItemGroupsInstance().ItemNames()...)
+		itemAliasesInstance.AddCanonicalNames(BattledomeCatalogueInstance().ExtraDropNames()...)
+		names, err := weightedItemNames(constants.ItemWeightsFilePath())
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to add the weighted items to the item aliases: %s", err))
+		}
+		itemAliasesInstance.AddCanonicalNames(names...)
+		if err := itemAliasesInstance.Load(); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to load item aliases; item names will only have their whitespace normalised: %s", err))
+		}
+	})
+	return itemAliasesInstance
+}
+
+// The names of the items in the weights file, without parsing their weights
+func weightedItemNames(filePath string) ([]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open item weights file: %s", filePath)
+	}
+	defer file.Close()
+
+	names := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name, _, isItemWeight := strings.Cut(line, " - "); isItemWeight {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read item weights file: %s", filePath)
+	}
+	return names, nil
+}
+
+func NewItemAliases(filePath string) *ItemAliases {
+	return &ItemAliases{
+		filePath:       filePath,
+		aliases:        map[string]string{},
+		canonicalNames: map[string]string{},
+	}
+}
+
+func itemAliasKey(name string) string {
+	return strings.ToLower(CollapseWhitespace(name))
+}
+
+// Names added here win over any other casing of the same name
+func (a *ItemAliases) AddCanonicalNames(names ...string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	for _, name := range names {
+		a.canonicalNames[itemAliasKey(name)] = CollapseWhitespace(name)
+	}
+}
+
+func (a *ItemAliases) AddAlias(alias string, canonicalName string) {
+	a.AddCanonicalNames(canonicalName)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.aliases[itemAliasKey(alias)] = CollapseWhitespace(canonicalName)
+}
+
+func (a *ItemAliases) Canonical(name string) string {
+	key := itemAliasKey(name)
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	if canonicalName, exists := a.aliases[key]; exists {
+		return canonicalName
+	}
+	if canonicalName, exists := a.canonicalNames[key]; exists {
+		return canonicalName
+	}
+	return CollapseWhitespace(name)
+}
+
+// The file has one "Alias|Canonical Name" per line; a line without a "|" just declares a canonical name
+func (a *ItemAliases) Load() error {
+	if !IsFileExists(a.filePath) {
+		return nil
+	}
+
+	file, err := os.Open(a.filePath)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to open item aliases file: %s", a.filePath)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		alias, canonicalName, isAlias := strings.Cut(line, "|")
+		if !isAlias {
+			a.AddCanonicalNames(line)
+			continue
+		}
+		if strings.TrimSpace(alias) == "" || strings.TrimSpace(canonicalName) == "" {
+			return fmt.Errorf("%s:%d: expected \"Alias|Canonical Name\" but got %q", a.filePath, lineNumber, line)
+		}
+		a.AddAlias(alias, canonicalName)
+	}
+	if err := scanner.Err(); err != nil {
+		return stacktrace.Propagate(err, "failed to read item aliases file: %s", a.filePath)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestItemAliasesCanonicalise(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "aliases.txt")
+	content := "# Renamed in 2025\nOld Blue Walein|Blue Walein\n\nFizzy Neocola Bottles\n"
+	if err := os.WriteFile(filePath, []byte(content), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	aliases := NewItemAliases(filePath)
+	if err := aliases.Load(); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	cases := map[string]string{
+		"old blue  walein ":     "Blue Walein",
+		"BLUE WALEIN":           "Blue Walein",
+		"fizzy neocola bottles": "Fizzy Neocola Bottles",
+		"  Green  Walein":       "Green Walein",
+	}
+	for name, expected := range cases {
+		if actual := aliases.Canonical(name); actual != expected {
+			t.Fatalf("Expected %q to be canonicalised to %q, but got %q", name, expected, actual)
+		}
+	}
+}
+
+func TestItemAliasesInstanceKnowsWeightedItems(t *testing.T) {
+	if actual := ItemAliasesInstance().Canonical("robot  muffin"); actual != "Robot Muffin" {
+		t.Fatalf("Expected a weighted item to be canonical without anything else being loaded first, but got %q", actual)
+	}
+}
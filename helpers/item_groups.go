@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/palantir/stacktrace"
+)
+
+// A set of items whose drop rates are worth looking at together, e.g. brown codestones
+type ItemGroup struct {
+	Name string
+	// Item names, or patterns where "*" matches anything
+	Members  []string
+	patterns []*regexp.Regexp
+}
+
+func NewItemGroup(name string, members ...string) *ItemGroup {
+	group := &ItemGroup{Name: name}
+	group.Add(members...)
+	return group
+}
+
+func isItemGroupPattern(member string) bool {
+	return strings.Contains(member, "*")
+}
+
+func (g *ItemGroup) Add(members ...string) {
+	for _, member := range members {
+		member = CollapseWhitespace(member)
+		g.Members = append(g.Members, member)
+		if isItemGroupPattern(member) {
+			parts := Map(strings.Split(member, "*"), regexp.QuoteMeta)
+			g.patterns = append(g.patterns, regexp.MustCompile("(?i)^"+strings.Join(parts, ".*")+"$"))
+		}
+	}
+}
+
+// The members that are item names rather than patterns
+func (g *ItemGroup) ItemNames() []string {
+	return Filter(g.Members, func(member string) bool {
+		return !isItemGroupPattern(member)
+	})
+}
+
+func (g *ItemGroup) Contains(itemName string) bool {
+	for _, member := range g.ItemNames() {
+		if strings.EqualFold(member, itemName) {
+			return true
+		}
+	}
+	for _, pattern := range g.patterns {
+		if pattern.MatchString(itemName) {
+			return true
+		}
+	}
+	return false
+}
+
+type ItemGroups struct {
+	// In the order they appear in the file
+	Groups []*ItemGroup
+}
+
+var (
+	itemGroupsOnce     = &sync.Once{}
+	itemGroupsInstance *ItemGroups
+)
+
+func ItemGroupsInstance() *ItemGroups {
+	itemGroupsOnce.Do(func() {
+		itemGroups, err := NewItemGroups(constants.ItemGroupsFilePath())
+		if err != nil {
+			slog.Warn(fmt.Sprintf("Failed to load item groups; no group drop rates will be shown: %s", err))
+			itemGroups = &ItemGroups{}
+		}
+		itemGroupsInstance = itemGroups
+	})
+	return itemGroupsInstance
+}
+
+// The file has a "[Group Name]" line before each group's members, one per line
+func NewItemGroups(filePath string) (*ItemGroups, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open item groups file: %s", filePath)
+	}
+	defer file.Close()
+
+	itemGroups := &ItemGroups{}
+	var group *ItemGroup
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("%s:%d: item group has no name", filePath, lineNumber)
+			}
+			if _, exists := itemGroups.Group(name); exists {
+				return nil, fmt.Errorf("%s:%d: item group %q is defined more than once", filePath, lineNumber, name)
+			}
+			group = NewItemGroup(name)
+			itemGroups.Groups = append(itemGroups.Groups, group)
+			continue
+		}
+		if group == nil {
+			return nil, fmt.Errorf("%s:%d: expected a \"[Group Name]\" line before %q", filePath, lineNumber, line)
+		}
+		group.Add(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read item groups file: %s", filePath)
+	}
+	return itemGroups, nil
+}
+
+func (g *ItemGroups) Group(name string) (*ItemGroup, bool) {
+	for _, group := range g.Groups {
+		if strings.EqualFold(group.Name, name) {
+			return group, true
+		}
+	}
+	return nil, false
+}
+
+// Across every group
+func (g *ItemGroups) ItemNames() []string {
+	return FlatMap(g.Groups, func(group *ItemGroup) []string {
+		return group.ItemNames()
+	})
+}
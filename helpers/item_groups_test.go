@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestItemGroupsMatchNamesAndPatterns(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "groups.txt")
+	content := "# Groups\n[Red Codestones]\nKew Codestone\nZed Codestone\n\n[Bottled Faeries]\n*Bottled*Faerie\n"
+	if err := os.WriteFile(filePath, []byte(content), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	itemGroups, err := NewItemGroups(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !slices.Equal(itemGroups.ItemNames(), []string{"Kew Codestone", "Zed Codestone"}) {
+		t.Fatalf("Expected only the red codestones to be item names, but got %q", itemGroups.ItemNames())
+	}
+
+	faeries, exists := itemGroups.Group("bottled faeries")
+	if !exists {
+		t.Fatalf("Expected a Bottled Faeries group")
+	}
+	cases := map[string]bool{
+		"Weak Bottled Fire Faerie":           true,
+		"Unidentifiable Weak Bottled Faerie": true,
+		"weak bottled dark faerie":           true,
+		"Battle Faerie Dagger":               false,
+		"Bottled Faerie Wings":               false,
+	}
+	for itemName, expected := range cases {
+		if actual := faeries.Contains(itemName); actual != expected {
+			t.Fatalf("Expected Contains(%q) to be %t", itemName, expected)
+		}
+	}
+}
+
+func TestItemGroupsRejectItemsOutsideAGroup(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "groups.txt")
+	if err := os.WriteFile(filePath, []byte("Kew Codestone\n[Red Codestones]\n"), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if _, err := NewItemGroups(filePath); err == nil {
+		t.Fatalf("Expected an item before any group header to be an error")
+	}
+}
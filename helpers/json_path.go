@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Resolves a small subset of JSONPath against a value decoded by encoding/json: a leading "$", dotted keys,
+// bracketed keys ($['key']) and array indices ($.items[0]). That's all a price endpoint ever needs.
+func JsonPathLookup(document any, path string) (any, error) {
+	tokens, err := jsonPathTokens(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := document
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]any:
+			value, exists := node[token]
+			if !exists {
+				return nil, fmt.Errorf("key %q does not exist at %q", token, path)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a valid array index at %q", token, path)
+			}
+			if index < 0 {
+				index += len(node)
+			}
+			if index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("index %s is out of range (length %d) at %q", token, len(node), path)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("cannot descend into %T with %q at %q", current, token, path)
+		}
+	}
+	return current, nil
+}
+
+func jsonPathTokens(path string) ([]string, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with \"$\"", path)
+	}
+
+	tokens := []string{}
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in JSONPath %q", path)
+			}
+			tokens = append(tokens, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated \"[\" in JSONPath %q", path)
+			}
+			tokens = append(tokens, strings.Trim(rest[1:end], `'"`))
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in JSONPath %q", rest[0], path)
+		}
+	}
+	return tokens, nil
+}
@@ -3,12 +3,31 @@ package helpers
 import (
 	"fmt"
 	"log/slog"
+	"math"
+	"math/rand/v2"
 	"time"
+
+	"github.com/palantir/stacktrace"
 )
 
 type RetryPolicy[TResult any] struct {
 	Backoff  func(int) int
 	MaxTries int
+	// The longest a server may ask us to wait before trying again; we give up rather than wait any longer
+	MaxBackoffMs int
+}
+
+// Exponential backoff with equal jitter, i.e. somewhere between half and all of the ceiling, in milliseconds
+func ExponentialBackoff(baseMs int, maxMs int) func(int) int {
+	return func(retryCount int) int {
+		ceiling := math.Min(float64(maxMs), float64(baseMs)*math.Pow(2, float64(retryCount-1)))
+		return int(ceiling/2) + rand.IntN(int(ceiling/2)+1)
+	}
+}
+
+func responseError(err error) (*ResponseError, bool) {
+	responseErr, ok := stacktrace.RootCause(err).(*ResponseError)
+	return responseErr, ok
 }
 
 func (rp RetryPolicy[T]) Execute(action func() (T, error), errorMsg string) (T, error) {
@@ -21,6 +40,22 @@ func (rp RetryPolicy[T]) Execute(action func() (T, error), errorMsg string) (T,
 		}
 
 		backoff := rp.Backoff(i)
+		if responseErr, ok := responseError(err); ok {
+			if !responseErr.Class.IsRetryable() {
+				slog.Error(fmt.Sprintf("Failed to execute %q; not retrying as the response was %s", errorMsg, responseErr.Class))
+				return outcome, err
+			}
+			if responseErr.RetryAfter > time.Duration(rp.MaxBackoffMs)*time.Millisecond {
+				slog.Error(fmt.Sprintf("Failed to execute %q; not retrying as the server asked us to wait %s", errorMsg, responseErr.RetryAfter))
+				return outcome, err
+			}
+			// Honour the server's wishes if it asked for longer than we were going to wait anyway
+			backoff = max(backoff, int(responseErr.RetryAfter.Milliseconds()))
+		}
+
+		if i == rp.MaxTries {
+			break
+		}
 		slog.Error(fmt.Sprintf("Failed to execute %q; retrying in %d ms...", errorMsg, backoff))
 		time.Sleep(time.Duration(backoff) * time.Millisecond)
 	}
@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/palantir/stacktrace"
+)
+
+// One outbound price request, as written to the scrape telemetry log
+type ScrapeRecord struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Url        string    `json:"url"`
+	Item       string    `json:"item"`
+	Source     string    `json:"source"`
+	UserAgent  string    `json:"userAgent"`
+	StatusCode int       `json:"status"`
+	LatencyMs  int64     `json:"latencyMs"`
+	Price      float64   `json:"price"`
+	ErrorClass string    `json:"errorClass,omitempty"`
+}
+
+func (r ScrapeRecord) IsFailure() bool {
+	return r.ErrorClass != ""
+}
+
+// Builds a record from whatever the request left behind; either res or err may be nil.
+func NewScrapeRecord(source string, item string, url string, start time.Time, res *http.Response, price float64, err error) ScrapeRecord {
+	record := ScrapeRecord{
+		Timestamp: start,
+		Url:       url,
+		Item:      item,
+		Source:    source,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Price:     price,
+	}
+	if res != nil {
+		record.UserAgent = RequestUserAgent(res)
+		record.StatusCode = res.StatusCode
+	}
+	if err == nil {
+		return record
+	}
+
+	responseErr, ok := responseError(err)
+	if !ok {
+		record.ErrorClass = "other"
+		return record
+	}
+	record.ErrorClass = responseErr.Class.String()
+	if record.UserAgent == "" {
+		record.UserAgent = responseErr.UserAgent
+	}
+	if record.StatusCode == 0 {
+		record.StatusCode = responseErr.StatusCode
+	}
+	return record
+}
+
+type ScrapeTelemetry struct {
+	mutex    sync.Mutex
+	filePath string
+}
+
+var (
+	scrapeTelemetryOnce     = &sync.Once{}
+	scrapeTelemetryInstance *ScrapeTelemetry
+)
+
+func ScrapeTelemetryInstance() *ScrapeTelemetry {
+	scrapeTelemetryOnce.Do(func() {
+		scrapeTelemetryInstance = NewScrapeTelemetry(constants.ScrapeTelemetryFilePath())
+	})
+	return scrapeTelemetryInstance
+}
+
+func NewScrapeTelemetry(filePath string) *ScrapeTelemetry {
+	return &ScrapeTelemetry{
+		filePath: filePath,
+	}
+}
+
+func (t *ScrapeTelemetry) Log(record ScrapeRecord) error {
+	serialised, err := json.Marshal(record)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to serialise scrape record for %q", record.Url)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	file, err := os.OpenFile(t.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0755)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to open scrape telemetry file: %s", t.filePath)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(serialised, '\n')); err != nil {
+		return stacktrace.Propagate(err, "failed to write scrape record to %s", t.filePath)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package helpers
+
+import "math"
+
+// The pth percentile of values, which must already be sorted in ascending order, interpolating between the two
+// nearest values. p is between 0 and 1, e.g. 0.25 for the 25th percentile.
+func Percentile(sortedValues []float64, p float64) float64 {
+	if len(sortedValues) == 0 {
+		panic("cannot calculate percentile of an empty slice")
+	}
+	if p < 0 || p > 1 {
+		panic("p must be between 0 and 1")
+	}
+
+	rank := p * float64(len(sortedValues)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sortedValues[lower]
+	}
+	weight := rank - float64(lower)
+	return sortedValues[lower]*(1-weight) + sortedValues[upper]*weight
+}
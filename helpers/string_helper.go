@@ -0,0 +1,45 @@
+package helpers
+
+import (
+	"slices"
+	"strings"
+)
+
+// Case-insensitive Levenshtein distance
+func EditDistance(a string, b string) int {
+	first := []rune(strings.ToLower(a))
+	second := []rune(strings.ToLower(b))
+
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+		for j := 1; j <= len(second); j++ {
+			substitutionCost := When(first[i-1] == second[j-1], 0, 1)
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+substitutionCost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(second)]
+}
+
+// Candidates within a third of the target's length in edits (and at most maxDistance), closest first
+func ClosestMatches(target string, candidates []string, maxDistance int) []string {
+	threshold := min(maxDistance, max(1, len(target)/3))
+	matches := Filter(candidates, func(candidate string) bool {
+		return candidate != target && EditDistance(target, candidate) <= threshold
+	})
+	slices.Sort(matches)
+	slices.SortStableFunc(matches, func(first string, second string) int {
+		return EditDistance(target, first) - EditDistance(target, second)
+	})
+	return matches
+}
+
+// Trims and collapses runs of whitespace into a single space
+func CollapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
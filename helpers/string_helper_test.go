@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClosestMatches(t *testing.T) {
+	if distance := EditDistance("Kitten", "sitting"); distance != 3 {
+		t.Fatalf("Expected an edit distance of 3, but got %d", distance)
+	}
+
+	matches := ClosestMatches("Halloween Koi Plushie", []string{"Halloween Draik Plushie", "Blue Walein", "Halloween Kau Plushie"}, 5)
+	if !slices.Equal(matches, []string{"Halloween Kau Plushie", "Halloween Draik Plushie"}) {
+		t.Fatalf("Expected the closest matches to be ordered by distance, but got %v", matches)
+	}
+}
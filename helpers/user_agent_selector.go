@@ -0,0 +1,182 @@
+package helpers
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/palantir/stacktrace"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type UserAgentRecord struct {
+	UserAgent string
+	Successes int
+	Failures  int
+}
+
+func (r *UserAgentRecord) Attempts() int {
+	return r.Successes + r.Failures
+}
+
+// Laplace-smoothed, so unseen agents start at 50%
+func (r *UserAgentRecord) SuccessRate() float64 {
+	return float64(r.Successes+1) / float64(r.Attempts()+2)
+}
+
+// Picks user agents by Thompson sampling over each agent's observed success rate, so agents that keep getting
+// blocked are tried less and less often without being written off entirely.
+type UserAgentSelector struct {
+	mutex    sync.Mutex
+	filePath string
+	records  map[string]*UserAgentRecord
+}
+
+var (
+	userAgentSelectorOnce     = &sync.Once{}
+	userAgentSelectorInstance *UserAgentSelector
+)
+
+func UserAgentSelectorInstance() *UserAgentSelector {
+	userAgentSelectorOnce.Do(func() {
+		userAgentSelectorInstance = NewUserAgentSelector(constants.UserAgentStatsFilePath())
+		if err := userAgentSelectorInstance.Load(); err != nil {
+			slog.Warn(fmt.Sprintf("Failed to load user agent statistics; starting afresh: %s", err))
+		}
+	})
+	return userAgentSelectorInstance
+}
+
+func NewUserAgentSelector(filePath string) *UserAgentSelector {
+	selector := &UserAgentSelector{
+		filePath: filePath,
+		records:  map[string]*UserAgentRecord{},
+	}
+	for _, entry := range USER_AGENTS {
+		userAgent := strings.Split(entry, "|")[1]
+		selector.records[userAgent] = &UserAgentRecord{UserAgent: userAgent}
+	}
+	return selector
+}
+
+func (s *UserAgentSelector) Select() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	bestUserAgent := ""
+	bestSample := -1.0
+	for userAgent, record := range s.records {
+		sample := distuv.Beta{
+			Alpha: float64(record.Successes + 1),
+			Beta:  float64(record.Failures + 1),
+		}.Rand()
+		if sample > bestSample {
+			bestSample = sample
+			bestUserAgent = userAgent
+		}
+	}
+	return bestUserAgent
+}
+
+func (s *UserAgentSelector) Record(userAgent string, class ResponseClass) {
+	if userAgent == "" {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, exists := s.records[userAgent]
+	if !exists {
+		record = &UserAgentRecord{UserAgent: userAgent}
+		s.records[userAgent] = record
+	}
+
+	switch {
+	case class == ResponseOk:
+		record.Successes++
+	case class.IsUserAgentFault() || class == ResponseNetworkError:
+		record.Failures++
+	default:
+		// Not found/markup changes aren't the user agent's fault
+	}
+}
+
+// Records ordered from most to least successful
+func (s *UserAgentSelector) Records() []UserAgentRecord {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records := Map(Values(s.records), func(record *UserAgentRecord) UserAgentRecord {
+		return *record
+	})
+	return OrderByDescending(records, func(record UserAgentRecord) float64 {
+		return record.SuccessRate()
+	})
+}
+
+func (s *UserAgentSelector) Load() error {
+	if !IsFileExists(s.filePath) {
+		return nil
+	}
+
+	file, err := os.Open(s.filePath)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to open user agent statistics file: %s", s.filePath)
+	}
+	defer file.Close()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		tokens := strings.Split(scanner.Text(), "|")
+		if len(tokens) != 3 {
+			continue
+		}
+		successes, err := strconv.Atoi(tokens[1])
+		if err != nil {
+			continue
+		}
+		failures, err := strconv.Atoi(tokens[2])
+		if err != nil {
+			continue
+		}
+		userAgent := tokens[0]
+		if _, exists := s.records[userAgent]; !exists {
+			// Agent has since been removed from the list
+			continue
+		}
+		s.records[userAgent] = &UserAgentRecord{
+			UserAgent: userAgent,
+			Successes: successes,
+			Failures:  failures,
+		}
+	}
+	return nil
+}
+
+func (s *UserAgentSelector) Save() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.OpenFile(s.filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to open user agent statistics file: %s", s.filePath)
+	}
+	defer file.Close()
+
+	for _, record := range s.records {
+		if record.Attempts() == 0 {
+			continue
+		}
+		file.WriteString(fmt.Sprintf("%s|%d|%d\n", record.UserAgent, record.Successes, record.Failures))
+	}
+	return nil
+}
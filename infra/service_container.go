@@ -2,10 +2,12 @@ package infra
 
 import (
 	"reflect"
+	"runtime"
 	"sync"
 
 	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/loggers"
 	"github.com/darienchong/neopets-battledome-analysis/parsers"
 	"github.com/darienchong/neopets-battledome-analysis/services"
@@ -18,20 +20,55 @@ type ServiceContainer struct {
 
 	ItemPriceCache caches.ItemPriceCache
 
-	BattledomeItemsLogger *loggers.BattledomeItemsLogger
-	DataComparisonLogger  *loggers.DataComparisonLogger
+	BattledomeItemsLogger     *loggers.BattledomeItemsLogger
+	DataComparisonLogger      *loggers.DataComparisonLogger
+	ScrapeTelemetryLogger     *loggers.ScrapeTelemetryLogger
+	DropDataLintLogger        *loggers.DropDataLintLogger
+	DropDataImportLogger      *loggers.DropDataImportLogger
+	DropDataExchangeLogger    *loggers.DropDataExchangeLogger
+	GeneratedDropsCacheLogger *loggers.GeneratedDropsCacheLogger
+	ProfitRiskLogger          *loggers.ProfitRiskLogger
+	IntervalCoverageLogger    *loggers.IntervalCoverageLogger
+	DryStreakLogger           *loggers.DryStreakLogger
+	GoalLogger                *loggers.GoalLogger
+	RecommendationLogger      *loggers.RecommendationLogger
+	WhatIfLogger              *loggers.WhatIfLogger
 
-	BattledomeItemDropDataParser  *parsers.BattledomeItemDropDataParser
-	BattledomeItemWeightParser    *parsers.BattledomeItemWeightParser
-	GeneratedBattledomeItemParser *parsers.GeneratedBattledomeItemParser
+	BattledomeItemDropDataParser       *parsers.BattledomeItemDropDataParser
+	StrictBattledomeItemDropDataParser *parsers.BattledomeItemDropDataParser
+	PrizeLogHtmlParser                 *parsers.PrizeLogHtmlParser
+	DropRecordJsonParser               *parsers.DropRecordJsonParser
+	DropRecordCsvParser                *parsers.DropRecordCsvParser
+	BattledomeItemWeightParser         *parsers.BattledomeItemWeightParser
+	GeneratedBattledomeItemParser      *parsers.GeneratedBattledomeItemParser
+	ScrapeTelemetryParser              *parsers.ScrapeTelemetryParser
+	WishlistParser                     *parsers.WishlistParser
 
 	BattledomeItemGenerationService *services.BattledomeItemGenerationService
 	BattledomeItemWeightService     *services.BattledomeItemWeightService
 	BattledomeItemsService          *services.BattledomeItemsService
 	DataComparisonService           *services.DataComparisonService
 	StatisticsService               *services.StatisticsService
+	BootstrapService                *services.BootstrapService
+	ScrapeTelemetryService          *services.ScrapeTelemetryService
+	DropDataLintService             *services.DropDataLintService
+	DropDataImportService           *services.DropDataImportService
+	DropDataExchangeService         *services.DropDataExchangeService
+	DropDataStore                   *services.DropDataStore
+	GeneratedDropsCacheService      *services.GeneratedDropsCacheService
+	ProfitRiskService               *services.ProfitRiskService
+	DryStreakService                *services.DryStreakService
+	GoalService                     *services.GoalService
+	RecommendationService           *services.RecommendationService
+	WhatIfService                   *services.WhatIfService
 
-	DataComparisonViewer *viewers.DataComparisonViewer
+	DataComparisonViewer   *viewers.DataComparisonViewer
+	ProfitRiskViewer       *viewers.ProfitRiskViewer
+	IntervalCoverageViewer *viewers.IntervalCoverageViewer
+	DryStreakViewer        *viewers.DryStreakViewer
+	GoalViewer             *viewers.GoalViewer
+	RecommendationViewer   *viewers.RecommendationViewer
+	WhatIfViewer           *viewers.WhatIfViewer
 }
 
 var (
@@ -54,25 +91,90 @@ func (sc *ServiceContainer) GetBattledomeItemsService() *services.BattledomeItem
 	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.BattledomeItemsService{}), &sync.Once{})
 	once.(*sync.Once).Do(func() {
 		sc.BattledomeItemsService = services.NewBattledomeItemsService(
+			sc.GetGeneratedDropsCacheService(),
+			sc.GetDropDataStore(),
+		)
+	})
+	return sc.BattledomeItemsService
+}
+
+func (sc *ServiceContainer) GetGeneratedDropsCacheService() *services.GeneratedDropsCacheService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.GeneratedDropsCacheService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.GeneratedDropsCacheService = services.NewGeneratedDropsCacheService(
 			sc.GetBattledomeItemGenerationService(),
 			sc.GetGeneratedBattledomeItemParser(),
+			sc.GetBattledomeItemWeightService(),
+			constants.CombineRelativeFolderAndFilename(constants.DataFolder, ""),
+			constants.GeneratedDropsSeed,
+			constants.NumberOfItemsToGenerate,
+		)
+	})
+	return sc.GeneratedDropsCacheService
+}
+
+func (sc *ServiceContainer) GetGeneratedDropsCacheLogger() *loggers.GeneratedDropsCacheLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.GeneratedDropsCacheLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.GeneratedDropsCacheLogger = loggers.NewGeneratedDropsCacheLogger(
+			sc.GetGeneratedDropsCacheService(),
+		)
+	})
+	return sc.GeneratedDropsCacheLogger
+}
+
+func (sc *ServiceContainer) GetProfitRiskLogger() *loggers.ProfitRiskLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.ProfitRiskLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.ProfitRiskLogger = loggers.NewProfitRiskLogger(
+			sc.GetProfitRiskService(),
+			sc.GetProfitRiskViewer(),
+		)
+	})
+	return sc.ProfitRiskLogger
+}
+
+func (sc *ServiceContainer) GetProfitRiskService() *services.ProfitRiskService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.ProfitRiskService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.ProfitRiskService = services.NewProfitRiskService(
+			sc.GetDataComparisonService(),
+			sc.GetBootstrapService(),
+		)
+	})
+	return sc.ProfitRiskService
+}
+
+func (sc *ServiceContainer) GetProfitRiskViewer() *viewers.ProfitRiskViewer {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(viewers.ProfitRiskViewer{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.ProfitRiskViewer = viewers.NewProfitRiskViewer()
+	})
+	return sc.ProfitRiskViewer
+}
+
+func (sc *ServiceContainer) GetDropDataStore() *services.DropDataStore {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.DropDataStore{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropDataStore = services.NewDropDataStore(
 			sc.GetBattledomeItemDropDataParser(),
+			constants.DropDataFilePath(""),
+			constants.DropDataIndexFilePath(),
 		)
 	})
-	return sc.BattledomeItemsService
+	return sc.DropDataStore
 }
 
 func (sc *ServiceContainer) GetItemPriceCache() caches.ItemPriceCache {
 	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(caches.RealItemPriceCache{}), &sync.Once{})
 	once.(*sync.Once).Do(func() {
-		var dataSource caches.ItemPriceDataSource
-		switch constants.ItemPriceDataSource {
-		case constants.Unknown:
-			panic(stacktrace.NewError("the data source for the item price cache wasn't specified"))
-		case constants.JellyNeo:
-			dataSource = caches.NewJellyNeoDataSource()
-		case constants.ItemDB:
-			dataSource = caches.NewItemDBDataSource()
+		dataSourceName, err := caches.LoadPriceSources(constants.PriceSourcesFilePath())
+		if err != nil {
+			panic(stacktrace.Propagate(err, "failed to load price sources"))
+		}
+		dataSource, err := caches.PriceSourceByName(dataSourceName)
+		if err != nil {
+			panic(stacktrace.Propagate(err, "failed to get the data source for the item price cache"))
 		}
 		cache, err := caches.ItemPriceCacheInstance(dataSource)
 		if err != nil {
@@ -105,14 +207,87 @@ func (sc *ServiceContainer) GetDataComparisonLogger() *loggers.DataComparisonLog
 	return sc.DataComparisonLogger
 }
 
+func (sc *ServiceContainer) GetScrapeTelemetryLogger() *loggers.ScrapeTelemetryLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.ScrapeTelemetryLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.ScrapeTelemetryLogger = loggers.NewScrapeTelemetryLogger(
+			sc.GetScrapeTelemetryService(),
+		)
+	})
+	return sc.ScrapeTelemetryLogger
+}
+
+func (sc *ServiceContainer) GetDropDataLintLogger() *loggers.DropDataLintLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.DropDataLintLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropDataLintLogger = loggers.NewDropDataLintLogger(
+			sc.GetDropDataLintService(),
+		)
+	})
+	return sc.DropDataLintLogger
+}
+
+func (sc *ServiceContainer) GetDropDataImportLogger() *loggers.DropDataImportLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.DropDataImportLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropDataImportLogger = loggers.NewDropDataImportLogger(
+			sc.GetDropDataImportService(),
+		)
+	})
+	return sc.DropDataImportLogger
+}
+
+func (sc *ServiceContainer) GetDropDataExchangeLogger() *loggers.DropDataExchangeLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.DropDataExchangeLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropDataExchangeLogger = loggers.NewDropDataExchangeLogger(
+			sc.GetDropDataExchangeService(),
+		)
+	})
+	return sc.DropDataExchangeLogger
+}
+
 func (sc *ServiceContainer) GetBattledomeItemDropDataParser() *parsers.BattledomeItemDropDataParser {
 	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(parsers.BattledomeItemDropDataParser{}), &sync.Once{})
 	once.(*sync.Once).Do(func() {
-		sc.BattledomeItemDropDataParser = parsers.NewBattledomeItemDropDataParser()
+		sc.BattledomeItemDropDataParser = parsers.NewBattledomeItemDropDataParser(helpers.BattledomeCatalogueInstance())
 	})
 	return sc.BattledomeItemDropDataParser
 }
 
+func (sc *ServiceContainer) GetStrictBattledomeItemDropDataParser() *parsers.BattledomeItemDropDataParser {
+	// Keyed by name since it shares a type with the lenient parser
+	once, _ := sc.onces.LoadOrStore("StrictBattledomeItemDropDataParser", &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.StrictBattledomeItemDropDataParser = parsers.NewStrictBattledomeItemDropDataParser(helpers.BattledomeCatalogueInstance())
+	})
+	return sc.StrictBattledomeItemDropDataParser
+}
+
+func (sc *ServiceContainer) GetPrizeLogHtmlParser() *parsers.PrizeLogHtmlParser {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(parsers.PrizeLogHtmlParser{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.PrizeLogHtmlParser = parsers.NewPrizeLogHtmlParser()
+	})
+	return sc.PrizeLogHtmlParser
+}
+
+func (sc *ServiceContainer) GetDropRecordJsonParser() *parsers.DropRecordJsonParser {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(parsers.DropRecordJsonParser{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropRecordJsonParser = parsers.NewDropRecordJsonParser()
+	})
+	return sc.DropRecordJsonParser
+}
+
+func (sc *ServiceContainer) GetDropRecordCsvParser() *parsers.DropRecordCsvParser {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(parsers.DropRecordCsvParser{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropRecordCsvParser = parsers.NewDropRecordCsvParser()
+	})
+	return sc.DropRecordCsvParser
+}
+
 func (sc *ServiceContainer) GetBattledomeItemWeightParser() *parsers.BattledomeItemWeightParser {
 	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(parsers.BattledomeItemWeightParser{}), &sync.Once{})
 	once.(*sync.Once).Do(func() {
@@ -129,6 +304,14 @@ func (sc *ServiceContainer) GetGeneratedBattledomeItemParser() *parsers.Generate
 	return sc.GeneratedBattledomeItemParser
 }
 
+func (sc *ServiceContainer) GetScrapeTelemetryParser() *parsers.ScrapeTelemetryParser {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(parsers.ScrapeTelemetryParser{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.ScrapeTelemetryParser = parsers.NewScrapeTelemetryParser()
+	})
+	return sc.ScrapeTelemetryParser
+}
+
 func (sc *ServiceContainer) GetBattledomeItemGenerationService() *services.BattledomeItemGenerationService {
 	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.BattledomeItemGenerationService{}), &sync.Once{})
 	once.(*sync.Once).Do(func() {
@@ -162,11 +345,71 @@ func (sc *ServiceContainer) GetDataComparisonService() *services.DataComparisonS
 func (sc *ServiceContainer) GetStatisticsService() *services.StatisticsService {
 	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.StatisticsService{}), &sync.Once{})
 	once.(*sync.Once).Do(func() {
-		sc.StatisticsService = services.NewStatisticsService()
+		sc.StatisticsService = services.NewStatisticsService(services.BinomialIntervalMethod(constants.BinomialIntervalMethod))
 	})
 	return sc.StatisticsService
 }
 
+func (sc *ServiceContainer) GetBootstrapService() *services.BootstrapService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.BootstrapService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.BootstrapService = services.NewBootstrapService(
+			services.BootstrapMethod(constants.BootstrapMethod),
+			constants.NumberOfBootstrapSamples,
+			runtime.NumCPU(),
+			constants.BootstrapSeed,
+		)
+	})
+	return sc.BootstrapService
+}
+
+func (sc *ServiceContainer) GetScrapeTelemetryService() *services.ScrapeTelemetryService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.ScrapeTelemetryService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.ScrapeTelemetryService = services.NewScrapeTelemetryService(
+			sc.GetScrapeTelemetryParser(),
+		)
+	})
+	return sc.ScrapeTelemetryService
+}
+
+func (sc *ServiceContainer) GetDropDataLintService() *services.DropDataLintService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.DropDataLintService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropDataLintService = services.NewDropDataLintService(
+			sc.GetStrictBattledomeItemDropDataParser(),
+			sc.GetBattledomeItemWeightParser(),
+			helpers.ItemAliasesInstance(),
+			helpers.BattledomeCatalogueInstance(),
+		)
+	})
+	return sc.DropDataLintService
+}
+
+func (sc *ServiceContainer) GetDropDataImportService() *services.DropDataImportService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.DropDataImportService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropDataImportService = services.NewDropDataImportService(
+			sc.GetPrizeLogHtmlParser(),
+			sc.GetBattledomeItemDropDataParser(),
+			sc.GetStrictBattledomeItemDropDataParser(),
+		)
+	})
+	return sc.DropDataImportService
+}
+
+func (sc *ServiceContainer) GetDropDataExchangeService() *services.DropDataExchangeService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.DropDataExchangeService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DropDataExchangeService = services.NewDropDataExchangeService(
+			sc.GetDropRecordJsonParser(),
+			sc.GetDropRecordCsvParser(),
+			sc.GetStrictBattledomeItemDropDataParser(),
+		)
+	})
+	return sc.DropDataExchangeService
+}
+
 func (sc *ServiceContainer) GetDataComparisonViewer() *viewers.DataComparisonViewer {
 	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(viewers.DataComparisonViewer{}), &sync.Once{})
 	once.(*sync.Once).Do(func() {
@@ -174,7 +417,164 @@ func (sc *ServiceContainer) GetDataComparisonViewer() *viewers.DataComparisonVie
 			sc.GetBattledomeItemsService(),
 			sc.GetDataComparisonService(),
 			sc.GetStatisticsService(),
+			sc.GetBootstrapService(),
 		)
 	})
 	return sc.DataComparisonViewer
 }
+
+func (sc *ServiceContainer) GetIntervalCoverageLogger() *loggers.IntervalCoverageLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.IntervalCoverageLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.IntervalCoverageLogger = loggers.NewIntervalCoverageLogger(
+			sc.GetStatisticsService(),
+			sc.GetIntervalCoverageViewer(),
+		)
+	})
+	return sc.IntervalCoverageLogger
+}
+
+func (sc *ServiceContainer) GetIntervalCoverageViewer() *viewers.IntervalCoverageViewer {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(viewers.IntervalCoverageViewer{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.IntervalCoverageViewer = viewers.NewIntervalCoverageViewer()
+	})
+	return sc.IntervalCoverageViewer
+}
+
+func (sc *ServiceContainer) GetDryStreakLogger() *loggers.DryStreakLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.DryStreakLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DryStreakLogger = loggers.NewDryStreakLogger(
+			sc.GetDryStreakService(),
+			sc.GetDryStreakViewer(),
+		)
+	})
+	return sc.DryStreakLogger
+}
+
+func (sc *ServiceContainer) GetDryStreakService() *services.DryStreakService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.DryStreakService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DryStreakService = services.NewDryStreakService(
+			sc.GetBattledomeItemsService(),
+			sc.GetBattledomeItemWeightService(),
+			helpers.ItemGroupsInstance(),
+		)
+	})
+	return sc.DryStreakService
+}
+
+func (sc *ServiceContainer) GetDryStreakViewer() *viewers.DryStreakViewer {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(viewers.DryStreakViewer{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.DryStreakViewer = viewers.NewDryStreakViewer()
+	})
+	return sc.DryStreakViewer
+}
+
+func (sc *ServiceContainer) GetWishlistParser() *parsers.WishlistParser {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(parsers.WishlistParser{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.WishlistParser = parsers.NewWishlistParser()
+	})
+	return sc.WishlistParser
+}
+
+func (sc *ServiceContainer) GetGoalLogger() *loggers.GoalLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.GoalLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.GoalLogger = loggers.NewGoalLogger(
+			sc.GetWishlistParser(),
+			sc.GetGoalService(),
+			sc.GetGoalViewer(),
+		)
+	})
+	return sc.GoalLogger
+}
+
+func (sc *ServiceContainer) GetGoalService() *services.GoalService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.GoalService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.GoalService = services.NewGoalService(
+			sc.GetBattledomeItemWeightService(),
+			sc.GetBattledomeItemsService(),
+			helpers.ItemGroupsInstance(),
+			constants.NumberOfGoalSimulations,
+			constants.MaxGoalDays,
+			constants.GoalMinimumSamples,
+			constants.GoalSeed,
+		)
+	})
+	return sc.GoalService
+}
+
+func (sc *ServiceContainer) GetGoalViewer() *viewers.GoalViewer {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(viewers.GoalViewer{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.GoalViewer = viewers.NewGoalViewer()
+	})
+	return sc.GoalViewer
+}
+
+func (sc *ServiceContainer) GetRecommendationLogger() *loggers.RecommendationLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.RecommendationLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.RecommendationLogger = loggers.NewRecommendationLogger(
+			sc.GetRecommendationService(),
+			sc.GetRecommendationViewer(),
+		)
+	})
+	return sc.RecommendationLogger
+}
+
+func (sc *ServiceContainer) GetRecommendationService() *services.RecommendationService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.RecommendationService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.RecommendationService = services.NewRecommendationService(
+			sc.GetDataComparisonService(),
+			sc.GetBootstrapService(),
+			helpers.BattledomeCatalogueInstance(),
+		)
+	})
+	return sc.RecommendationService
+}
+
+func (sc *ServiceContainer) GetRecommendationViewer() *viewers.RecommendationViewer {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(viewers.RecommendationViewer{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.RecommendationViewer = viewers.NewRecommendationViewer()
+	})
+	return sc.RecommendationViewer
+}
+
+func (sc *ServiceContainer) GetWhatIfLogger() *loggers.WhatIfLogger {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(loggers.WhatIfLogger{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.WhatIfLogger = loggers.NewWhatIfLogger(
+			sc.GetWhatIfService(),
+			sc.GetWhatIfViewer(),
+		)
+	})
+	return sc.WhatIfLogger
+}
+
+func (sc *ServiceContainer) GetWhatIfService() *services.WhatIfService {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(services.WhatIfService{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.WhatIfService = services.NewWhatIfService(
+			sc.GetDataComparisonService(),
+			sc.GetDataComparisonService(),
+			helpers.BattledomeCatalogueInstance(),
+		)
+	})
+	return sc.WhatIfService
+}
+
+func (sc *ServiceContainer) GetWhatIfViewer() *viewers.WhatIfViewer {
+	once, _ := sc.onces.LoadOrStore(reflect.TypeOf(viewers.WhatIfViewer{}), &sync.Once{})
+	once.(*sync.Once).Do(func() {
+		sc.WhatIfViewer = viewers.NewWhatIfViewer()
+	})
+	return sc.WhatIfViewer
+}
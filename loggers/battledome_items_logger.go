@@ -5,13 +5,14 @@ import (
 	"log/slog"
 	"math"
 	"strconv"
+	"strings"
 
-	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
 	"github.com/darienchong/neopets-battledome-analysis/parsers"
 	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
 	"github.com/palantir/stacktrace"
 )
 
@@ -27,7 +28,9 @@ func NewBattledomeItemsLogger(battledomeItemsService *services.BattledomeItemsSe
 	}
 }
 
-func (l *BattledomeItemsLogger) Log(itemPriceCache caches.ItemPriceCache, dataFolderPath string, numDropsToPrint int) error {
+func (l *BattledomeItemsLogger) Log(valuation valuations.ItemValuation, dataFolderPath string, numDropsToPrint int) error {
+	logValuation(valuation)
+
 	if numDropsToPrint <= 0 {
 		numDropsToPrint = constants.NumberOfDropsToPrint
 	}
@@ -41,88 +44,114 @@ func (l *BattledomeItemsLogger) Log(itemPriceCache caches.ItemPriceCache, dataFo
 		return stacktrace.Propagate(err, "failed to get files in %s", dataFolderPath)
 	}
 
-	if numDropsToPrint > 0 {
+	contributorFilter := l.BattledomeItemsService.ContributorFilter
+	if contributorFilter == "" && numDropsToPrint > 0 {
+		// Only the most recent files are needed, so don't bother parsing the rest
 		files = files[int(math.Max(float64(len(files)-numDropsToPrint), 0)):]
 	}
 
-	samplesByArena := map[models.Arena]models.BattledomeItems{}
+	dtos := []*models.BattledomeItemsDto{}
 	for _, file := range files {
 		items, err := l.BattledomeItemDropDataParser.Parse(constants.DropDataFilePath(file))
 		if err != nil {
 			return stacktrace.Propagate(err, "failed to parse drop data file: %s", file)
 		}
-
-		_, isKeyExists := samplesByArena[items.Metadata.Arena]
-		if !isKeyExists {
-			samplesByArena[items.Metadata.Arena] = models.BattledomeItems{}
+		if contributorFilter != "" && !strings.EqualFold(models.ContributorName(items.Metadata.Contributor), contributorFilter) {
+			continue
 		}
-		samplesByArena[items.Metadata.Arena] = append(samplesByArena[items.Metadata.Arena], items.Items...)
+		dtos = append(dtos, items)
+	}
+	if numDropsToPrint > 0 {
+		dtos = dtos[int(math.Max(float64(len(dtos)-numDropsToPrint), 0)):]
+	}
 
-		if constants.FilterArena != "" && constants.FilterArena != items.Metadata.Arena {
-			continue
+	samplesByArena := map[models.Arena]models.BattledomeItems{}
+	for _, items := range dtos {
+		source := items.Metadata.Source
+		if items.Metadata.Contributor != "" {
+			source = fmt.Sprintf("%s (%s)", source, items.Metadata.Contributor)
 		}
+		for _, battle := range items.Battles {
+			if err := l.logBattle(valuation, source, battle, samplesByArena); err != nil {
+				return stacktrace.Propagate(err, "failed to log battle %s in %s", battle, items.Metadata.Source)
+			}
+		}
+	}
 
-		itemCount := 0
-		profitBreakdownTable := helpers.NewTable([]string{
-			"i",
-			"Item Name",
-			"Qty",
-			"Price",
-			"Profit",
-			"%-age",
-		})
-		profitBreakdownTable.IsLastRowDistinct = true
+	return nil
+}
 
-		normalisedItems, err := items.Items.Normalise()
-		if err != nil {
-			return helpers.PropagateWithSerialisedValue(err, "failed to normalise items: %s", "failed to normalise items; another error occurred while trying to serialise the input: %s", items)
-		}
+func (l *BattledomeItemsLogger) logBattle(valuation valuations.ItemValuation, source string, battle *models.Battle, samplesByArena map[models.Arena]models.BattledomeItems) error {
+	_, isKeyExists := samplesByArena[battle.Metadata.Arena]
+	if !isKeyExists {
+		samplesByArena[battle.Metadata.Arena] = models.BattledomeItems{}
+	}
+	samplesByArena[battle.Metadata.Arena] = append(samplesByArena[battle.Metadata.Arena], battle.Items...)
 
-		orderedNormalisedItems, err := normalisedItems.ItemsOrderedByProfit(itemPriceCache)
-		if err != nil {
-			return helpers.PropagateWithSerialisedValue(err, "failed to get items ordered by profit: %s", "failed to get items ordered by profit; another error occurred while trying to serialise the input: %s", normalisedItems)
-		}
+	if constants.FilterArena != "" && constants.FilterArena != battle.Metadata.Arena {
+		return nil
+	}
 
-		for i, item := range orderedNormalisedItems {
-			itemCount += int(item.Quantity)
-			itemProfit := item.Profit(itemPriceCache)
-			itemPercentageProfit, err := item.PercentageProfit(itemPriceCache, normalisedItems)
-			if err != nil {
-				return helpers.PropagateWithSerialisedValue(err, "failed to get percentage profit: %s", "failed to get percentage profit; another error occurred while trying to serialise the input: %s", items)
-			}
-			if itemPercentageProfit < 0.01 {
-				continue
-			}
-			profitBreakdownTable.AddRow([]string{
-				strconv.Itoa(i + 1),
-				string(item.Name),
-				strconv.Itoa(int(item.Quantity)),
-				helpers.FormatFloat(itemPriceCache.Price(string(item.Name))) + " NP",
-				helpers.FormatFloat(itemProfit) + " NP",
-				helpers.FormatPercentage(itemPercentageProfit) + "%",
-			})
-		}
+	itemCount := 0
+	profitBreakdownTable := helpers.NewTable([]string{
+		"i",
+		"Item Name",
+		"Qty",
+		"Value",
+		"Profit",
+		"%-age",
+	})
+	profitBreakdownTable.IsLastRowDistinct = true
+
+	normalisedItems, err := battle.Items.Normalise()
+	if err != nil {
+		return helpers.PropagateWithSerialisedValue(err, "failed to normalise items: %s", "failed to normalise items; another error occurred while trying to serialise the input: %s", battle)
+	}
 
-		totalProfit, err := normalisedItems.TotalProfit(itemPriceCache)
+	orderedNormalisedItems, err := normalisedItems.ItemsOrderedByProfit(valuation)
+	if err != nil {
+		return helpers.PropagateWithSerialisedValue(err, "failed to get items ordered by profit: %s", "failed to get items ordered by profit; another error occurred while trying to serialise the input: %s", normalisedItems)
+	}
+
+	for i, item := range orderedNormalisedItems {
+		itemCount += int(item.Quantity)
+		itemProfit := item.Profit(valuation)
+		itemPercentageProfit, err := item.PercentageProfit(valuation, normalisedItems)
 		if err != nil {
-			return helpers.PropagateWithSerialisedValue(err, "failed to get total profit: %s", "failed to get total profit; an error occurred while trying to serialise the input to log: %s", normalisedItems)
+			return helpers.PropagateWithSerialisedValue(err, "failed to get percentage profit: %s", "failed to get percentage profit; another error occurred while trying to serialise the input: %s", battle)
+		}
+		if itemPercentageProfit < 0.01 {
+			continue
 		}
-
 		profitBreakdownTable.AddRow([]string{
-			"",
-			"Total",
-			helpers.FormatFloat(float64(itemCount)),
-			"",
-			helpers.FormatFloat(totalProfit) + " NP",
-			"",
+			strconv.Itoa(i + 1),
+			string(item.Name),
+			strconv.Itoa(int(item.Quantity)),
+			helpers.FormatFloat(valuation.Value(string(item.Name))) + " NP",
+			helpers.FormatFloat(itemProfit) + " NP",
+			helpers.FormatPercentage(itemPercentageProfit) + "%",
 		})
+	}
 
-		slog.Info(items.Metadata.String())
-		for _, line := range profitBreakdownTable.Lines() {
-			slog.Info("\t" + line)
-		}
-		slog.Info("")
+	totalProfit, err := normalisedItems.TotalProfit(valuation)
+	if err != nil {
+		return helpers.PropagateWithSerialisedValue(err, "failed to get total profit: %s", "failed to get total profit; an error occurred while trying to serialise the input to log: %s", normalisedItems)
+	}
+
+	profitBreakdownTable.AddRow([]string{
+		"",
+		"Total",
+		helpers.FormatFloat(float64(itemCount)),
+		"",
+		helpers.FormatFloat(totalProfit) + " NP",
+		"",
+	})
+
+	slog.Info(fmt.Sprintf("%s - %s", source, battle))
+	for _, line := range profitBreakdownTable.Lines() {
+		slog.Info("\t" + line)
 	}
+	slog.Info("")
 
 	return nil
 }
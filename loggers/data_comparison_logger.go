@@ -5,11 +5,11 @@ import (
 	"log/slog"
 	"strings"
 
-	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
 	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
 	"github.com/darienchong/neopets-battledome-analysis/viewers"
 	"github.com/palantir/stacktrace"
 )
@@ -18,6 +18,11 @@ func prefix(indentLevel int) string {
 	return strings.Repeat("  ", indentLevel)
 }
 
+// Heads the output, since every profit figure depends on it
+func logValuation(valuation valuations.ItemValuation) {
+	slog.Info(fmt.Sprintf("Items valued at %s", valuation.Description()))
+}
+
 type DataComparisonLogger struct {
 	DataComparisonService *services.DataComparisonService
 	DataComparisonViewer  *viewers.DataComparisonViewer
@@ -30,11 +35,13 @@ func NewDataComparisonLogger(dataComparisonService *services.DataComparisonServi
 	}
 }
 
-func (l *DataComparisonLogger) BriefCompareAllArenas(itemPriceCache caches.ItemPriceCache) error {
+func (l *DataComparisonLogger) BriefCompareAllArenas(valuation valuations.ItemValuation) error {
+	logValuation(valuation)
+
 	realData := map[models.Arena]models.NormalisedBattledomeItems{}
 	generatedData := map[models.Arena]models.NormalisedBattledomeItems{}
 
-	for _, arenaString := range constants.Arenas {
+	for _, arenaString := range helpers.BattledomeCatalogueInstance().ArenaNames() {
 		arena := models.Arena(arenaString)
 		realArenaData, generatedArenaData, err := l.DataComparisonService.CompareArena(arena)
 		if err != nil {
@@ -44,7 +51,7 @@ func (l *DataComparisonLogger) BriefCompareAllArenas(itemPriceCache caches.ItemP
 		generatedData[arena] = generatedArenaData
 	}
 
-	lines, err := l.DataComparisonViewer.ViewBriefArenaComparisons(itemPriceCache, realData, generatedData)
+	lines, err := l.DataComparisonViewer.ViewBriefArenaComparisons(valuation, realData, generatedData)
 	if err != nil {
 		return stacktrace.Propagate(err, "failed to generate brief arena comparisons")
 	}
@@ -56,10 +63,12 @@ func (l *DataComparisonLogger) BriefCompareAllArenas(itemPriceCache caches.ItemP
 	return nil
 }
 
-func (l *DataComparisonLogger) CompareAllArenas(itemPriceCache caches.ItemPriceCache) error {
+func (l *DataComparisonLogger) CompareAllArenas(valuation valuations.ItemValuation) error {
+	logValuation(valuation)
+
 	comparisonData := helpers.OrderByDescending(
 		helpers.Map(
-			constants.Arenas,
+			helpers.BattledomeCatalogueInstance().ArenaNames(),
 			func(arena string) *helpers.Tuple {
 				realData, generatedData, err := l.DataComparisonService.CompareArena(models.Arena(arena))
 				if err != nil {
@@ -74,9 +83,9 @@ func (l *DataComparisonLogger) CompareAllArenas(itemPriceCache caches.ItemPriceC
 			var profit float64 = 0.0
 			var err error
 			if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-				profit, err = realData.ArenaMeanDropsProfit(itemPriceCache, generatedData)
+				profit, err = realData.ArenaMeanDropsProfit(valuation, generatedData)
 			} else {
-				profit, err = realData.MeanDropsProfit(itemPriceCache)
+				profit, err = realData.MeanDropsProfit(valuation)
 			}
 			if err != nil {
 				return 0
@@ -89,7 +98,7 @@ func (l *DataComparisonLogger) CompareAllArenas(itemPriceCache caches.ItemPriceC
 		arena := comparisonDatum.Elements[0].(models.Arena)
 		realData := comparisonDatum.Elements[1].(models.NormalisedBattledomeItems)
 		generatedData := comparisonDatum.Elements[2].(models.NormalisedBattledomeItems)
-		lines, err := l.DataComparisonViewer.ViewArenaComparison(itemPriceCache, realData, generatedData)
+		lines, err := l.DataComparisonViewer.ViewArenaComparison(valuation, realData, generatedData)
 		if err != nil {
 			return stacktrace.Propagate(err, "failed to get arena comparison for %q", arena)
 		}
@@ -104,12 +113,14 @@ func (l *DataComparisonLogger) CompareAllArenas(itemPriceCache caches.ItemPriceC
 	return nil
 }
 
-func (l *DataComparisonLogger) CompareChallenger(itemPriceCache caches.ItemPriceCache, metadata models.BattledomeItemMetadata) error {
+func (l *DataComparisonLogger) CompareChallenger(valuation valuations.ItemValuation, metadata models.BattledomeItemMetadata) error {
+	logValuation(valuation)
+
 	realData, generatedData, err := l.DataComparisonService.CompareByMetadata(metadata)
 	if err != nil {
 		return stacktrace.Propagate(err, "failed to generate metadata comparison for %q", metadata)
 	}
-	lines, err := l.DataComparisonViewer.ViewChallengerComparison(itemPriceCache, realData, generatedData)
+	lines, err := l.DataComparisonViewer.ViewChallengerComparison(valuation, realData, generatedData)
 	if err != nil {
 		return stacktrace.Propagate(err, "failed to generate challenger comparison for %q", metadata)
 	}
@@ -120,13 +131,15 @@ func (l *DataComparisonLogger) CompareChallenger(itemPriceCache caches.ItemPrice
 	return nil
 }
 
-func (l *DataComparisonLogger) CompareAllChallengers(itemPriceCache caches.ItemPriceCache) error {
-	data, err := l.DataComparisonService.CompareAllChallengers(itemPriceCache)
+func (l *DataComparisonLogger) CompareAllChallengers(valuation valuations.ItemValuation) error {
+	logValuation(valuation)
+
+	data, err := l.DataComparisonService.CompareAllChallengers(valuation)
 	if err != nil {
 		return stacktrace.Propagate(err, "failed to compare all challengers")
 	}
 
-	lines, err := l.DataComparisonViewer.ViewChallengerComparisons(itemPriceCache, data)
+	lines, err := l.DataComparisonViewer.ViewChallengerComparisons(valuation, data)
 	if err != nil {
 		return stacktrace.Propagate(err, "failed to generate challenger comparison view")
 	}
@@ -0,0 +1,40 @@
+package loggers
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/palantir/stacktrace"
+)
+
+type DropDataExchangeLogger struct {
+	DropDataExchangeService *services.DropDataExchangeService
+}
+
+func NewDropDataExchangeLogger(dropDataExchangeService *services.DropDataExchangeService) *DropDataExchangeLogger {
+	return &DropDataExchangeLogger{
+		DropDataExchangeService: dropDataExchangeService,
+	}
+}
+
+func (l *DropDataExchangeLogger) Export(dropDataFolderPath string, format string, outputFilePath string, contributorFilter string) error {
+	recordCount, err := l.DropDataExchangeService.Export(dropDataFolderPath, format, outputFilePath, contributorFilter)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to export drop data as %s", format)
+	}
+	slog.Info(fmt.Sprintf("Exported %d drop record(s) to %s", recordCount, outputFilePath))
+	return nil
+}
+
+func (l *DropDataExchangeLogger) Import(format string, inputFilePath string, dropDataFolderPath string, shouldOverwrite bool) error {
+	writtenFiles, err := l.DropDataExchangeService.Import(format, inputFilePath, dropDataFolderPath, shouldOverwrite)
+	for _, file := range writtenFiles {
+		slog.Info(fmt.Sprintf("Wrote %s", file))
+	}
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to import %s", inputFilePath)
+	}
+	slog.Info(fmt.Sprintf("Imported %d day(s) of drops from %s", len(writtenFiles), inputFilePath))
+	return nil
+}
@@ -0,0 +1,59 @@
+package loggers
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/palantir/stacktrace"
+)
+
+type DropDataImportLogger struct {
+	DropDataImportService *services.DropDataImportService
+}
+
+func NewDropDataImportLogger(dropDataImportService *services.DropDataImportService) *DropDataImportLogger {
+	return &DropDataImportLogger{
+		DropDataImportService: dropDataImportService,
+	}
+}
+
+func (l *DropDataImportLogger) ImportHtml(htmlFilePath string, dropDataFolderPath string, date time.Time, overrides models.BattledomeItemMetadata) error {
+	dropDataFilePath, battles, err := l.DropDataImportService.ImportHtml(htmlFilePath, dropDataFolderPath, date, overrides)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to import %s", htmlFilePath)
+	}
+
+	for _, battle := range battles {
+		normalisedItems, err := battle.Items.Normalise()
+		if err != nil {
+			return helpers.PropagateWithSerialisedValue(err, "failed to normalise items: %s", "failed to normalise items; another error occurred while trying to serialise the input: %s", battle)
+		}
+
+		table := helpers.NewNamedTable(battle.String(), []string{
+			"Item Name",
+			"Qty",
+		})
+		for _, item := range helpers.OrderBy(helpers.Values(normalisedItems), func(item *models.BattledomeItem) string {
+			return string(item.Name)
+		}) {
+			table.AddRow([]string{
+				string(item.Name),
+				helpers.FormatInt(int(item.Quantity)),
+			})
+		}
+		for _, line := range table.Lines() {
+			slog.Info(line)
+		}
+	}
+
+	totalDrops := helpers.Sum(helpers.Map(battles, func(battle *models.Battle) int {
+		return battle.TotalItemQuantity()
+	}))
+	slog.Info(fmt.Sprintf("Imported %d drop(s) from %d battle(s) into %s", totalDrops, len(battles), filepath.Base(dropDataFilePath)))
+	return nil
+}
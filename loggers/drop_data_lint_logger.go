@@ -0,0 +1,44 @@
+package loggers
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/palantir/stacktrace"
+)
+
+type DropDataLintLogger struct {
+	DropDataLintService *services.DropDataLintService
+}
+
+func NewDropDataLintLogger(dropDataLintService *services.DropDataLintService) *DropDataLintLogger {
+	return &DropDataLintLogger{
+		DropDataLintService: dropDataLintService,
+	}
+}
+
+func (l *DropDataLintLogger) Log(dataFolderPath string, shouldFix bool) (*services.LintReport, error) {
+	report, err := l.DropDataLintService.Lint(dataFolderPath, shouldFix)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to lint %s", dataFolderPath)
+	}
+
+	for _, file := range report.FixedFiles {
+		slog.Info(fmt.Sprintf("Fixed whitespace/casing in %s", file))
+	}
+
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case services.LintError:
+			slog.Error(finding.DropDataDiagnostic.String())
+		case services.LintWarning:
+			slog.Warn(finding.DropDataDiagnostic.String())
+		default:
+			slog.Info(finding.DropDataDiagnostic.String())
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Checked %d files: %d error(s), %d warning(s), %d alias(es) resolved, %d file(s) fixed", report.FilesChecked, report.Count(services.LintError), report.Count(services.LintWarning), report.Count(services.LintInfo), len(report.FixedFiles)))
+	return report, nil
+}
@@ -0,0 +1,33 @@
+package loggers
+
+import (
+	"log/slog"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/viewers"
+	"github.com/palantir/stacktrace"
+)
+
+type DryStreakLogger struct {
+	DryStreakService *services.DryStreakService
+	DryStreakViewer  *viewers.DryStreakViewer
+}
+
+func NewDryStreakLogger(dryStreakService *services.DryStreakService, dryStreakViewer *viewers.DryStreakViewer) *DryStreakLogger {
+	return &DryStreakLogger{
+		DryStreakService: dryStreakService,
+		DryStreakViewer:  dryStreakViewer,
+	}
+}
+
+func (l *DryStreakLogger) Log(item string, metadata models.BattledomeItemMetadata) error {
+	report, err := l.DryStreakService.DryStreak(item, metadata)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to work out the dry streak of %q in %s", item, metadata.String())
+	}
+	for _, line := range l.DryStreakViewer.ViewDryStreak(report) {
+		slog.Info(line)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package loggers
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/palantir/stacktrace"
+)
+
+type GeneratedDropsCacheLogger struct {
+	GeneratedDropsCacheService *services.GeneratedDropsCacheService
+}
+
+func NewGeneratedDropsCacheLogger(generatedDropsCacheService *services.GeneratedDropsCacheService) *GeneratedDropsCacheLogger {
+	return &GeneratedDropsCacheLogger{
+		GeneratedDropsCacheService: generatedDropsCacheService,
+	}
+}
+
+func (l *GeneratedDropsCacheLogger) List() error {
+	entries, err := l.GeneratedDropsCacheService.Entries()
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to list generated drops")
+	}
+	if len(entries) == 0 {
+		slog.Info("There are no generated drops saved yet")
+		return nil
+	}
+
+	table := helpers.NewNamedTable("Generated drops", []string{
+		"i",
+		"File",
+		"Pool",
+		"Items",
+		"Seed",
+		"Generator",
+		"Weights",
+		"Generated At",
+		"Size",
+		"Status",
+	})
+	for i, entry := range entries {
+		row := []string{strconv.Itoa(i + 1), entry.FileName, "?", "?", "?", "?", "?", "?"}
+		if entry.Provenance != nil {
+			row = []string{
+				strconv.Itoa(i + 1),
+				entry.FileName,
+				entry.Provenance.PoolName(),
+				helpers.FormatInt(entry.Provenance.Count),
+				strconv.FormatUint(entry.Provenance.Seed, 10),
+				fmt.Sprintf("v%d", entry.Provenance.GeneratorVersion),
+				entry.Provenance.WeightsHash[:min(len(entry.Provenance.WeightsHash), 12)],
+				entry.Provenance.GeneratedAt.Local().Format(constants.TimeLayout),
+			}
+		}
+		table.AddRow(append(row,
+			helpers.FormatInt(int(entry.Size/1024))+" KiB",
+			helpers.When(entry.IsStale, "stale", "current"),
+		))
+	}
+	for _, line := range table.Lines() {
+		slog.Info(line)
+	}
+	return nil
+}
+
+func (l *GeneratedDropsCacheLogger) Clear(isOnlyStale bool) error {
+	cleared, err := l.GeneratedDropsCacheService.Clear(isOnlyStale)
+	for _, entry := range cleared {
+		slog.Info(fmt.Sprintf("Deleted %s", entry.FileName))
+	}
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to clear generated drops")
+	}
+	slog.Info(fmt.Sprintf("Deleted %d %sgenerated drops file(s)", len(cleared), helpers.When(isOnlyStale, "stale ", "")))
+	return nil
+}
@@ -0,0 +1,40 @@
+package loggers
+
+import (
+	"log/slog"
+
+	"github.com/darienchong/neopets-battledome-analysis/parsers"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/viewers"
+	"github.com/palantir/stacktrace"
+)
+
+type GoalLogger struct {
+	WishlistParser *parsers.WishlistParser
+	GoalService    *services.GoalService
+	GoalViewer     *viewers.GoalViewer
+}
+
+func NewGoalLogger(wishlistParser *parsers.WishlistParser, goalService *services.GoalService, goalViewer *viewers.GoalViewer) *GoalLogger {
+	return &GoalLogger{
+		WishlistParser: wishlistParser,
+		GoalService:    goalService,
+		GoalViewer:     goalViewer,
+	}
+}
+
+// rates is services.ObservedGoalRates or services.DataminedGoalRates
+func (l *GoalLogger) Log(wishlistFilePath string, rates string) error {
+	wishlist, err := l.WishlistParser.Parse(wishlistFilePath)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to parse wishlist %s", wishlistFilePath)
+	}
+	report, err := l.GoalService.Plan(wishlist, rates)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to plan wishlist %s", wishlistFilePath)
+	}
+	for _, line := range l.GoalViewer.ViewGoal(report) {
+		slog.Info(line)
+	}
+	return nil
+}
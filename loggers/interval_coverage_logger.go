@@ -0,0 +1,44 @@
+package loggers
+
+import (
+	"log/slog"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/viewers"
+	"github.com/palantir/stacktrace"
+)
+
+type IntervalCoverageLogger struct {
+	StatisticsService      *services.StatisticsService
+	IntervalCoverageViewer *viewers.IntervalCoverageViewer
+}
+
+func NewIntervalCoverageLogger(statisticsService *services.StatisticsService, intervalCoverageViewer *viewers.IntervalCoverageViewer) *IntervalCoverageLogger {
+	return &IntervalCoverageLogger{
+		StatisticsService:      statisticsService,
+		IntervalCoverageViewer: intervalCoverageViewer,
+	}
+}
+
+// Compares every interval method at each drop rate after each number of days of drops
+func (l *IntervalCoverageLogger) Log(days []int, rates []float64) error {
+	coverages := []models.BinomialIntervalCoverage{}
+	for _, rate := range rates {
+		for _, day := range days {
+			for _, method := range services.BinomialIntervalMethods {
+				coverage, err := l.StatisticsService.Coverage(method, day*constants.BattledomeDropsPerDay, rate, constants.SignificanceLevel)
+				if err != nil {
+					return stacktrace.Propagate(err, "failed to work out the coverage of %s for a drop rate of %g over %d days", method, rate, day)
+				}
+				coverages = append(coverages, coverage)
+			}
+		}
+	}
+
+	for _, line := range l.IntervalCoverageViewer.ViewCoverage(coverages) {
+		slog.Info(line)
+	}
+	return nil
+}
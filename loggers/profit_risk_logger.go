@@ -0,0 +1,52 @@
+package loggers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+	"github.com/darienchong/neopets-battledome-analysis/viewers"
+	"github.com/palantir/stacktrace"
+)
+
+type ProfitRiskLogger struct {
+	ProfitRiskService *services.ProfitRiskService
+	ProfitRiskViewer  *viewers.ProfitRiskViewer
+}
+
+func NewProfitRiskLogger(profitRiskService *services.ProfitRiskService, profitRiskViewer *viewers.ProfitRiskViewer) *ProfitRiskLogger {
+	return &ProfitRiskLogger{
+		ProfitRiskService: profitRiskService,
+		ProfitRiskViewer:  profitRiskViewer,
+	}
+}
+
+// Also writes the report as JSON to jsonFilePath, unless it's empty
+func (l *ProfitRiskLogger) Log(valuation valuations.ItemValuation, metadata models.BattledomeItemMetadata, dailyThreshold float64, jsonFilePath string) error {
+	logValuation(valuation)
+
+	report, err := l.ProfitRiskService.Risk(valuation, metadata, dailyThreshold)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to work out profit risk for %s", metadata.String())
+	}
+	for _, line := range l.ProfitRiskViewer.ViewRisk(report) {
+		slog.Info(line)
+	}
+
+	if jsonFilePath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to serialise profit risk report")
+	}
+	if err := os.WriteFile(jsonFilePath, content, 0644); err != nil {
+		return stacktrace.Propagate(err, "failed to write profit risk report to %q", jsonFilePath)
+	}
+	slog.Info(fmt.Sprintf("Wrote profit risk report to %s", jsonFilePath))
+	return nil
+}
@@ -0,0 +1,35 @@
+package loggers
+
+import (
+	"log/slog"
+
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+	"github.com/darienchong/neopets-battledome-analysis/viewers"
+	"github.com/palantir/stacktrace"
+)
+
+type RecommendationLogger struct {
+	RecommendationService *services.RecommendationService
+	RecommendationViewer  *viewers.RecommendationViewer
+}
+
+func NewRecommendationLogger(recommendationService *services.RecommendationService, recommendationViewer *viewers.RecommendationViewer) *RecommendationLogger {
+	return &RecommendationLogger{
+		RecommendationService: recommendationService,
+		RecommendationViewer:  recommendationViewer,
+	}
+}
+
+func (l *RecommendationLogger) Log(valuation valuations.ItemValuation, constraints services.RecommendationConstraints) error {
+	logValuation(valuation)
+
+	report, err := l.RecommendationService.Recommend(valuation, constraints)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to recommend a fight")
+	}
+	for _, line := range l.RecommendationViewer.ViewRecommendations(report) {
+		slog.Info(line)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package loggers
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/palantir/stacktrace"
+)
+
+type ScrapeTelemetryLogger struct {
+	ScrapeTelemetryService *services.ScrapeTelemetryService
+}
+
+func NewScrapeTelemetryLogger(scrapeTelemetryService *services.ScrapeTelemetryService) *ScrapeTelemetryLogger {
+	return &ScrapeTelemetryLogger{
+		ScrapeTelemetryService: scrapeTelemetryService,
+	}
+}
+
+func failureRatesTable(name string, keyHeader string, rates []*services.ScrapeFailureRate) *helpers.Table {
+	table := helpers.NewNamedTable(name, []string{
+		"i",
+		keyHeader,
+		"Requests",
+		"Failures",
+		"Failure Rate",
+		"Top Failure",
+		"Mean Latency",
+	})
+	for i, rate := range rates {
+		table.AddRow([]string{
+			strconv.Itoa(i + 1),
+			rate.Key,
+			helpers.FormatInt(rate.Requests),
+			helpers.FormatInt(rate.Failures),
+			helpers.FormatPercentage(rate.Rate()) + "%",
+			helpers.When(rate.TopFailureClass() == "", "-", rate.TopFailureClass()),
+			helpers.FormatFloat(rate.MeanLatencyMs()) + " ms",
+		})
+	}
+	return table
+}
+
+func (l *ScrapeTelemetryLogger) Log() error {
+	records, err := l.ScrapeTelemetryService.Records()
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to get scrape records")
+	}
+
+	if len(records) == 0 {
+		slog.Info(fmt.Sprintf("No scrape telemetry has been recorded yet (expected at %s)", constants.ScrapeTelemetryFilePath()))
+		return nil
+	}
+
+	failures := helpers.Count(records, func(record helpers.ScrapeRecord) bool {
+		return record.IsFailure()
+	})
+	slog.Info(fmt.Sprintf("%s requests between %s and %s; %s failed (%s%%)",
+		helpers.FormatInt(len(records)),
+		records[0].Timestamp.Local().Format(constants.TimeLayout),
+		records[len(records)-1].Timestamp.Local().Format(constants.TimeLayout),
+		helpers.FormatInt(failures),
+		helpers.FormatPercentage(float64(failures)/float64(len(records))),
+	))
+
+	tables := []*helpers.Table{
+		failureRatesTable("Failure rates by source", "Source", l.ScrapeTelemetryService.FailureRatesBySource(records)),
+		failureRatesTable("Failure rates by hour", "Hour", l.ScrapeTelemetryService.FailureRatesByHour(records)),
+		failureRatesTable("Failure rates by user agent", "User Agent", l.ScrapeTelemetryService.FailureRatesByUserAgent(records)),
+	}
+	for _, table := range tables {
+		for _, line := range table.Lines() {
+			slog.Info(line)
+		}
+		slog.Info("")
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package loggers
+
+import (
+	"log/slog"
+
+	"github.com/darienchong/neopets-battledome-analysis/caches"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+	"github.com/darienchong/neopets-battledome-analysis/viewers"
+	"github.com/palantir/stacktrace"
+)
+
+type WhatIfLogger struct {
+	WhatIfService *services.WhatIfService
+	WhatIfViewer  *viewers.WhatIfViewer
+}
+
+func NewWhatIfLogger(whatIfService *services.WhatIfService, whatIfViewer *viewers.WhatIfViewer) *WhatIfLogger {
+	return &WhatIfLogger{
+		WhatIfService: whatIfService,
+		WhatIfViewer:  whatIfViewer,
+	}
+}
+
+// scenario should value items the same way as baseline, only over the adjusted prices
+func (l *WhatIfLogger) Log(baseline valuations.ItemValuation, scenario valuations.ItemValuation, adjustments []caches.PriceAdjustment) error {
+	logValuation(baseline)
+
+	report, err := l.WhatIfService.Compare(baseline, scenario)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to compare the price scenario")
+	}
+	for _, line := range l.WhatIfViewer.ViewWhatIf(adjustments, report) {
+		slog.Info(line)
+	}
+	return nil
+}
@@ -2,15 +2,21 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/infra"
 	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
 )
 
 var (
@@ -20,6 +26,17 @@ var (
 		"arenas",
 		"challengers",
 		"challenger",
+		"scrape-stats",
+		"lint",
+		"import",
+		"export",
+		"cache",
+		"risk",
+		"coverage",
+		"dry",
+		"goal",
+		"recommend",
+		"whatif",
 	}
 )
 
@@ -46,6 +63,38 @@ func callClear() {
 	}
 }
 
+// The value following --name in args, or "" if it isn't there
+func flagValue(args []string, name string) string {
+	index := slices.Index(args, "--"+name)
+	if index == -1 || index+1 >= len(args) {
+		return ""
+	}
+	return args[index+1]
+}
+
+// args with --name (and the value after it, if hasValue) taken out
+func withoutFlag(args []string, name string, hasValue bool) []string {
+	index := slices.Index(args, "--"+name)
+	if index == -1 {
+		return args
+	}
+	end := min(index+helpers.When(hasValue, 2, 1), len(args))
+	return slices.Concat(args[:index], args[end:])
+}
+
+// Each comma-separated value in raw, parsed
+func parseList[T any](raw string, parse func(value string) (T, error)) ([]T, error) {
+	values := []T{}
+	for _, rawValue := range strings.Split(raw, ",") {
+		value, err := parse(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
 func main() {
 	callClear()
 
@@ -57,6 +106,76 @@ func main() {
 	if len(args) == 0 {
 		panic(fmt.Errorf("please provide an argument (one of %s)", strings.Join(possibleArgs, ", ")))
 	}
+
+	// Contributor options apply to every command, so they're taken out before the command sees its arguments
+	battledomeItemsService := serviceContainer.GetBattledomeItemsService()
+	battledomeItemsService.ContributorFilter = flagValue(args, "contributor")
+	args = withoutFlag(args, "contributor", true)
+	isGroupedByContributor := slices.Contains(args, "--by-contributor")
+	args = withoutFlag(args, "by-contributor", false)
+
+	// So is how items are valued, e.g. to rank arenas by what they're worth for training rather than for NP
+	stoneValue := 0.0
+	if rawStoneValue := flagValue(args, "stone-value"); rawStoneValue != "" {
+		var err error
+		stoneValue, err = strconv.ParseFloat(rawStoneValue, 64)
+		if err != nil {
+			panic(err)
+		}
+	}
+	valuationProfile := flagValue(args, "valuation")
+	valuationOver := func(itemPriceCache caches.ItemPriceCache) (valuations.ItemValuation, error) {
+		return valuations.ValuationProfile(valuationProfile, itemPriceCache, stoneValue)
+	}
+	valuation, err := valuationOver(itemPriceCache)
+	if err != nil {
+		panic(err)
+	}
+	args = withoutFlag(withoutFlag(args, "valuation", true), "stone-value", true)
+
+	// And how profit intervals are bootstrapped
+	bootstrapService := serviceContainer.GetBootstrapService()
+	if rawMethod := flagValue(args, "bootstrap"); rawMethod != "" {
+		bootstrapService.Method, err = services.ParseBootstrapMethod(rawMethod)
+		if err != nil {
+			panic(err)
+		}
+	}
+	if rawSeed := flagValue(args, "seed"); rawSeed != "" {
+		bootstrapService.Seed, err = strconv.ParseUint(rawSeed, 10, 64)
+		if err != nil {
+			panic(err)
+		}
+	}
+	args = withoutFlag(withoutFlag(args, "bootstrap", true), "seed", true)
+
+	// And drop rate intervals
+	if rawIntervalMethod := flagValue(args, "interval"); rawIntervalMethod != "" {
+		serviceContainer.GetStatisticsService().IntervalMethod, err = services.ParseBinomialIntervalMethod(rawIntervalMethod)
+		if err != nil {
+			panic(err)
+		}
+	}
+	args = withoutFlag(args, "interval", true)
+
+	if !isGroupedByContributor {
+		runCommand(serviceContainer, valuation, valuationOver, args)
+		return
+	}
+
+	contributors, err := battledomeItemsService.Contributors()
+	if err != nil {
+		panic(err)
+	}
+	for _, contributor := range contributors {
+		slog.Info(fmt.Sprintf("===== %s =====", contributor))
+		battledomeItemsService.ContributorFilter = contributor
+		runCommand(serviceContainer, valuation, valuationOver, args)
+	}
+}
+
+// valuationOver values items the same way as valuation, but over other prices
+func runCommand(serviceContainer *infra.ServiceContainer, valuation valuations.ItemValuation, valuationOver func(itemPriceCache caches.ItemPriceCache) (valuations.ItemValuation, error), args []string) {
 	dataFolderPath := strings.Replace(constants.BattledomeDropsFolder, "../", "", 1)
 	switch args[0] {
 	case possibleArgs[0]:
@@ -70,21 +189,21 @@ func main() {
 			}
 		}
 
-		serviceContainer.GetBattledomeItemsLogger().Log(itemPriceCache, dataFolderPath, int(numDropsToLog))
+		serviceContainer.GetBattledomeItemsLogger().Log(valuation, dataFolderPath, int(numDropsToLog))
 	case possibleArgs[1]:
 		if len(args) > 1 && args[1] == "brief" {
-			err := serviceContainer.GetDataComparisonLogger().BriefCompareAllArenas(itemPriceCache)
+			err := serviceContainer.GetDataComparisonLogger().BriefCompareAllArenas(valuation)
 			if err != nil {
 				panic(err)
 			}
 		} else {
-			err := serviceContainer.GetDataComparisonLogger().CompareAllArenas(itemPriceCache)
+			err := serviceContainer.GetDataComparisonLogger().CompareAllArenas(valuation)
 			if err != nil {
 				panic(err)
 			}
 		}
 	case possibleArgs[2]:
-		err := serviceContainer.GetDataComparisonLogger().CompareAllChallengers(itemPriceCache)
+		err := serviceContainer.GetDataComparisonLogger().CompareAllChallengers(valuation)
 		if err != nil {
 			panic(err)
 		}
@@ -99,7 +218,7 @@ func main() {
 			panic(fmt.Errorf("please provide a difficulty"))
 		}
 
-		err := serviceContainer.GetDataComparisonLogger().CompareChallenger(itemPriceCache, models.BattledomeItemMetadata{
+		err := serviceContainer.GetDataComparisonLogger().CompareChallenger(valuation, models.BattledomeItemMetadata{
 			Arena:      models.Arena(strings.ReplaceAll(args[1], "_", " ")),
 			Challenger: models.Challenger(strings.ReplaceAll(args[2], "_", " ")),
 			Difficulty: models.Difficulty(strings.ReplaceAll(args[3], "_", " ")),
@@ -107,6 +226,204 @@ func main() {
 		if err != nil {
 			panic(err)
 		}
+	case possibleArgs[4]:
+		err := serviceContainer.GetScrapeTelemetryLogger().Log()
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[5]:
+		shouldFix := slices.Contains(args[1:], "--fix")
+		report, err := serviceContainer.GetDropDataLintLogger().Log(constants.DropDataFilePath(""), shouldFix)
+		if err != nil {
+			panic(err)
+		}
+		if report.HasErrors() {
+			serviceContainer.GetItemPriceCache().Close()
+			os.Exit(1)
+		}
+	case possibleArgs[6]:
+		if len(args) >= 3 && (args[1] == "json" || args[1] == "csv") {
+			err := serviceContainer.GetDropDataExchangeLogger().Import(args[1], args[2], constants.DropDataFilePath(""), slices.Contains(args[3:], "--overwrite"))
+			if err != nil {
+				panic(err)
+			}
+			break
+		}
+		if len(args) >= 2 && args[1] == "html" {
+			// The prize log parser's selectors were never checked against real saved pages, so it may find nothing or
+			// the wrong items; it stays off until there are real pages to test it against
+			panic(fmt.Errorf("import html is disabled until its selectors have been checked against real saved Battledome pages"))
+		}
+		panic(fmt.Errorf("usage: import json|csv <file> [--overwrite]"))
+	case possibleArgs[7]:
+		if len(args) < 3 {
+			panic(fmt.Errorf("usage: export json|csv <output file>"))
+		}
+		err := serviceContainer.GetDropDataExchangeLogger().Export(constants.DropDataFilePath(""), args[1], args[2], serviceContainer.GetBattledomeItemsService().ContributorFilter)
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[8]:
+		var err error
+		switch {
+		case len(args) >= 2 && args[1] == "list":
+			err = serviceContainer.GetGeneratedDropsCacheLogger().List()
+		case len(args) >= 2 && args[1] == "clear":
+			err = serviceContainer.GetGeneratedDropsCacheLogger().Clear(slices.Contains(args[2:], "--stale"))
+		default:
+			err = fmt.Errorf("usage: cache list, or cache clear [--stale]")
+		}
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[9]:
+		dailyThreshold := 0.0
+		if rawThreshold := flagValue(args, "below"); rawThreshold != "" {
+			var err error
+			dailyThreshold, err = strconv.ParseFloat(rawThreshold, 64)
+			if err != nil {
+				panic(err)
+			}
+		}
+		jsonFilePath := flagValue(args, "json")
+		args = withoutFlag(withoutFlag(args, "below", true), "json", true)
+		if len(args) != 2 && len(args) != 4 {
+			panic(fmt.Errorf("usage: risk <arena> [<challenger> <difficulty>] [--below NP] [--json file]"))
+		}
+
+		metadata := models.BattledomeItemMetadata{
+			Arena: models.Arena(strings.ReplaceAll(args[1], "_", " ")),
+		}
+		if len(args) == 4 {
+			metadata.Challenger = models.Challenger(strings.ReplaceAll(args[2], "_", " "))
+			metadata.Difficulty = models.Difficulty(strings.ReplaceAll(args[3], "_", " "))
+		}
+		err := serviceContainer.GetProfitRiskLogger().Log(valuation, metadata, dailyThreshold, jsonFilePath)
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[10]:
+		days := constants.CoverageDays
+		if rawDays := flagValue(args, "days"); rawDays != "" {
+			var err error
+			days, err = parseList(rawDays, strconv.Atoi)
+			if err != nil {
+				panic(err)
+			}
+		}
+		rates := constants.CoverageDropRates
+		if rawRates := flagValue(args, "rates"); rawRates != "" {
+			var err error
+			rates, err = parseList(rawRates, func(value string) (float64, error) {
+				return strconv.ParseFloat(value, 64)
+			})
+			if err != nil {
+				panic(err)
+			}
+		}
+		err := serviceContainer.GetIntervalCoverageLogger().Log(days, rates)
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[11]:
+		if len(args) != 3 && len(args) != 5 {
+			panic(fmt.Errorf("usage: dry <item or item group> <arena> [<challenger> <difficulty>]"))
+		}
+
+		metadata := models.BattledomeItemMetadata{
+			Arena: models.Arena(strings.ReplaceAll(args[2], "_", " ")),
+		}
+		if len(args) == 5 {
+			metadata.Challenger = models.Challenger(strings.ReplaceAll(args[3], "_", " "))
+			metadata.Difficulty = models.Difficulty(strings.ReplaceAll(args[4], "_", " "))
+		}
+		err := serviceContainer.GetDryStreakLogger().Log(strings.ReplaceAll(args[1], "_", " "), metadata)
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[12]:
+		goalService := serviceContainer.GetGoalService()
+		if rawMinimumSamples := flagValue(args, "min-samples"); rawMinimumSamples != "" {
+			var err error
+			goalService.MinimumSamples, err = strconv.Atoi(rawMinimumSamples)
+			if err != nil {
+				panic(err)
+			}
+		}
+		rates := helpers.When(flagValue(args, "source") != "", flagValue(args, "source"), constants.GoalDropRates)
+		args = withoutFlag(withoutFlag(args, "min-samples", true), "source", true)
+		if len(args) != 2 {
+			panic(fmt.Errorf("usage: goal <wishlist file> [--source observed|datamined] [--min-samples n]"))
+		}
+		err := serviceContainer.GetGoalLogger().Log(args[1], rates)
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[13]:
+		constraints := services.RecommendationConstraints{
+			MinimumSamples: constants.RecommendationMinimumSamples,
+			Criterion:      constants.RecommendationCriterion,
+			ShortlistSize:  constants.RecommendationShortlistSize,
+		}
+		var err error
+		if rawChallengers := flagValue(args, "beatable"); rawChallengers != "" {
+			constraints.Challengers, err = parseList(rawChallengers, func(value string) (models.Challenger, error) {
+				return models.Challenger(strings.ReplaceAll(value, "_", " ")), nil
+			})
+			if err != nil {
+				panic(err)
+			}
+		}
+		if rawDifficulties := flagValue(args, "difficulties"); rawDifficulties != "" {
+			constraints.Difficulties, err = parseList(rawDifficulties, func(value string) (models.Difficulty, error) {
+				return models.Difficulty(value), nil
+			})
+			if err != nil {
+				panic(err)
+			}
+		}
+		if rawMinimumSamples := flagValue(args, "min-samples"); rawMinimumSamples != "" {
+			constraints.MinimumSamples, err = strconv.Atoi(rawMinimumSamples)
+			if err != nil {
+				panic(err)
+			}
+		}
+		if rawCriterion := flagValue(args, "risk"); rawCriterion != "" {
+			constraints.Criterion, err = services.ParseRecommendationCriterion(rawCriterion)
+			if err != nil {
+				panic(err)
+			}
+		}
+		if rawShortlistSize := flagValue(args, "top"); rawShortlistSize != "" {
+			constraints.ShortlistSize, err = strconv.Atoi(rawShortlistSize)
+			if err != nil {
+				panic(err)
+			}
+		}
+		err = serviceContainer.GetRecommendationLogger().Log(valuation, constraints)
+		if err != nil {
+			panic(err)
+		}
+	case possibleArgs[14]:
+		if len(args) < 2 {
+			panic(fmt.Errorf("usage: whatif <item or group>=<change> [<item or group>=<change> ...], e.g. whatif Nerkmids=-50%% Kasuki_Lu_Plushie=2000000 Lu_Codestone=+1000"))
+		}
+		adjustments := []caches.PriceAdjustment{}
+		for _, rawAdjustment := range args[1:] {
+			adjustment, err := caches.ParsePriceAdjustment(strings.ReplaceAll(rawAdjustment, "_", " "))
+			if err != nil {
+				panic(err)
+			}
+			adjustments = append(adjustments, adjustment)
+		}
+		scenario, err := valuationOver(caches.NewScenarioItemPriceCache(serviceContainer.GetItemPriceCache(), adjustments, helpers.ItemGroupsInstance()))
+		if err != nil {
+			panic(err)
+		}
+		err = serviceContainer.GetWhatIfLogger().Log(valuation, scenario, adjustments)
+		if err != nil {
+			panic(err)
+		}
 	default:
 		panic(fmt.Errorf("please provide an argument (one of %s)", strings.Join(possibleArgs, ", ")))
 	}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+)
+
+// One section of a drop file; a day's drops can be split across several battles with different challengers
+type Battle struct {
+	Metadata BattledomeItemMetadata
+	// Zero if the section didn't record one
+	Timestamp time.Time
+	// $TIME as it was written, e.g. "09:30", so that it can be written back out the same way
+	RawTime string
+	// Zero if the section didn't record one
+	Wins  int
+	Items BattledomeItems
+	// The comments and blank lines in the section
+	Comments []DropFileComment
+}
+
+// A comment or blank line, kept so that a drop file can be written back out as it was
+type DropFileComment struct {
+	Line string
+	// How many of the battle's item lines came before it
+	ItemsBefore int
+}
+
+var (
+	timestampLayouts = []string{
+		time.RFC3339,
+		time.DateTime,
+		"2006-01-02 15:04",
+	}
+	timeOfDayLayouts = []string{
+		time.TimeOnly,
+		"15:04",
+	}
+)
+
+// Either a full timestamp or a time of day, which is taken to be on the day the file is named after
+func ParseBattleTimestamp(value string, source string) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if timestamp, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return timestamp, nil
+		}
+	}
+
+	day, err := time.ParseInLocation(constants.DropDataFileNameLayout, source, time.Local)
+	if err != nil {
+		day = time.Time{}
+	}
+	for _, layout := range timeOfDayLayouts {
+		if timeOfDay, err := time.Parse(layout, value); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), timeOfDay.Hour(), timeOfDay.Minute(), timeOfDay.Second(), 0, time.Local), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse %q as a timestamp; expected e.g. %q or %q", value, time.DateTime, "15:04")
+}
+
+// $TIME as it should be written to a drop file
+func (b *Battle) TimeText() string {
+	if b.RawTime != "" {
+		return b.RawTime
+	}
+	if b.Timestamp.IsZero() {
+		return ""
+	}
+	return b.Timestamp.Format(time.DateTime)
+}
+
+func (b *Battle) TotalItemQuantity() int {
+	total := 0
+	for _, item := range b.Items {
+		total += int(item.Quantity)
+	}
+	return total
+}
+
+func (b *Battle) String() string {
+	description := b.Metadata.String()
+	if !b.Timestamp.IsZero() {
+		description += fmt.Sprintf(" @ %s", b.Timestamp.Format(time.DateTime))
+	}
+	if b.Wins > 0 {
+		description += fmt.Sprintf(" (%d wins)", b.Wins)
+	}
+	return description
+}
@@ -3,8 +3,8 @@ package models
 import (
 	"fmt"
 
-	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
 	"github.com/palantir/stacktrace"
 )
 
@@ -14,6 +14,40 @@ type BattledomeItem struct {
 	Metadata BattledomeItemMetadata
 	Name     ItemName
 	Quantity int32
+	// How much of Quantity each contributor recorded; nil for generated items
+	Contributors map[string]int32
+}
+
+// Files without a $CONTRIBUTOR are attributed to this
+const UnattributedContributor = "(unattributed)"
+
+func ContributorName(contributor string) string {
+	return helpers.When(contributor == "", UnattributedContributor, contributor)
+}
+
+func (i *BattledomeItem) AttributeTo(contributor string) {
+	i.Contributors = map[string]int32{
+		ContributorName(contributor): i.Quantity,
+	}
+}
+
+func (i *BattledomeItem) IsFromContributor(contributor string) bool {
+	_, exists := i.Contributors[ContributorName(contributor)]
+	return exists
+}
+
+func mergeContributors(first map[string]int32, second map[string]int32) map[string]int32 {
+	if first == nil && second == nil {
+		return nil
+	}
+	merged := map[string]int32{}
+	for contributor, quantity := range first {
+		merged[contributor] += quantity
+	}
+	for contributor, quantity := range second {
+		merged[contributor] += quantity
+	}
+	return merged
 }
 
 func (first *BattledomeItem) Union(second *BattledomeItem) (*BattledomeItem, error) {
@@ -29,21 +63,29 @@ func (first *BattledomeItem) Union(second *BattledomeItem) (*BattledomeItem, err
 	combined.Metadata = combinedMetadata
 	combined.Name = first.Name
 	combined.Quantity = first.Quantity + second.Quantity
+	combined.Contributors = mergeContributors(first.Contributors, second.Contributors)
 	return combined, nil
 }
 
-func (i *BattledomeItem) Profit(itemPriceCache caches.ItemPriceCache) float64 {
-	return float64(i.Quantity) * itemPriceCache.Price(string(i.Name))
+func (i *BattledomeItem) Profit(valuation valuations.ItemValuation) float64 {
+	return float64(i.Quantity) * valuation.Value(string(i.Name))
 }
 
-func (i *BattledomeItem) PercentageProfit(itemPriceCache caches.ItemPriceCache, items NormalisedBattledomeItems) (float64, error) {
+func (i *BattledomeItem) PercentageProfit(valuation valuations.ItemValuation, items NormalisedBattledomeItems) (float64, error) {
 	var defaultValue float64
 
-	totalProfit, err := items.TotalProfit(itemPriceCache)
+	totalProfit, err := items.TotalProfit(valuation)
 	if err != nil {
 		return defaultValue, helpers.PropagateWithSerialisedValue(err, "failed to get total profit for %q", "failed to get total profit for a battledome item; additionally encountered an error while trying to serialise the value to log: %s", i)
 	}
-	return i.Profit(itemPriceCache) / totalProfit, nil
+	return i.Profit(valuation) / totalProfit, nil
+}
+
+// Whether the item can drop in the arena regardless of the challenger, i.e. it is in the arena's generated drops or is
+// one of the arena's extra drops
+func (i *BattledomeItem) IsArenaDrop(generatedItems NormalisedBattledomeItems) bool {
+	_, exists := generatedItems[i.Name]
+	return exists || helpers.BattledomeCatalogueInstance().IsExtraDrop(string(i.Metadata.Arena), string(i.Name))
 }
 
 func (i *BattledomeItem) DropRate(items NormalisedBattledomeItems) float64 {
@@ -56,8 +98,9 @@ func (i *BattledomeItem) String() string {
 
 func (i *BattledomeItem) Copy() *BattledomeItem {
 	return &BattledomeItem{
-		Metadata: i.Metadata,
-		Name:     i.Name,
-		Quantity: i.Quantity,
+		Metadata:     i.Metadata,
+		Name:         i.Name,
+		Quantity:     i.Quantity,
+		Contributors: mergeContributors(i.Contributors, nil),
 	}
 }
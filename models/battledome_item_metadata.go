@@ -1,6 +1,9 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Arena string
 
@@ -39,12 +42,15 @@ func (first BattledomeItemMetadata) Copy() BattledomeItemMetadata {
 
 type DropsMetadataWithSource struct {
 	Source string
+	// Who recorded the file; empty if it doesn't say
+	Contributor string
 	BattledomeItemMetadata
 }
 
 func (md *DropsMetadataWithSource) Copy() *DropsMetadataWithSource {
 	copy := new(DropsMetadataWithSource)
 	copy.Source = md.Source
+	copy.Contributor = md.Contributor
 	copy.BattledomeItemMetadata = md.BattledomeItemMetadata
 	return copy
 }
@@ -56,6 +62,9 @@ func (first *DropsMetadataWithSource) Combine(second *DropsMetadataWithSource) (
 
 	copy := first.Copy()
 	copy.Source = "(multiple sources)"
+	if first.Contributor != second.Contributor {
+		copy.Contributor = "(multiple contributors)"
+	}
 	return copy, nil
 }
 
@@ -67,6 +76,14 @@ func (md *BattledomeItemMetadata) String() string {
 	return fmt.Sprintf("%s - %s - %s", md.Arena, md.Challenger, md.Difficulty)
 }
 
+// e.g. "Mighty Kasuki Lu in Central Arena", or just the arena if there's no challenger
+func (md BattledomeItemMetadata) FightName() string {
+	if md.Challenger == "" {
+		return string(md.Arena)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s in %s", md.Difficulty, md.Challenger, md.Arena))
+}
+
 func GeneratedMetadata(arena Arena) *DropsMetadataWithSource {
 	return &DropsMetadataWithSource{
 		Source: "(generated)",
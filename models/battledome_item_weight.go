@@ -1,7 +1,19 @@
 package models
 
+// Challenger and Difficulty are empty for items in the arena's own prize pool. For items in a challenger's pool,
+// Weight is the observed rate at which that item drops in fights against the challenger.
 type BattledomeItemWeight struct {
-	Arena  string
-	Name   string
-	Weight float64
+	Arena      string
+	Challenger string
+	Difficulty string
+	Name       string
+	Weight     float64
+}
+
+func (w BattledomeItemWeight) Pool() BattledomeItemMetadata {
+	return BattledomeItemMetadata{
+		Arena:      Arena(w.Arena),
+		Challenger: Challenger(w.Challenger),
+		Difficulty: Difficulty(w.Difficulty),
+	}
 }
@@ -3,13 +3,10 @@ package models
 import (
 	"fmt"
 	"math"
-	"math/rand/v2"
-	"slices"
-	"sort"
 
-	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
 	"github.com/montanaflynn/stats"
 	"github.com/palantir/stacktrace"
 )
@@ -44,30 +41,28 @@ func (n NormalisedBattledomeItems) Metadata() (BattledomeItemMetadata, error) {
 	return BattledomeItemMetadata{}, fmt.Errorf("there was no item to get metadata from")
 }
 
-func generateProfitData(itemPriceCache caches.ItemPriceCache, items NormalisedBattledomeItems) ([]float64, error) {
+func generateProfitData(valuation valuations.ItemValuation, items NormalisedBattledomeItems) ([]float64, error) {
 	profitData := []float64{}
 	for _, item := range items {
 		if item.Name == "nothing" {
 			continue
 		}
 		for j := 0; j < int(item.Quantity); j++ {
-			profitData = append(profitData, itemPriceCache.Price(string(item.Name)))
+			profitData = append(profitData, valuation.Value(string(item.Name)))
 		}
 	}
 
 	return profitData, nil
 }
 
-func generateArenaProfitData(itemPriceCache caches.ItemPriceCache, items NormalisedBattledomeItems, generatedItems NormalisedBattledomeItems) ([]float64, error) {
+func generateArenaProfitData(valuation valuations.ItemValuation, items NormalisedBattledomeItems, generatedItems NormalisedBattledomeItems) ([]float64, error) {
 	profitData := []float64{}
 	for _, item := range items {
 		if item.Name == "nothing" {
 			continue
 		}
-		itemPrice := itemPriceCache.Price(string(item.Name))
-		_, exists := generatedItems[item.Name]
-		_, isArenaSpecificItem := constants.AdditionalArenaSpecificDrops[string(item.Metadata.Arena)][string(item.Name)]
-		if !(exists || isArenaSpecificItem) {
+		itemPrice := valuation.Value(string(item.Name))
+		if !item.IsArenaDrop(generatedItems) {
 			// Remove profit contribution from challenger-specific drops if flag is set
 			itemPrice = 0
 		}
@@ -79,8 +74,8 @@ func generateArenaProfitData(itemPriceCache caches.ItemPriceCache, items Normali
 	return profitData, nil
 }
 
-func (i NormalisedBattledomeItems) MeanDropsProfit(itemPriceCache caches.ItemPriceCache) (float64, error) {
-	profitData, err := generateProfitData(itemPriceCache, i)
+func (i NormalisedBattledomeItems) MeanDropsProfit(valuation valuations.ItemValuation) (float64, error) {
+	profitData, err := generateProfitData(valuation, i)
 	if len(profitData) == 0 {
 		return 0.0, nil
 	}
@@ -97,8 +92,8 @@ func (i NormalisedBattledomeItems) MeanDropsProfit(itemPriceCache caches.ItemPri
 	return mean * constants.BattledomeDropsPerDay, nil
 }
 
-func (i NormalisedBattledomeItems) ArenaMeanDropsProfit(itemPriceCache caches.ItemPriceCache, generatedItems NormalisedBattledomeItems) (float64, error) {
-	profitData, err := generateArenaProfitData(itemPriceCache, i, generatedItems)
+func (i NormalisedBattledomeItems) ArenaMeanDropsProfit(valuation valuations.ItemValuation, generatedItems NormalisedBattledomeItems) (float64, error) {
+	profitData, err := generateArenaProfitData(valuation, i, generatedItems)
 	if len(profitData) == 0 {
 		return 0.0, nil
 	}
@@ -115,8 +110,8 @@ func (i NormalisedBattledomeItems) ArenaMeanDropsProfit(itemPriceCache caches.It
 	return mean * constants.BattledomeDropsPerDay, nil
 }
 
-func (i NormalisedBattledomeItems) DropsProfitStdev(itemPriceCache caches.ItemPriceCache) (float64, error) {
-	profitData, err := generateProfitData(itemPriceCache, i)
+func (i NormalisedBattledomeItems) DropsProfitStdev(valuation valuations.ItemValuation) (float64, error) {
+	profitData, err := generateProfitData(valuation, i)
 	if len(profitData) == 0 {
 		return 0.0, nil
 	}
@@ -132,34 +127,34 @@ func (i NormalisedBattledomeItems) DropsProfitStdev(itemPriceCache caches.ItemPr
 	return stdev * math.Sqrt(constants.BattledomeDropsPerDay), nil
 }
 
-func (i NormalisedBattledomeItems) ItemsOrderedByPrice(itemPriceCache caches.ItemPriceCache) ([]*BattledomeItem, error) {
+func (i NormalisedBattledomeItems) ItemsOrderedByValue(valuation valuations.ItemValuation) ([]*BattledomeItem, error) {
 	orderedItems := []*BattledomeItem{}
 	for _, v := range i {
 		orderedItems = append(orderedItems, v)
 	}
 	return helpers.OrderByDescending(orderedItems, func(item *BattledomeItem) float64 {
-		return itemPriceCache.Price(string(item.Name))
+		return valuation.Value(string(item.Name))
 	}), nil
 }
 
-func (i NormalisedBattledomeItems) ItemsOrderedByProfit(itemPriceCache caches.ItemPriceCache) ([]*BattledomeItem, error) {
+func (i NormalisedBattledomeItems) ItemsOrderedByProfit(valuation valuations.ItemValuation) ([]*BattledomeItem, error) {
 	orderedItems := []*BattledomeItem{}
 	for _, v := range i {
 		orderedItems = append(orderedItems, v)
 	}
 
 	return helpers.OrderByDescending(orderedItems, func(item *BattledomeItem) float64 {
-		return item.Profit(itemPriceCache)
+		return item.Profit(valuation)
 	}), nil
 }
 
-func (i NormalisedBattledomeItems) TotalProfit(itemPriceCache caches.ItemPriceCache) (float64, error) {
+func (i NormalisedBattledomeItems) TotalProfit(valuation valuations.ItemValuation) (float64, error) {
 	totalProfit := 0.0
 	for _, item := range i {
-		if item.Name == "nothing" || itemPriceCache.Price(string(item.Name)) <= 0 {
+		if item.Name == "nothing" || valuation.Value(string(item.Name)) <= 0 {
 			continue
 		} else {
-			totalProfit += item.Profit(itemPriceCache)
+			totalProfit += item.Profit(valuation)
 		}
 	}
 	return totalProfit, nil
@@ -172,6 +167,17 @@ func (i NormalisedBattledomeItems) TotalItemQuantity() int {
 	return helpers.Sum(quantities)
 }
 
+// Number of drops each contributor recorded, for real data
+func (i NormalisedBattledomeItems) SamplesByContributor() map[string]int {
+	samples := map[string]int{}
+	for _, item := range i {
+		for contributor, quantity := range item.Contributors {
+			samples[contributor] += int(quantity)
+		}
+	}
+	return samples
+}
+
 func (i NormalisedBattledomeItems) EstimateDropRates() []*BattledomeItemDropRate {
 	totalItemCount := helpers.Sum(helpers.Map(helpers.Values(i), func(item *BattledomeItem) int32 {
 		return item.Quantity
@@ -185,80 +191,3 @@ func (i NormalisedBattledomeItems) EstimateDropRates() []*BattledomeItemDropRate
 		}
 	})
 }
-
-// Percentile calculates the Pth percentile of a slice of float64 values.
-// P should be between 0 and 1 (e.g., 0.25 for the 25th percentile).
-func percentile(values []float64, p float64) float64 {
-	if len(values) == 0 {
-		panic("cannot calculate percentile of an empty slice")
-	}
-	if p < 0 || p > 1 {
-		panic("p must be between 0 and 1")
-	}
-
-	// Sort the slice in ascending order
-	sort.Float64s(values)
-
-	// Calculate the rank
-	n := float64(len(values))
-	rank := p * (n - 1)
-	lower := int(math.Floor(rank))
-	upper := int(math.Ceil(rank))
-
-	// Interpolate if the rank is not an integer
-	if lower == upper {
-		return values[lower]
-	}
-	weight := rank - float64(lower)
-	return values[lower]*(1-weight) + values[upper]*weight
-}
-
-func (i NormalisedBattledomeItems) ProfitConfidenceInterval(itemPriceCache caches.ItemPriceCache) (float64, float64, error) {
-	profitData, err := generateProfitData(itemPriceCache, i)
-	if err != nil {
-		return 0.0, 0.0, stacktrace.Propagate(err, "failed to generate profit data")
-	}
-
-	if len(profitData) == 0 {
-		return 0.0, 0.0, nil
-	}
-
-	bootstrap_sums := []float64{}
-	for _ = range constants.NumberOfBootstrapSamples {
-		bootstrap_sum := 0.0
-
-		for _ = range constants.BattledomeDropsPerDay {
-			bootstrap_sum += profitData[rand.IntN(len(profitData))]
-		}
-
-		bootstrap_sums = append(bootstrap_sums, bootstrap_sum)
-	}
-
-	slices.Sort(bootstrap_sums)
-	return percentile(bootstrap_sums, constants.SignificanceLevel/2), percentile(bootstrap_sums, 1-constants.SignificanceLevel/2), nil
-}
-
-func (i NormalisedBattledomeItems) ArenaProfitConfidenceInterval(itemPriceCache caches.ItemPriceCache, generatedItems NormalisedBattledomeItems) (float64, float64, error) {
-	profitData, err := generateArenaProfitData(itemPriceCache, i, generatedItems)
-	if err != nil {
-		return 0.0, 0.0, stacktrace.Propagate(err, "failed to generate profit data")
-	}
-
-	if len(profitData) == 0 {
-		return 0.0, 0.0, nil
-	}
-
-	bootstrap_sums := []float64{}
-	for _ = range constants.NumberOfBootstrapSamples {
-		bootstrap_sum := 0.0
-
-		for _ = range constants.BattledomeDropsPerDay {
-			bootstrap_sum += profitData[rand.IntN(len(profitData))]
-		}
-
-		bootstrap_sums = append(bootstrap_sums, bootstrap_sum)
-	}
-
-	slices.Sort(bootstrap_sums)
-	return percentile(bootstrap_sums, constants.SignificanceLevel/2), percentile(bootstrap_sums, 1-constants.SignificanceLevel/2), nil
-}
@@ -1,6 +1,52 @@
 package models
 
+// Metadata is that of the first battle; Items holds the drops from every battle in the file
 type BattledomeItemsDto struct {
 	Metadata DropsMetadataWithSource
+	Battles  []*Battle
 	Items    BattledomeItems
+	// Comments and blank lines before the first battle
+	Comments []string
+}
+
+// The battle that lines are currently being read into, starting one if there isn't any yet
+func (dto *BattledomeItemsDto) CurrentBattle() *Battle {
+	if len(dto.Battles) == 0 {
+		return dto.StartBattle()
+	}
+	return dto.Battles[len(dto.Battles)-1]
+}
+
+func (dto *BattledomeItemsDto) StartBattle() *Battle {
+	battle := &Battle{
+		Items: BattledomeItems{},
+	}
+	dto.Battles = append(dto.Battles, battle)
+	return battle
+}
+
+// Metadata left out of a battle is carried over from the one before it, and every item is attributed to the file's
+// contributor
+func (dto *BattledomeItemsDto) InheritMetadata() {
+	for i, battle := range dto.Battles {
+		if i > 0 {
+			previous := dto.Battles[i-1].Metadata
+			if battle.Metadata.Arena == "" {
+				battle.Metadata.Arena = previous.Arena
+			}
+			if battle.Metadata.Challenger == "" {
+				battle.Metadata.Challenger = previous.Challenger
+			}
+			if battle.Metadata.Difficulty == "" {
+				battle.Metadata.Difficulty = previous.Difficulty
+			}
+		}
+		for _, item := range battle.Items {
+			item.Metadata = battle.Metadata
+			item.AttributeTo(dto.Metadata.Contributor)
+		}
+	}
+	if len(dto.Battles) > 0 {
+		dto.Metadata.BattledomeItemMetadata = dto.Battles[0].Metadata
+	}
 }
@@ -0,0 +1,14 @@
+package models
+
+// How a binomial interval method behaves for a drop rate and number of drops
+type BinomialIntervalCoverage struct {
+	Method string
+	Trials int
+	Rate   float64
+	// The chance that the interval contains Rate
+	Coverage float64
+	// The chances that Rate is below or above the interval
+	BelowInterval float64
+	AboveInterval float64
+	MeanWidth     float64
+}
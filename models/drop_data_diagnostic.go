@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+)
+
+// A problem with a drop data file. Line and Column are 1-based; a Line of 0 means the problem is with the file as a
+// whole.
+type DropDataDiagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d DropDataDiagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// What a strict parse reports for a file that doesn't have the expected number of drops
+func DropCountMessage(expected int, found int) string {
+	return fmt.Sprintf("expected %d drops but found %d", expected, found)
+}
+
+type DropDataParseErrors struct {
+	Diagnostics []DropDataDiagnostic
+}
+
+func (e *DropDataParseErrors) Error() string {
+	return strings.Join(helpers.Map(e.Diagnostics, func(diagnostic DropDataDiagnostic) string {
+		return diagnostic.String()
+	}), "\n")
+}
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+)
+
+const (
+	DropRecordsVersion = 1
+)
+
+// One row of the JSON/CSV interchange format: an item and how many of it dropped in one battle. Battle numbers the
+// battles within a day from 1, so that two battles with the same metadata stay apart. A record with no item is a
+// comment or blank line from the drop file, in its place among the battle's items; battle 0 holds the ones before the
+// first battle.
+type DropRecord struct {
+	Date        string `json:"date"`
+	Battle      int    `json:"battle"`
+	Time        string `json:"time,omitempty"`
+	Wins        int    `json:"wins,omitempty"`
+	Arena       string `json:"arena"`
+	Challenger  string `json:"challenger"`
+	Difficulty  string `json:"difficulty"`
+	Item        string `json:"item"`
+	Quantity    int    `json:"quantity"`
+	Contributor string `json:"contributor,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+type DropRecords struct {
+	Version int          `json:"version"`
+	Drops   []DropRecord `json:"drops"`
+}
+
+// The date the drop file is named after
+func (dto *BattledomeItemsDto) Date() (time.Time, error) {
+	date, err := time.ParseInLocation(constants.DropDataFileNameLayout, dto.Metadata.Source, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not named after a date (%s)", dto.Metadata.Source, constants.DropDataFileNameLayout)
+	}
+	return date, nil
+}
+
+func (dto *BattledomeItemsDto) Records() ([]DropRecord, error) {
+	date, err := dto.Date()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []DropRecord{}
+	for _, comment := range dto.Comments {
+		records = append(records, DropRecord{
+			Date:        date.Format(time.DateOnly),
+			Contributor: dto.Metadata.Contributor,
+			Comment:     comment,
+		})
+	}
+	for i, battle := range dto.Battles {
+		battleRecord := func() DropRecord {
+			return DropRecord{
+				Date:        date.Format(time.DateOnly),
+				Battle:      i + 1,
+				Time:        battle.TimeText(),
+				Wins:        battle.Wins,
+				Arena:       string(battle.Metadata.Arena),
+				Challenger:  string(battle.Metadata.Challenger),
+				Difficulty:  string(battle.Metadata.Difficulty),
+				Contributor: dto.Metadata.Contributor,
+			}
+		}
+		comments := battle.Comments
+		for j := 0; j <= len(battle.Items); j++ {
+			for len(comments) > 0 && comments[0].ItemsBefore <= j {
+				record := battleRecord()
+				record.Comment = comments[0].Line
+				records = append(records, record)
+				comments = comments[1:]
+			}
+			if j < len(battle.Items) {
+				record := battleRecord()
+				record.Item = string(battle.Items[j].Name)
+				record.Quantity = int(battle.Items[j].Quantity)
+				records = append(records, record)
+			}
+		}
+	}
+	return records, nil
+}
+
+// Groups records back into one DTO per day, ordered by date, with battles in the order of their numbers
+func DtosFromRecords(records []DropRecord) ([]*BattledomeItemsDto, error) {
+	dtosByDate := map[string]*BattledomeItemsDto{}
+	battlesByDate := map[string]map[int]*Battle{}
+	for i, record := range records {
+		isComment := strings.TrimSpace(record.Item) == ""
+		if isComment && record.Quantity != 0 {
+			return nil, fmt.Errorf("record %d on %s has a quantity but no item", i+1, record.Date)
+		}
+		if isComment && strings.TrimSpace(record.Comment) != "" && !strings.HasPrefix(record.Comment, "#") {
+			return nil, fmt.Errorf("record %d on %s has no item, and its comment %q does not start with #", i+1, record.Date, record.Comment)
+		}
+		if !isComment && record.Quantity <= 0 {
+			return nil, fmt.Errorf("record %d (%s on %s) has a non-positive quantity %d", i+1, record.Item, record.Date, record.Quantity)
+		}
+
+		date, err := time.ParseInLocation(time.DateOnly, record.Date, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("record %d has date %q, which is not in the form %s", i+1, record.Date, time.DateOnly)
+		}
+		dto, exists := dtosByDate[record.Date]
+		if !exists {
+			dto = &BattledomeItemsDto{
+				Metadata: DropsMetadataWithSource{
+					Source:      date.Format(constants.DropDataFileNameLayout),
+					Contributor: record.Contributor,
+				},
+				Battles: []*Battle{},
+				Items:   BattledomeItems{},
+			}
+			dtosByDate[record.Date] = dto
+			battlesByDate[record.Date] = map[int]*Battle{}
+		}
+		if dto.Metadata.Contributor != record.Contributor {
+			return nil, fmt.Errorf("record %d on %s has contributor %q, but earlier records on that day have %q", i+1, record.Date, record.Contributor, dto.Metadata.Contributor)
+		}
+
+		if isComment && record.Battle == 0 {
+			dto.Comments = append(dto.Comments, record.Comment)
+			continue
+		}
+
+		battleNumber := max(record.Battle, 1)
+		metadata := BattledomeItemMetadata{
+			Arena:      Arena(record.Arena),
+			Challenger: Challenger(record.Challenger),
+			Difficulty: Difficulty(record.Difficulty),
+		}
+		battle, exists := battlesByDate[record.Date][battleNumber]
+		if !exists {
+			battle = &Battle{
+				Metadata: metadata,
+				Wins:     record.Wins,
+				Items:    BattledomeItems{},
+			}
+			if record.Time != "" {
+				timestamp, err := ParseBattleTimestamp(record.Time, dto.Metadata.Source)
+				if err != nil {
+					return nil, fmt.Errorf("record %d has time %q: %s", i+1, record.Time, err)
+				}
+				battle.Timestamp = timestamp
+				battle.RawTime = record.Time
+			}
+			battlesByDate[record.Date][battleNumber] = battle
+		}
+		if battle.Metadata != metadata {
+			return nil, fmt.Errorf("record %d is in battle %d on %s but has metadata %s rather than %s", i+1, battleNumber, record.Date, metadata.String(), battle.Metadata.String())
+		}
+
+		if isComment {
+			battle.Comments = append(battle.Comments, DropFileComment{
+				Line:        record.Comment,
+				ItemsBefore: len(battle.Items),
+			})
+			continue
+		}
+
+		item := &BattledomeItem{
+			Metadata: metadata,
+			Name:     ItemName(record.Item),
+			Quantity: int32(record.Quantity),
+		}
+		battle.Items = append(battle.Items, item)
+	}
+
+	dtos := []*BattledomeItemsDto{}
+	for date, dto := range dtosByDate {
+		battleNumbers := []int{}
+		for battleNumber := range battlesByDate[date] {
+			battleNumbers = append(battleNumbers, battleNumber)
+		}
+		slices.Sort(battleNumbers)
+		for _, battleNumber := range battleNumbers {
+			battle := battlesByDate[date][battleNumber]
+			dto.Battles = append(dto.Battles, battle)
+			dto.Items = append(dto.Items, battle.Items...)
+		}
+		if len(dto.Battles) > 0 {
+			dto.Metadata.BattledomeItemMetadata = dto.Battles[0].Metadata
+		}
+		dtos = append(dtos, dto)
+	}
+	slices.SortFunc(dtos, func(first *BattledomeItemsDto, second *BattledomeItemsDto) int {
+		return strings.Compare(first.Metadata.Source, second.Metadata.Source)
+	})
+	return dtos, nil
+}
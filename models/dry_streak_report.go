@@ -0,0 +1,97 @@
+package models
+
+import (
+	"math"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+)
+
+type PercentileDays struct {
+	Percentile float64
+	Days       int
+}
+
+// How long it takes an item to drop at a drop rate, and how unusual a streak without it is
+type DryStreakOdds struct {
+	// The chance of any one drop being the item
+	Rate float64
+	// The chance of a day's drops including the item
+	DailyRate    float64
+	ExpectedDays float64
+	// Days until the first drop
+	Percentiles []PercentileDays
+	// The chance of going at least as many drops as the current streak without the item
+	ChanceOfCurrentStreak float64
+}
+
+// Nil if the rate isn't positive, as the item would never drop
+func NewDryStreakOdds(rate float64, currentStreak int, percentiles []float64) *DryStreakOdds {
+	if rate <= 0 {
+		return nil
+	}
+	dailyRate := 1 - math.Pow(1-rate, constants.BattledomeDropsPerDay)
+	odds := &DryStreakOdds{
+		Rate:                  rate,
+		DailyRate:             dailyRate,
+		ExpectedDays:          1 / dailyRate,
+		ChanceOfCurrentStreak: math.Pow(1-rate, float64(currentStreak)),
+	}
+	for _, percentile := range percentiles {
+		days := 1
+		if dailyRate < 1 {
+			days = max(1, int(math.Ceil(math.Log(1-percentile)/math.Log(1-dailyRate))))
+		}
+		odds.Percentiles = append(odds.Percentiles, PercentileDays{Percentile: percentile, Days: days})
+	}
+	return odds
+}
+
+type DryStreakReport struct {
+	// An item or an item group
+	Item     string
+	Metadata BattledomeItemMetadata
+	// How many drops were recorded
+	Drops int
+	// How many times the item dropped
+	Occurrences int
+	// Drops since the last battle the item dropped in, or since the first recorded drop if it never has
+	CurrentStreak int
+	LongestStreak int
+	// From the datamined weights; nil if the item isn't in them
+	Predicted *DryStreakOdds
+	// From the recorded drops; nil if the item has never dropped
+	Actual *DryStreakOdds
+}
+
+// Streaks are counted in whole battles, as the order of drops within a battle isn't recorded
+func NewDryStreakReport(item string, metadata BattledomeItemMetadata, battles []*Battle, isItem func(itemName ItemName) bool) *DryStreakReport {
+	report := &DryStreakReport{
+		Item:     item,
+		Metadata: metadata,
+	}
+	for _, battle := range battles {
+		occurrences := 0
+		for _, battleItem := range battle.Items {
+			if isItem(battleItem.Name) {
+				occurrences += int(battleItem.Quantity)
+			}
+		}
+		report.Drops += battle.TotalItemQuantity()
+		report.Occurrences += occurrences
+		if occurrences > 0 {
+			report.CurrentStreak = 0
+			continue
+		}
+		report.CurrentStreak += battle.TotalItemQuantity()
+		report.LongestStreak = max(report.LongestStreak, report.CurrentStreak)
+	}
+	return report
+}
+
+func (r *DryStreakReport) CurrentStreakDays() float64 {
+	return float64(r.CurrentStreak) / constants.BattledomeDropsPerDay
+}
+
+func (r *DryStreakReport) LongestStreakDays() float64 {
+	return float64(r.LongestStreak) / constants.BattledomeDropsPerDay
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// What a generated drops file was generated from. Files generated from the same inputs have the same Key.
+type GeneratedDropsProvenance struct {
+	// The arena, or a challenger's prize pool in it
+	Pool BattledomeItemMetadata
+	// Hash of the pool's item weights
+	WeightsHash      string
+	GeneratorVersion int
+	Seed             uint64
+	Count            int
+	GeneratedAt      time.Time
+}
+
+func (p GeneratedDropsProvenance) Key() string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%d|%d|%d", p.Pool.Arena, p.Pool.Challenger, p.Pool.Difficulty, p.WeightsHash, p.GeneratorVersion, p.Seed, p.Count)))
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+func (p GeneratedDropsProvenance) String() string {
+	return fmt.Sprintf("%s (%d items, seed %d, generator v%d, weights %s)", p.PoolName(), p.Count, p.Seed, p.GeneratorVersion, p.WeightsHash[:min(len(p.WeightsHash), 12)])
+}
+
+// The pool as it is written in the weights file, e.g. "Arena > Challenger"
+func (p GeneratedDropsProvenance) PoolName() string {
+	name := string(p.Pool.Arena)
+	for _, part := range []string{string(p.Pool.Challenger), string(p.Pool.Difficulty)} {
+		if part != "" {
+			name += " > " + part
+		}
+	}
+	return name
+}
@@ -0,0 +1,33 @@
+package models
+
+// How long a wishlist would take from one fight
+type GoalPlan struct {
+	Metadata BattledomeItemMetadata
+	// How many drops the rates came from; zero if they're the datamined weights
+	Samples int
+	// The mean over every simulation, with those that didn't finish counted as taking the simulated number of days, so
+	// it's only a lower bound if ChanceOfFinishing is below 1
+	ExpectedDays float64
+	// Zero days if fewer simulations than the percentile finished
+	Percentiles []PercentileDays
+	// The chance of finishing within the simulated number of days
+	ChanceOfFinishing float64
+	// Wanted items that never drop in the fight
+	MissingItems []string
+}
+
+func (p GoalPlan) IsPossible() bool {
+	return len(p.MissingItems) == 0 && p.ChanceOfFinishing > 0
+}
+
+type GoalReport struct {
+	Wishlist Wishlist
+	// "observed" or "datamined"
+	Rates       string
+	Simulations int
+	MaxDays     int
+	// The percentiles of days each plan has
+	Percentiles []float64
+	// Fastest first
+	Plans []GoalPlan
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"math"
+	"slices"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+)
+
+type HistogramBin struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// Simulated profit over a number of days, from resampling drops
+type ProfitDistribution struct {
+	Days int
+	// Sorted in ascending order
+	Profits []float64
+}
+
+// The share of the total value of the drops that comes from the most valuable fraction of them, e.g. 0.01 for the
+// top 1%
+func (i NormalisedBattledomeItems) TopDropsProfitShare(valuation valuations.ItemValuation, fraction float64) float64 {
+	items := helpers.OrderByDescending(helpers.Filter(helpers.Values(i), func(item *BattledomeItem) bool {
+		return item.Name != "nothing"
+	}), func(item *BattledomeItem) float64 {
+		return valuation.Value(string(item.Name))
+	})
+	totalQuantity := helpers.Sum(helpers.Map(items, func(item *BattledomeItem) int {
+		return int(item.Quantity)
+	}))
+	totalProfit := helpers.Sum(helpers.Map(items, func(item *BattledomeItem) float64 {
+		return item.Profit(valuation)
+	}))
+	if totalProfit <= 0 {
+		return 0.0
+	}
+
+	remaining := int(math.Ceil(fraction * float64(totalQuantity)))
+	topProfit := 0.0
+	for _, item := range items {
+		if remaining <= 0 {
+			break
+		}
+		quantity := min(remaining, int(item.Quantity))
+		topProfit += float64(quantity) * valuation.Value(string(item.Name))
+		remaining -= quantity
+	}
+	return topProfit / totalProfit
+}
+
+func (d *ProfitDistribution) IsEmpty() bool {
+	return len(d.Profits) == 0
+}
+
+func (d *ProfitDistribution) Mean() float64 {
+	if d.IsEmpty() {
+		return 0.0
+	}
+	return helpers.Sum(d.Profits) / float64(len(d.Profits))
+}
+
+// p is between 0 and 1, e.g. 0.25 for the 25th percentile
+func (d *ProfitDistribution) Percentile(p float64) float64 {
+	if d.IsEmpty() {
+		return 0.0
+	}
+	return helpers.Percentile(d.Profits, p)
+}
+
+func (d *ProfitDistribution) Median() float64 {
+	return d.Percentile(0.5)
+}
+
+// The chance of making less than the threshold over the period
+func (d *ProfitDistribution) ProbabilityBelow(threshold float64) float64 {
+	if d.IsEmpty() {
+		return 0.0
+	}
+	below, _ := slices.BinarySearch(d.Profits, threshold)
+	return float64(below) / float64(len(d.Profits))
+}
+
+// The mean profit of the worst alpha of periods, e.g. 0.05 for the worst 5%
+func (d *ProfitDistribution) ExpectedShortfall(alpha float64) float64 {
+	if d.IsEmpty() {
+		return 0.0
+	}
+	tail := d.Profits[:max(1, int(math.Ceil(alpha*float64(len(d.Profits)))))]
+	return helpers.Sum(tail) / float64(len(tail))
+}
+
+// Evenly-sized bins from the lowest to the highest profit
+func (d *ProfitDistribution) Histogram(numberOfBins int) []HistogramBin {
+	if d.IsEmpty() || numberOfBins <= 0 {
+		return []HistogramBin{}
+	}
+	lowest := d.Profits[0]
+	highest := d.Profits[len(d.Profits)-1]
+	width := (highest - lowest) / float64(numberOfBins)
+	if width == 0 {
+		return []HistogramBin{{From: lowest, To: highest, Count: len(d.Profits)}}
+	}
+
+	bins := make([]HistogramBin, numberOfBins)
+	for j := range bins {
+		bins[j].From = lowest + float64(j)*width
+		bins[j].To = lowest + float64(j+1)*width
+	}
+	for _, profit := range d.Profits {
+		bins[min(int((profit-lowest)/width), numberOfBins-1)].Count++
+	}
+	return bins
+}
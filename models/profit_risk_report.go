@@ -0,0 +1,57 @@
+package models
+
+type PercentileProfit struct {
+	Percentile float64 `json:"percentile"`
+	Profit     float64 `json:"profit"`
+}
+
+// What a period of farming could make, beyond the mean
+type ProfitRisk struct {
+	Days        int                `json:"days"`
+	Mean        float64            `json:"mean"`
+	Median      float64            `json:"median"`
+	Percentiles []PercentileProfit `json:"percentiles"`
+	Threshold   float64            `json:"threshold"`
+	// The chance of making less than Threshold over the period
+	ProbabilityBelowThreshold float64 `json:"probabilityBelowThreshold"`
+	// The mean profit of the worst ShortfallLevel of periods
+	ExpectedShortfall float64        `json:"expectedShortfall"`
+	ShortfallLevel    float64        `json:"shortfallLevel"`
+	Histogram         []HistogramBin `json:"histogram"`
+}
+
+func NewProfitRisk(distribution *ProfitDistribution, percentiles []float64, threshold float64, shortfallLevel float64, numberOfBins int) ProfitRisk {
+	percentileProfits := make([]PercentileProfit, len(percentiles))
+	for i, p := range percentiles {
+		percentileProfits[i] = PercentileProfit{Percentile: p, Profit: distribution.Percentile(p)}
+	}
+	return ProfitRisk{
+		Days:                      distribution.Days,
+		Mean:                      distribution.Mean(),
+		Median:                    distribution.Median(),
+		Percentiles:               percentileProfits,
+		Threshold:                 threshold,
+		ProbabilityBelowThreshold: distribution.ProbabilityBelow(threshold),
+		ExpectedShortfall:         distribution.ExpectedShortfall(shortfallLevel),
+		ShortfallLevel:            shortfallLevel,
+		Histogram:                 distribution.Histogram(numberOfBins),
+	}
+}
+
+type ProfitRiskSummary struct {
+	Samples int `json:"samples"`
+	// The share of profit from the most valuable TopDropsFraction of drops
+	TopDropsProfitShare float64    `json:"topDropsProfitShare"`
+	TopDropsFraction    float64    `json:"topDropsFraction"`
+	Day                 ProfitRisk `json:"day"`
+	Season              ProfitRisk `json:"season"`
+}
+
+type ProfitRiskReport struct {
+	Valuation  string            `json:"valuation"`
+	Arena      Arena             `json:"arena"`
+	Challenger Challenger        `json:"challenger,omitempty"`
+	Difficulty Difficulty        `json:"difficulty,omitempty"`
+	Predicted  ProfitRiskSummary `json:"predicted"`
+	Actual     ProfitRiskSummary `json:"actual"`
+}
@@ -0,0 +1,38 @@
+package models
+
+// A fight that meets the recommendation constraints, with what it was ranked by
+type Recommendation struct {
+	Metadata BattledomeItemMetadata
+	Samples  int
+	// Mean profit of a day, with a bootstrap confidence interval for it whatever --bootstrap is
+	MeanProfit float64
+	LeftBound  float64
+	RightBound float64
+	// The mean shrunk towards the mean of every recorded fight, by less the more drops the fight has
+	PosteriorProfit float64
+	// Whichever of the above the fights were ranked by
+	Score float64
+	// The item that makes the most of the fight's profit, and how much of it
+	TopItem      ItemName
+	TopItemShare float64
+	// What it takes to be able to fight the challenger, from the catalogue; empty if nothing
+	Unlock string
+}
+
+type RecommendationReport struct {
+	// "mean", "lower" or "posterior"
+	Criterion string
+	// Best first, at most the size of the shortlist
+	Recommendations []Recommendation
+	// How many recorded fights met every constraint
+	Candidates int
+	// The mean profit the posteriors are shrunk towards
+	PriorProfit float64
+	// How many recorded fights each constraint ruled out; a fight only counts towards the first one it fails
+	UnbeatableChallenger int
+	LockedDifficulty     int
+	TooFewSamples        int
+	MinimumSamples       int
+	// Beatable challengers with no recorded drops, which are likely misspelt
+	UnknownChallengers []Challenger
+}
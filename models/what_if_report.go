@@ -0,0 +1,26 @@
+package models
+
+// Where a fight or arena ranks by mean profit at the real prices and at the scenario's
+type WhatIfRanking struct {
+	Metadata       BattledomeItemMetadata
+	Samples        int
+	BaselineRank   int
+	ScenarioRank   int
+	BaselineProfit float64
+	ScenarioProfit float64
+}
+
+// Positive if the scenario moves it up
+func (r WhatIfRanking) RankChange() int {
+	return r.BaselineRank - r.ScenarioRank
+}
+
+func (r WhatIfRanking) ProfitDelta() float64 {
+	return r.ScenarioProfit - r.BaselineProfit
+}
+
+type WhatIfReport struct {
+	// In scenario rank order
+	Arenas []WhatIfRanking
+	Fights []WhatIfRanking
+}
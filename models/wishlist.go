@@ -0,0 +1,11 @@
+package models
+
+// An item wanted in some quantity. If Name is an item group, every item in the group is wanted in that quantity, or
+// just that many of any of them if IsAny is set.
+type WishlistEntry struct {
+	Name     string
+	Quantity int
+	IsAny    bool
+}
+
+type Wishlist []WishlistEntry
@@ -6,8 +6,10 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
@@ -15,21 +17,51 @@ import (
 	"github.com/palantir/stacktrace"
 )
 
-type BattledomeItemDropDataParser struct{}
+// In strict mode every problem in a file is collected and returned as a *models.DropDataParseErrors instead of being
+// logged and skipped. Arenas, challengers and difficulties are checked against BattledomeCatalogue.
+type BattledomeItemDropDataParser struct {
+	IsStrict            bool
+	BattledomeCatalogue *helpers.BattledomeCatalogue
+}
 
-func NewBattledomeItemDropDataParser() *BattledomeItemDropDataParser {
-	return &BattledomeItemDropDataParser{}
+func NewBattledomeItemDropDataParser(battledomeCatalogue *helpers.BattledomeCatalogue) *BattledomeItemDropDataParser {
+	return &BattledomeItemDropDataParser{
+		BattledomeCatalogue: battledomeCatalogue,
+	}
+}
+
+func NewStrictBattledomeItemDropDataParser(battledomeCatalogue *helpers.BattledomeCatalogue) *BattledomeItemDropDataParser {
+	return &BattledomeItemDropDataParser{
+		IsStrict:            true,
+		BattledomeCatalogue: battledomeCatalogue,
+	}
 }
 
 var (
 	parsers = []DropDataLineParser{
+		new(BlankLineParser),
 		new(MetadataParser),
 		new(CommentParser),
 		new(ItemDataParser),
 	}
 )
 
+// Returned by line parsers to point at where on the line things went wrong
+type LineError struct {
+	Column  int
+	Message string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Column, e.Message)
+}
+
+// In strict mode, the returned DTO is still populated as far as possible alongside a *models.DropDataParseErrors so that
+// callers can report on it.
 func (p *BattledomeItemDropDataParser) Parse(filePath string) (*models.BattledomeItemsDto, error) {
+	if p.BattledomeCatalogue == nil {
+		return nil, fmt.Errorf("there is no battledome catalogue to check %q against", filePath)
+	}
 	if !helpers.IsFileExists(filePath) {
 		return nil, fmt.Errorf("file at %q does not exist", filePath)
 	}
@@ -46,45 +78,250 @@ func (p *BattledomeItemDropDataParser) Parse(filePath string) (*models.Battledom
 	}
 	dto.Metadata.Source = filepath.Base(filePath)
 
+	diagnostics := []models.DropDataDiagnostic{}
+	report := func(line int, column int, message string) {
+		diagnostic := models.DropDataDiagnostic{
+			File:    dto.Metadata.Source,
+			Line:    line,
+			Column:  column,
+			Message: message,
+		}
+		if !p.IsStrict {
+			slog.Warn(diagnostic.String())
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	// The line each metadata key was set on, per battle
+	metadataLineNumbers := []map[string]int{}
 	scanner := bufio.NewScanner(file)
+	lineNumber := 0
 	for scanner.Scan() {
-		line := scanner.Text()
+		lineNumber++
+		line := strings.TrimPrefix(scanner.Text(), "\uFEFF")
 		for _, parser := range parsers {
 			if !parser.IsApplicable(line) {
 				continue
 			}
 
-			parser.Parse(line, dto)
+			if err := parser.Parse(line, dto); err != nil {
+				lineErr, isLineError := err.(*LineError)
+				if isLineError {
+					report(lineNumber, lineErr.Column, lineErr.Message)
+				} else {
+					report(lineNumber, 1, err.Error())
+				}
+			} else if _, isMetadata := parser.(*MetadataParser); isMetadata && len(dto.Battles) > 0 {
+				for len(metadataLineNumbers) < len(dto.Battles) {
+					metadataLineNumbers = append(metadataLineNumbers, map[string]int{})
+				}
+				key, _, _ := strings.Cut(line, ":")
+				metadataLineNumbers[len(dto.Battles)-1][strings.ToLower(strings.TrimSpace(key))] = lineNumber
+			}
 			break
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read file: %s", filePath)
+	}
+
+	dto.InheritMetadata()
+	reportedCatalogueProblems := map[string]bool{}
+	for i, battle := range dto.Battles {
+		battleDescription := helpers.When(len(dto.Battles) > 1, fmt.Sprintf(" in battle %d", i+1), "")
+		if i == 0 {
+			for _, missing := range missingMetadataKeys(battle.Metadata) {
+				report(0, 0, fmt.Sprintf("missing %s%s", missing, battleDescription))
+			}
+		}
+		for _, problem := range uncataloguedMetadata(p.BattledomeCatalogue, battle.Metadata) {
+			// Inherited values would otherwise be reported again for every battle
+			if reportedCatalogueProblems[problem.message] {
+				continue
+			}
+			reportedCatalogueProblems[problem.message] = true
+			if i < len(metadataLineNumbers) && metadataLineNumbers[i][problem.key] != 0 {
+				report(metadataLineNumbers[i][problem.key], 1, problem.message)
+			} else {
+				report(0, 0, problem.message+battleDescription)
+			}
+		}
+		if len(dto.Battles) > 1 && len(battle.Items) == 0 {
+			report(0, 0, fmt.Sprintf("battle %d (%s) has no drops", i+1, battle.Metadata.String()))
+		}
+	}
+	if len(dto.Battles) == 0 {
+		for _, missing := range missingMetadataKeys(dto.Metadata.BattledomeItemMetadata) {
+			report(0, 0, fmt.Sprintf("missing %s", missing))
+		}
+	}
 
+	// The drop limit is per day, so it applies to the whole file rather than to each battle
 	itemCount := 0
 	for _, item := range dto.Items {
 		itemCount += int(item.Quantity)
 	}
 	if itemCount != constants.BattledomeDropsPerDay {
-		slog.Error(fmt.Sprintf("WARNING! The drop data in %q does not contain %d drops; %d drops were detected.", dto.Metadata.Source, constants.BattledomeDropsPerDay, itemCount))
+		if p.IsStrict {
+			report(0, 0, models.DropCountMessage(constants.BattledomeDropsPerDay, itemCount))
+		} else {
+			slog.Error(fmt.Sprintf("WARNING! The drop data in %q does not contain %d drops; %d drops were detected.", dto.Metadata.Source, constants.BattledomeDropsPerDay, itemCount))
 
-		// Zero out all the item quantities and return so that it doesn't pollute the dataset
-		for _, item := range dto.Items {
-			item.Quantity = 0
+			// Zero out all the item quantities and return so that it doesn't pollute the dataset
+			for _, item := range dto.Items {
+				item.Quantity = 0
+				item.AttributeTo(dto.Metadata.Contributor)
+			}
+		}
+	}
+
+	if p.IsStrict && len(diagnostics) > 0 {
+		return dto, &models.DropDataParseErrors{
+			Diagnostics: diagnostics,
 		}
 	}
 	return dto, nil
 }
 
+// Writes the battles in drop file format, after whatever is already in the file
+func (p *BattledomeItemDropDataParser) Append(filePath string, battles []*models.Battle) error {
+	lines := []string{}
+	for _, battle := range battles {
+		lines = append(lines, FormatBattle(battle)...)
+	}
+
+	prefix := ""
+	if helpers.IsFileExists(filePath) {
+		existing, err := os.ReadFile(filePath)
+		if err != nil {
+			return stacktrace.Propagate(err, "failed to read file: %s", filePath)
+		}
+		if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+			prefix = "\n"
+		}
+	}
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0755)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to open file: %s", filePath)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteString(prefix + strings.Join(lines, "\n") + "\n"); err != nil {
+		return stacktrace.Propagate(err, "failed to write battles to %s", filePath)
+	}
+	return nil
+}
+
+// Writes the whole file, replacing anything already there
+func (p *BattledomeItemDropDataParser) Save(dto *models.BattledomeItemsDto, filePath string) error {
+	lines := []string{}
+	if dto.Metadata.Contributor != "" {
+		lines = append(lines, fmt.Sprintf("%s:%s", strings.ToUpper(CONTRIBUTOR_KEY), dto.Metadata.Contributor))
+	}
+	lines = append(lines, dto.Comments...)
+	for _, battle := range dto.Battles {
+		lines = append(lines, FormatBattle(battle)...)
+	}
+
+	if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")+"\n"), 0755); err != nil {
+		return stacktrace.Propagate(err, "failed to write drop data to %s", filePath)
+	}
+	return nil
+}
+
+// The lines for one battle, with its items and comments in the order they were read
+func FormatBattle(battle *models.Battle) []string {
+	lines := []string{
+		fmt.Sprintf("%s:%s", strings.ToUpper(ARENA_KEY), battle.Metadata.Arena),
+		fmt.Sprintf("%s:%s", strings.ToUpper(CHALLENGER_KEY), battle.Metadata.Challenger),
+		fmt.Sprintf("%s:%s", strings.ToUpper(DIFFICULTY_KEY), battle.Metadata.Difficulty),
+	}
+	if timeText := battle.TimeText(); timeText != "" {
+		lines = append(lines, fmt.Sprintf("%s:%s", strings.ToUpper(TIME_KEY), timeText))
+	}
+	if battle.Wins > 0 {
+		lines = append(lines, fmt.Sprintf("%s:%d", strings.ToUpper(WINS_KEY), battle.Wins))
+	}
+
+	comments := battle.Comments
+	for i := 0; i <= len(battle.Items); i++ {
+		for len(comments) > 0 && comments[0].ItemsBefore <= i {
+			lines = append(lines, comments[0].Line)
+			comments = comments[1:]
+		}
+		if i < len(battle.Items) {
+			lines = append(lines, fmt.Sprintf("%s|%d", battle.Items[i].Name, battle.Items[i].Quantity))
+		}
+	}
+	return lines
+}
+
+type uncataloguedMetadataProblem struct {
+	key     string
+	message string
+}
+
+func suggestion(name string, candidates []string) string {
+	suggestions := helpers.ClosestMatches(name, candidates, 4)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("; did you mean %q?", suggestions[0])
+}
+
+// Metadata that isn't in the battledome catalogue
+func uncataloguedMetadata(catalogue *helpers.BattledomeCatalogue, metadata models.BattledomeItemMetadata) []uncataloguedMetadataProblem {
+	if metadata.Arena == "" {
+		return nil
+	}
+	if _, exists := catalogue.Arena(string(metadata.Arena)); !exists {
+		arenas := catalogue.ArenaNames()
+		return []uncataloguedMetadataProblem{{ARENA_KEY, fmt.Sprintf("unknown arena %q%s", metadata.Arena, suggestion(string(metadata.Arena), arenas))}}
+	}
+	if metadata.Challenger == "" {
+		return nil
+	}
+	if _, exists := catalogue.Challenger(string(metadata.Arena), string(metadata.Challenger)); !exists {
+		challengers := catalogue.ChallengerNames(string(metadata.Arena))
+		return []uncataloguedMetadataProblem{{CHALLENGER_KEY, fmt.Sprintf("challenger %q is not in the catalogue for %s%s", metadata.Challenger, metadata.Arena, suggestion(string(metadata.Challenger), challengers))}}
+	}
+	difficulties := catalogue.DifficultyNames(string(metadata.Arena), string(metadata.Challenger))
+	if metadata.Difficulty != "" && !slices.Contains(difficulties, string(metadata.Difficulty)) {
+		return []uncataloguedMetadataProblem{{DIFFICULTY_KEY, fmt.Sprintf("%s can't be fought at difficulty %q; expected one of %s%s", metadata.Challenger, metadata.Difficulty, strings.Join(difficulties, ", "), suggestion(string(metadata.Difficulty), difficulties))}}
+	}
+	return nil
+}
+
+func missingMetadataKeys(metadata models.BattledomeItemMetadata) []string {
+	missing := []string{}
+	if metadata.Arena == "" {
+		missing = append(missing, strings.ToUpper(ARENA_KEY))
+	}
+	if metadata.Challenger == "" {
+		missing = append(missing, strings.ToUpper(CHALLENGER_KEY))
+	}
+	if metadata.Difficulty == "" {
+		missing = append(missing, strings.ToUpper(DIFFICULTY_KEY))
+	}
+	return missing
+}
+
 type DropDataLineParser interface {
 	IsApplicable(line string) bool
 	Parse(line string, items *models.BattledomeItemsDto) error
 }
 
 const (
-	ARENA_KEY      = "$arena"
-	CHALLENGER_KEY = "$challenger"
-	DIFFICULTY_KEY = "$difficulty"
+	ARENA_KEY       = "$arena"
+	CHALLENGER_KEY  = "$challenger"
+	DIFFICULTY_KEY  = "$difficulty"
+	TIME_KEY        = "$time"
+	WINS_KEY        = "$wins"
+	CONTRIBUTOR_KEY = "$contributor"
 )
 
+// A metadata line after a battle's drops starts a new battle, so one file can hold several
 type MetadataParser struct{}
 
 func (p *MetadataParser) IsApplicable(line string) bool {
@@ -92,26 +329,90 @@ func (p *MetadataParser) IsApplicable(line string) bool {
 }
 
 func (p *MetadataParser) Parse(line string, dto *models.BattledomeItemsDto) error {
-	tokens := strings.Split(line, ":")
-	metadata := dto.Metadata.Copy()
-	metadataKey := strings.ToLower(strings.TrimSpace(tokens[0]))
-	metadataValue := strings.TrimSpace(tokens[1])
+	separatorIndex := strings.Index(line, ":")
+	if separatorIndex == -1 {
+		return &LineError{Column: len(line) + 1, Message: fmt.Sprintf("expected \"$KEY:value\" but there was no \":\" in %q", line)}
+	}
+	metadataKey := strings.ToLower(strings.TrimSpace(line[:separatorIndex]))
+	metadataValue := strings.TrimSpace(line[separatorIndex+1:])
+	valueColumn := separatorIndex + 2
+	if metadataValue == "" {
+		return &LineError{Column: valueColumn, Message: fmt.Sprintf("%s has no value", strings.ToUpper(metadataKey))}
+	}
+
+	// A repeated key still takes the later value, as it always has, so lenient parsing keeps reading files the same way
+	duplicateError := func(previousValue string) error {
+		if previousValue == "" {
+			return nil
+		}
+		return &LineError{Column: 1, Message: fmt.Sprintf("duplicate %s; it was already set to %q", strings.ToUpper(metadataKey), previousValue)}
+	}
+
+	// The contributor applies to the whole file rather than to a battle
+	if metadataKey == CONTRIBUTOR_KEY {
+		previousContributor := dto.Metadata.Contributor
+		dto.Metadata.Contributor = metadataValue
+		return duplicateError(previousContributor)
+	}
+
+	battle := dto.CurrentBattle()
+	if len(battle.Items) > 0 {
+		battle = dto.StartBattle()
+	}
+	metadata := &battle.Metadata
 
 	switch key := metadataKey; key {
 	case ARENA_KEY:
+		previousArena := metadata.Arena
 		slog.Debug(fmt.Sprintf("Set Arena to %q", metadataValue))
 		metadata.Arena = models.Arena(metadataValue)
+		return duplicateError(string(previousArena))
 	case CHALLENGER_KEY:
+		previousChallenger := metadata.Challenger
 		slog.Debug(fmt.Sprintf("Set Challenger to %q", metadataValue))
 		metadata.Challenger = models.Challenger(metadataValue)
+		return duplicateError(string(previousChallenger))
 	case DIFFICULTY_KEY:
+		previousDifficulty := metadata.Difficulty
 		slog.Debug(fmt.Sprintf("Set Difficulty to %q", metadataValue))
 		metadata.Difficulty = models.Difficulty(metadataValue)
+		return duplicateError(string(previousDifficulty))
+	case TIME_KEY:
+		timestamp, err := models.ParseBattleTimestamp(metadataValue, dto.Metadata.Source)
+		if err != nil {
+			return &LineError{Column: valueColumn, Message: err.Error()}
+		}
+		previousTimestamp := battle.Timestamp
+		battle.Timestamp = timestamp
+		battle.RawTime = metadataValue
+		if !previousTimestamp.IsZero() {
+			return duplicateError(previousTimestamp.Format(time.DateTime))
+		}
+	case WINS_KEY:
+		wins, err := strconv.Atoi(metadataValue)
+		if err != nil || wins <= 0 {
+			return &LineError{Column: valueColumn, Message: fmt.Sprintf("%s must be a positive integer but was %q", strings.ToUpper(metadataKey), metadataValue)}
+		}
+		previousWins := battle.Wins
+		battle.Wins = wins
+		if previousWins != 0 {
+			return duplicateError(strconv.Itoa(previousWins))
+		}
 	default:
-		slog.Warn(fmt.Sprintf("Encountered an unrecognised metadata key while parsing drop data; the unrecognised key was %q", metadataKey))
+		return &LineError{Column: 1, Message: fmt.Sprintf("unrecognised metadata key %q", metadataKey)}
 	}
 
-	dto.Metadata = *metadata
+	return nil
+}
+
+type BlankLineParser struct{}
+
+func (p *BlankLineParser) IsApplicable(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func (p *BlankLineParser) Parse(line string, dto *models.BattledomeItemsDto) error {
+	keepComment(line, dto)
 	return nil
 }
 
@@ -122,9 +423,24 @@ func (p *CommentParser) IsApplicable(line string) bool {
 }
 
 func (p *CommentParser) Parse(line string, dto *models.BattledomeItemsDto) error {
+	keepComment(line, dto)
 	return nil
 }
 
+// Lines before the first battle belong to the file; after that they belong to the battle being read, which only ends
+// at the next battle's metadata
+func keepComment(line string, dto *models.BattledomeItemsDto) {
+	if len(dto.Battles) == 0 {
+		dto.Comments = append(dto.Comments, line)
+		return
+	}
+	battle := dto.CurrentBattle()
+	battle.Comments = append(battle.Comments, models.DropFileComment{
+		Line:        line,
+		ItemsBefore: len(battle.Items),
+	})
+}
+
 type ItemDataParser struct{}
 
 func (p *ItemDataParser) IsApplicable(line string) bool {
@@ -132,18 +448,34 @@ func (p *ItemDataParser) IsApplicable(line string) bool {
 }
 
 func (p *ItemDataParser) Parse(line string, dto *models.BattledomeItemsDto) error {
-	tokens := strings.Split(line, "|")
-	itemName := models.ItemName(strings.TrimSpace(tokens[0]))
-	itemQuantity, err := strconv.ParseInt(strings.TrimSpace(tokens[1]), 0, 32)
+	separatorIndex := strings.LastIndex(line, "|")
+	if separatorIndex == -1 {
+		return &LineError{Column: len(line) + 1, Message: fmt.Sprintf("expected \"Item Name|Quantity\" but there was no \"|\" in %q", line)}
+	}
+
+	itemName := models.ItemName(helpers.ItemAliasesInstance().Canonical(line[:separatorIndex]))
+	if itemName == "" {
+		return &LineError{Column: 1, Message: "item name is empty"}
+	}
+
+	rawQuantity := line[separatorIndex+1:]
+	quantityColumn := separatorIndex + 2 + len(rawQuantity) - len(strings.TrimLeft(rawQuantity, " \t"))
+	itemQuantity, err := strconv.ParseInt(strings.TrimSpace(rawQuantity), 0, 32)
 	if err != nil {
-		return stacktrace.Propagate(err, "failed to parse %q as integer", strings.TrimSpace(tokens[1]))
+		return &LineError{Column: quantityColumn, Message: fmt.Sprintf("failed to parse %q as integer", strings.TrimSpace(rawQuantity))}
+	}
+	if itemQuantity <= 0 {
+		return &LineError{Column: quantityColumn, Message: fmt.Sprintf("quantity of %q must be positive but was %d", itemName, itemQuantity)}
 	}
 
-	dto.Items = append(dto.Items, &models.BattledomeItem{
-		Metadata: dto.Metadata.BattledomeItemMetadata,
+	// Metadata is filled in once the whole file has been read, as later battles can inherit it
+	item := &models.BattledomeItem{
 		Name:     itemName,
 		Quantity: int32(itemQuantity),
-	})
+	}
+	battle := dto.CurrentBattle()
+	battle.Items = append(battle.Items, item)
+	dto.Items = append(dto.Items, item)
 
 	return nil
 }
@@ -1,13 +1,26 @@
 package parsers
 
 import (
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
 )
 
+func testBattledomeCatalogue(t *testing.T) *helpers.BattledomeCatalogue {
+	catalogue, err := helpers.NewBattledomeCatalogue(constants.BattledomeCatalogueFilePath())
+	if err != nil {
+		t.Fatalf("Failed to load the battledome catalogue: %s", err)
+	}
+	return catalogue
+}
+
 func shouldHaveItemAndQuantity(normalisedItems models.NormalisedBattledomeItems, t *testing.T, itemName string, itemQuantity int32) {
 	battledomeItem, isInItems := normalisedItems[models.ItemName(itemName)]
 	if !isInItems {
@@ -20,7 +33,7 @@ func shouldHaveItemAndQuantity(normalisedItems models.NormalisedBattledomeItems,
 }
 
 func TestDropDataParser(t *testing.T) {
-	target := NewBattledomeItemDropDataParser()
+	target := NewBattledomeItemDropDataParser(testBattledomeCatalogue(t))
 	dto, err := target.Parse(constants.DropDataFilePath("2024_12_20.txt"))
 	if err != nil {
 		t.Fatalf("Failed to parse file: %s", err)
@@ -55,3 +68,189 @@ func TestDropDataParser(t *testing.T) {
 	shouldHaveItemAndQuantity(normalisedItems, t, "Eo Codestone", 1)
 	shouldHaveItemAndQuantity(normalisedItems, t, "Robot Muffin", 1)
 }
+
+func writeDropDataFile(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "drops.txt")
+	if err := os.WriteFile(filePath, []byte(content), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+	return filePath
+}
+
+func TestStrictDropDataParserCollectsDiagnostics(t *testing.T) {
+	filePath := writeDropDataFile(t, strings.Join([]string{
+		"$ARENA:Central Arena",
+		"$ARENA:Frost Arena",
+		"$CHALENGER:Flaming Meerca",
+		"$DIFFICULTY",
+		"",
+		"Har Codestone|2",
+		"Robot Muffin",
+		"Orn Codestone| 0",
+		"Bri Codestone|x",
+	}, "\n"))
+
+	dto, err := NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)).Parse(filePath)
+	parseErrors, ok := err.(*models.DropDataParseErrors)
+	if !ok {
+		t.Fatalf("Expected a *models.DropDataParseErrors, but got %T: %v", err, err)
+	}
+	if dto == nil || len(dto.Items) != 1 {
+		t.Fatalf("Expected the one valid item to still be parsed")
+	}
+
+	expected := []string{
+		"drops.txt:2:1: duplicate $ARENA; it was already set to \"Central Arena\"",
+		"drops.txt:3:1: unrecognised metadata key \"$chalenger\"",
+		"drops.txt:4:12: expected \"$KEY:value\" but there was no \":\" in \"$DIFFICULTY\"",
+		"drops.txt:7:13: expected \"Item Name|Quantity\" but there was no \"|\" in \"Robot Muffin\"",
+		"drops.txt:8:16: quantity of \"Orn Codestone\" must be positive but was 0",
+		"drops.txt:9:15: failed to parse \"x\" as integer",
+		"drops.txt: missing $CHALLENGER",
+		"drops.txt: missing $DIFFICULTY",
+		"drops.txt: expected 15 drops but found 2",
+	}
+	actual := helpers.Map(parseErrors.Diagnostics, func(diagnostic models.DropDataDiagnostic) string {
+		return diagnostic.String()
+	})
+	if !slices.Equal(expected, actual) {
+		t.Fatalf("Diagnostics did not match:\n\tExpected: %q\n\tReceived: %q", expected, actual)
+	}
+}
+
+func TestStrictDropDataParserChecksCatalogue(t *testing.T) {
+	filePath := writeDropDataFile(t, strings.Join([]string{
+		"$ARENA:Central Arena",
+		"$CHALLENGER:Flaming Meerka",
+		"$DIFFICULTY:Mighty",
+		"Har Codestone|15",
+	}, "\n"))
+
+	_, err := NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)).Parse(filePath)
+	parseErrors, ok := err.(*models.DropDataParseErrors)
+	if !ok {
+		t.Fatalf("Expected a *models.DropDataParseErrors, but got %T: %v", err, err)
+	}
+	expected := []string{
+		"drops.txt:2:1: challenger \"Flaming Meerka\" is not in the catalogue for Central Arena; did you mean \"Flaming Meerca\"?",
+	}
+	actual := helpers.Map(parseErrors.Diagnostics, func(diagnostic models.DropDataDiagnostic) string {
+		return diagnostic.String()
+	})
+	if !slices.Equal(expected, actual) {
+		t.Fatalf("Diagnostics did not match:\n\tExpected: %q\n\tReceived: %q", expected, actual)
+	}
+}
+
+func TestLenientDropDataParserSkipsMalformedLines(t *testing.T) {
+	filePath := writeDropDataFile(t, "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\n\nRobot Muffin\nHar Codestone|15\n")
+
+	dto, err := NewBattledomeItemDropDataParser(testBattledomeCatalogue(t)).Parse(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(dto.Items) != 1 || dto.Items[0].Quantity != 15 {
+		t.Fatalf("Expected only the well-formed item line to be parsed, but got %d items", len(dto.Items))
+	}
+}
+
+func TestDropDataParserSplitsBattles(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "2025_01_02.txt")
+	content := strings.Join([]string{
+		"$ARENA:Central Arena",
+		"$CHALLENGER:Flaming Meerca",
+		"$DIFFICULTY:Mighty",
+		"$TIME:09:30",
+		"$WINS:3",
+		"Har Codestone|9",
+		"$CHALLENGER:Kasuki Lu",
+		"Robot Muffin|6",
+	}, "\n")
+	if err := os.WriteFile(filePath, []byte(content), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	dto, err := NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)).Parse(filePath)
+	if err != nil {
+		t.Fatalf("Expected 15 drops split across two battles to be valid, but got: %s", err)
+	}
+	if len(dto.Battles) != 2 {
+		t.Fatalf("Expected 2 battles, but got %d", len(dto.Battles))
+	}
+
+	first, second := dto.Battles[0], dto.Battles[1]
+	if first.Wins != 3 || !first.Timestamp.Equal(time.Date(2025, 1, 2, 9, 30, 0, 0, time.Local)) {
+		t.Fatalf("Expected the first battle to have 3 wins at 2025-01-02 09:30, but got %d at %s", first.Wins, first.Timestamp)
+	}
+	expectedMetadata := models.BattledomeItemMetadata{
+		Arena:      "Central Arena",
+		Challenger: "Kasuki Lu",
+		Difficulty: "Mighty",
+	}
+	if second.Metadata != expectedMetadata || second.Items[0].Metadata != expectedMetadata {
+		t.Fatalf("Expected the second battle to inherit its arena and difficulty, but got %s", second.Metadata.String())
+	}
+	if first.Items[0].Metadata.Challenger != "Flaming Meerca" {
+		t.Fatalf("Expected the first battle's drops to keep their own challenger")
+	}
+}
+
+func TestDropDataParserAttributesContributor(t *testing.T) {
+	folderPath := t.TempDir()
+	files := map[string]string{
+		"2025_01_01.txt": "$CONTRIBUTOR:alice\n$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nHar Codestone|15\n",
+		"2025_01_02.txt": "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nHar Codestone|10\nRobot Muffin|5\n",
+	}
+	items := models.BattledomeItems{}
+	for fileName, content := range files {
+		filePath := filepath.Join(folderPath, fileName)
+		if err := os.WriteFile(filePath, []byte(content), 0755); err != nil {
+			t.Fatalf("%s", err)
+		}
+		dto, err := NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)).Parse(filePath)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		items = append(items, dto.Items...)
+	}
+
+	normalisedItems, err := items.Normalise()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	samples := normalisedItems.SamplesByContributor()
+	if len(samples) != 2 || samples["alice"] != 15 || samples[models.UnattributedContributor] != 15 {
+		t.Fatalf("Expected 15 samples each from alice and unattributed files, but got %v", samples)
+	}
+	if normalisedItems["Har Codestone"].Contributors["alice"] != 15 {
+		t.Fatalf("Expected alice's Har Codestones to be kept apart after normalising, but got %v", normalisedItems["Har Codestone"].Contributors)
+	}
+}
+
+func TestLenientDropDataParserKeepsTheLastDuplicateMetadata(t *testing.T) {
+	filePath := writeDropDataFile(t, strings.Join([]string{
+		"$ARENA:Central Arena",
+		"$ARENA:Frost Arena",
+		"$CHALLENGER:The Snowager",
+		"$DIFFICULTY:Average",
+		"Robot Muffin|15",
+	}, "\n"))
+
+	dto, err := NewBattledomeItemDropDataParser(testBattledomeCatalogue(t)).Parse(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if dto.Metadata.Arena != "Frost Arena" {
+		t.Fatalf("Expected the later $ARENA to win, but got %q", dto.Metadata.Arena)
+	}
+}
+
+func TestDropDataParserNeedsACatalogue(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "2025_01_01.txt")
+	if err := os.WriteFile(filePath, []byte("$ARENA:Central Arena\nRobot Muffin|15\n"), 0644); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, err := NewStrictBattledomeItemDropDataParser(nil).Parse(filePath); err == nil {
+		t.Fatalf("Expected an error rather than a panic when there is no catalogue")
+	}
+}
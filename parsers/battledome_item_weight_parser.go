@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,12 +19,16 @@ func NewBattledomeItemWeightParser() *BattledomeItemWeightParser {
 	return &BattledomeItemWeightParser{}
 }
 
+const poolSeparator = ">"
+
+// Each section starts with the pool its items belong to: an arena, or "Arena > Challenger" or
+// "Arena > Challenger > Difficulty" for a challenger's own prizes. Lines starting with # are ignored.
 func (p *BattledomeItemWeightParser) Parse(filePath string) ([]models.BattledomeItemWeight, error) {
 	if !helpers.IsFileExists(filePath) {
 		return nil, fmt.Errorf("item weights file does not exist: %s", filePath)
 	}
 
-	currentArena := ""
+	currentPool := []string{}
 	weights := []models.BattledomeItemWeight{}
 	file, err := os.OpenFile(filePath, os.O_RDONLY, 0755)
 	if err != nil {
@@ -33,11 +38,11 @@ func (p *BattledomeItemWeightParser) Parse(filePath string) ([]models.Battledome
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if strings.TrimSpace(line) == "" {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
 			continue
-		} else if strings.Contains(line, "-") {
+		} else if strings.Contains(line, " - ") {
 			// It's an item weight
-			if currentArena == "" {
+			if len(currentPool) == 0 {
 				return nil, fmt.Errorf("read an item weight before an arena was read! The offending line was %q", line)
 			}
 			tokens := strings.Split(line, " - ")
@@ -49,12 +54,20 @@ func (p *BattledomeItemWeightParser) Parse(filePath string) ([]models.Battledome
 			}
 			itemWeight := parsedItemWeight / 100
 			weights = append(weights, models.BattledomeItemWeight{
-				Arena:  currentArena,
-				Name:   itemName,
-				Weight: itemWeight,
+				Arena:      currentPool[0],
+				Challenger: currentPool[1],
+				Difficulty: currentPool[2],
+				Name:       itemName,
+				Weight:     itemWeight,
 			})
 		} else {
-			currentArena = strings.TrimSpace(line)
+			currentPool = helpers.Map(strings.Split(line, poolSeparator), strings.TrimSpace)
+			if len(currentPool) > 3 || slices.Contains(currentPool, "") {
+				return nil, fmt.Errorf("expected an arena, \"Arena > Challenger\" or \"Arena > Challenger > Difficulty\" but read %q", line)
+			}
+			for len(currentPool) < 3 {
+				currentPool = append(currentPool, "")
+			}
 		}
 	}
 	return weights, nil
@@ -1,6 +1,10 @@
 package parsers
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/darienchong/neopets-battledome-analysis/constants"
@@ -27,3 +31,34 @@ func TestBattledomeItemWeightsParser(t *testing.T) {
 		t.Fatalf("Weak Bottled Earth Faerie's weight was not correctly parsed!\nExpected: 0.015\nReceived:%f", weakBottledEarthFaerieWeight)
 	}
 }
+
+func TestBattledomeItemWeightsParserReadsChallengerPools(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "weights.txt")
+	content := strings.Join([]string{
+		"# Comments are ignored",
+		"Central Arena",
+		"Orn Codestone - 2 %",
+		"",
+		"Central Arena > Flaming Meerca",
+		"Meerca Plushie - 5 %",
+		"",
+		"Central Arena > Flaming Meerca > Mighty",
+		"Half-Eaten Meerca Cake - 1 %",
+	}, "\n")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write weights file: %s", err)
+	}
+
+	itemWeights, err := NewBattledomeItemWeightParser().Parse(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	expected := []models.BattledomeItemWeight{
+		{Arena: "Central Arena", Name: "Orn Codestone", Weight: 0.02},
+		{Arena: "Central Arena", Challenger: "Flaming Meerca", Name: "Meerca Plushie", Weight: 0.05},
+		{Arena: "Central Arena", Challenger: "Flaming Meerca", Difficulty: "Mighty", Name: "Half-Eaten Meerca Cake", Weight: 0.01},
+	}
+	if !slices.Equal(itemWeights, expected) {
+		t.Fatalf("Challenger pools were not correctly parsed!\nExpected: %v\nReceived: %v", expected, itemWeights)
+	}
+}
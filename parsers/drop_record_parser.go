@@ -0,0 +1,203 @@
+package parsers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+var (
+	// In the order they're written; when reading, columns are matched by header so their order doesn't matter
+	DropRecordCsvColumns = []string{
+		"date",
+		"battle",
+		"time",
+		"wins",
+		"arena",
+		"challenger",
+		"difficulty",
+		"item",
+		"quantity",
+		"contributor",
+		"comment",
+	}
+	requiredDropRecordCsvColumns = []string{
+		"date",
+		"arena",
+		"challenger",
+		"difficulty",
+		"item",
+		"quantity",
+	}
+)
+
+func canonicaliseRecords(records []models.DropRecord) []models.DropRecord {
+	for i := range records {
+		records[i].Item = helpers.ItemAliasesInstance().Canonical(records[i].Item)
+	}
+	return records
+}
+
+type DropRecordJsonParser struct{}
+
+func NewDropRecordJsonParser() *DropRecordJsonParser {
+	return &DropRecordJsonParser{}
+}
+
+func (p *DropRecordJsonParser) Parse(filePath string) ([]models.DropRecord, error) {
+	if !helpers.IsFileExists(filePath) {
+		return nil, fmt.Errorf("file at %q does not exist", filePath)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read file: %s", filePath)
+	}
+
+	records := models.DropRecords{}
+	if err = json.Unmarshal(content, &records); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse %s as JSON drop records", filePath)
+	}
+	if records.Version > models.DropRecordsVersion {
+		return nil, fmt.Errorf("%s is version %d of the drop records format, but only up to version %d is understood", filePath, records.Version, models.DropRecordsVersion)
+	}
+	return canonicaliseRecords(records.Drops), nil
+}
+
+func (p *DropRecordJsonParser) Save(records []models.DropRecord, filePath string) error {
+	content, err := json.MarshalIndent(models.DropRecords{
+		Version: models.DropRecordsVersion,
+		Drops:   records,
+	}, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to serialise drop records")
+	}
+	if err = os.WriteFile(filePath, append(content, '\n'), 0755); err != nil {
+		return stacktrace.Propagate(err, "failed to write drop records to %s", filePath)
+	}
+	return nil
+}
+
+type DropRecordCsvParser struct{}
+
+func NewDropRecordCsvParser() *DropRecordCsvParser {
+	return &DropRecordCsvParser{}
+}
+
+func (p *DropRecordCsvParser) Parse(filePath string) ([]models.DropRecord, error) {
+	if !helpers.IsFileExists(filePath) {
+		return nil, fmt.Errorf("file at %q does not exist", filePath)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open file: %s", filePath)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse %s as CSV", filePath)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s is empty; expected a header row", filePath)
+	}
+
+	columnIndices := map[string]int{}
+	for i, header := range rows[0] {
+		columnIndices[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\uFEFF")))] = i
+	}
+	for _, column := range requiredDropRecordCsvColumns {
+		if _, exists := columnIndices[column]; !exists {
+			return nil, fmt.Errorf("%s has no %q column; the columns are %s", filePath, column, strings.Join(DropRecordCsvColumns, ","))
+		}
+	}
+
+	records := []models.DropRecord{}
+	for i, row := range rows[1:] {
+		if slices.IndexFunc(row, func(cell string) bool { return strings.TrimSpace(cell) != "" }) == -1 {
+			continue
+		}
+
+		rowNumber := i + 2
+		value := func(column string) string {
+			index, exists := columnIndices[column]
+			if !exists || index >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[index])
+		}
+		integer := func(column string) (int, error) {
+			if value(column) == "" {
+				return 0, nil
+			}
+			parsed, err := strconv.Atoi(value(column))
+			if err != nil {
+				return 0, fmt.Errorf("%s:%d: %s %q is not an integer", filePath, rowNumber, column, value(column))
+			}
+			return parsed, nil
+		}
+
+		record := models.DropRecord{
+			Date:        value("date"),
+			Time:        value("time"),
+			Arena:       value("arena"),
+			Challenger:  value("challenger"),
+			Difficulty:  value("difficulty"),
+			Item:        value("item"),
+			Contributor: value("contributor"),
+			Comment:     value("comment"),
+		}
+		if record.Battle, err = integer("battle"); err != nil {
+			return nil, err
+		}
+		if record.Wins, err = integer("wins"); err != nil {
+			return nil, err
+		}
+		if record.Quantity, err = integer("quantity"); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return canonicaliseRecords(records), nil
+}
+
+func (p *DropRecordCsvParser) Save(records []models.DropRecord, filePath string) error {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to open file: %s", filePath)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	rows := [][]string{DropRecordCsvColumns}
+	for _, record := range records {
+		rows = append(rows, []string{
+			record.Date,
+			strconv.Itoa(record.Battle),
+			record.Time,
+			helpers.When(record.Wins == 0, "", strconv.Itoa(record.Wins)),
+			record.Arena,
+			record.Challenger,
+			record.Difficulty,
+			record.Item,
+			helpers.When(record.Item == "", "", strconv.Itoa(record.Quantity)),
+			record.Contributor,
+			record.Comment,
+		})
+	}
+	if err = writer.WriteAll(rows); err != nil {
+		return stacktrace.Propagate(err, "failed to write drop records to %s", filePath)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package parsers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+func TestDropRecordsRoundTrip(t *testing.T) {
+	original := strings.Join([]string{
+		"$CONTRIBUTOR:someone",
+		"# Morning",
+		"",
+		"$ARENA:Central Arena",
+		"$CHALLENGER:Flaming Meerca",
+		"$DIFFICULTY:Mighty",
+		"$TIME:09:30",
+		"$WINS:3",
+		"# Before the drops",
+		"Har Codestone|4",
+		"Har Codestone|5",
+		"",
+		"$ARENA:Central Arena",
+		"$CHALLENGER:Flaming Meerca",
+		"$DIFFICULTY:Mighty",
+		"$TIME:2025-01-02 18:00:00",
+		"Robot Muffin|6",
+		"# The end",
+	}, "\n") + "\n"
+
+	folderPath := t.TempDir()
+	filePath := filepath.Join(folderPath, "2025_01_02.txt")
+	if err := os.WriteFile(filePath, []byte(original), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	dropDataParser := NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t))
+	dto, err := dropDataParser.Parse(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	records, err := dto.Records()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	recordParsers := map[string]interface {
+		Parse(filePath string) ([]models.DropRecord, error)
+		Save(records []models.DropRecord, filePath string) error
+	}{
+		"drops.json": NewDropRecordJsonParser(),
+		"drops.csv":  NewDropRecordCsvParser(),
+	}
+	for fileName, recordParser := range recordParsers {
+		exportFilePath := filepath.Join(folderPath, fileName)
+		if err := recordParser.Save(records, exportFilePath); err != nil {
+			t.Fatalf("%s", err)
+		}
+		imported, err := recordParser.Parse(exportFilePath)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if !slices.Equal(records, imported) {
+			t.Fatalf("Records did not survive a round trip through %s:\n\tExpected: %v\n\tReceived: %v", fileName, records, imported)
+		}
+
+		dtos, err := models.DtosFromRecords(imported)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if len(dtos) != 1 {
+			t.Fatalf("Expected one day of drops, but got %d", len(dtos))
+		}
+		roundTripFilePath := filepath.Join(t.TempDir(), dtos[0].Metadata.Source)
+		if err := dropDataParser.Save(dtos[0], roundTripFilePath); err != nil {
+			t.Fatalf("%s", err)
+		}
+		roundTripped, err := os.ReadFile(roundTripFilePath)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if string(roundTripped) != original {
+			t.Fatalf("Drop file did not survive a round trip through %s:\n\tExpected: %q\n\tReceived: %q", fileName, original, string(roundTripped))
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
@@ -18,12 +19,36 @@ func NewGeneratedBattledomeItemParser() *GeneratedBattledomeItemParser {
 	return &GeneratedBattledomeItemParser{}
 }
 
-func (p *GeneratedBattledomeItemParser) Save(items models.NormalisedBattledomeItems, filePath string) error {
+const (
+	provenanceArenaKey            = "arena"
+	provenanceChallengerKey       = "challenger"
+	provenanceDifficultyKey       = "difficulty"
+	provenanceWeightsHashKey      = "weights"
+	provenanceGeneratorVersionKey = "generator"
+	provenanceSeedKey             = "seed"
+	provenanceCountKey            = "count"
+	provenanceGeneratedAtKey      = "generated"
+)
+
+// Writes the provenance as "# key: value" header lines, followed by one "Arena|Item|Quantity" line per item
+func (p *GeneratedBattledomeItemParser) Save(items models.NormalisedBattledomeItems, provenance models.GeneratedDropsProvenance, filePath string) error {
 	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
 	if err != nil {
 		return stacktrace.Propagate(err, "failed to open file: %q", filePath)
 	}
 	defer file.Close()
+	for _, header := range [][]string{
+		{provenanceArenaKey, string(provenance.Pool.Arena)},
+		{provenanceChallengerKey, string(provenance.Pool.Challenger)},
+		{provenanceDifficultyKey, string(provenance.Pool.Difficulty)},
+		{provenanceWeightsHashKey, provenance.WeightsHash},
+		{provenanceGeneratorVersionKey, strconv.Itoa(provenance.GeneratorVersion)},
+		{provenanceSeedKey, strconv.FormatUint(provenance.Seed, 10)},
+		{provenanceCountKey, strconv.Itoa(provenance.Count)},
+		{provenanceGeneratedAtKey, provenance.GeneratedAt.Format(time.RFC3339)},
+	} {
+		file.WriteString(fmt.Sprintf("# %s: %s\n", header[0], header[1]))
+	}
 	for _, item := range items {
 		file.WriteString(fmt.Sprintf("%s|%s|%d\n", item.Metadata.Arena, item.Name, item.Quantity))
 	}
@@ -45,10 +70,13 @@ func (p *GeneratedBattledomeItemParser) Parse(filePath string) (models.Normalise
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
 		tokens := strings.Split(line, "|")
 		arena := models.Arena(strings.TrimSpace(tokens[0]))
 		metadata := *models.GeneratedMetadata(arena)
-		itemName := models.ItemName(strings.TrimSpace(tokens[1]))
+		itemName := models.ItemName(helpers.ItemAliasesInstance().Canonical(tokens[1]))
 		itemQuantity, err := strconv.ParseInt(strings.TrimSpace(tokens[2]), 0, 32)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "failed to parse %s as int", strings.TrimSpace(tokens[2]))
@@ -69,3 +97,53 @@ func (p *GeneratedBattledomeItemParser) Parse(filePath string) (models.Normalise
 
 	return items, nil
 }
+
+// The provenance in the file's header, or nil if it was generated before provenance was recorded
+func (p *GeneratedBattledomeItemParser) Provenance(filePath string) (*models.GeneratedDropsProvenance, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open file: %q", filePath)
+	}
+	defer file.Close()
+
+	headers := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, isHeader := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "#")
+		if !isHeader {
+			break
+		}
+		key, value, isKeyValue := strings.Cut(line, ":")
+		if isKeyValue {
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read %q", filePath)
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	provenance := &models.GeneratedDropsProvenance{
+		Pool: models.BattledomeItemMetadata{
+			Arena:      models.Arena(headers[provenanceArenaKey]),
+			Challenger: models.Challenger(headers[provenanceChallengerKey]),
+			Difficulty: models.Difficulty(headers[provenanceDifficultyKey]),
+		},
+		WeightsHash: headers[provenanceWeightsHashKey],
+	}
+	if provenance.GeneratorVersion, err = strconv.Atoi(headers[provenanceGeneratorVersionKey]); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse the generator version in %q", filePath)
+	}
+	if provenance.Seed, err = strconv.ParseUint(headers[provenanceSeedKey], 10, 64); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse the seed in %q", filePath)
+	}
+	if provenance.Count, err = strconv.Atoi(headers[provenanceCountKey]); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse the count in %q", filePath)
+	}
+	if provenance.GeneratedAt, err = time.Parse(time.RFC3339, headers[provenanceGeneratedAtKey]); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse the generation time in %q", filePath)
+	}
+	return provenance, nil
+}
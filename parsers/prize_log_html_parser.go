@@ -0,0 +1,130 @@
+package parsers
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+// CSS selectors for the parts of a saved page we care about. Everything other than Battle is looked up within each
+// battle; if nothing matches Battle, the whole page is treated as one battle.
+type PrizeLogSelectors struct {
+	Battle     string
+	Arena      string
+	Challenger string
+	Difficulty string
+	Item       string
+}
+
+var (
+	// Covers both the results screen at the end of a fight and the prize log, which lists one entry per fight
+	// These haven't been checked against real saved pages yet; the pages in testdata were written to match them, so
+	// import html stays disabled until both come from real pages
+	DefaultPrizeLogSelectors = PrizeLogSelectors{
+		Battle:     "#arenacontainer, .prizelog-entry",
+		Arena:      "#arenaname, .prizelog-arena",
+		Challenger: "#p2name, .prizelog-opponent",
+		Difficulty: "#p2difficulty, .prizelog-difficulty",
+		Item:       "#bd_rewardsloot td, .prizelog-loot li",
+	}
+)
+
+type PrizeLogHtmlParser struct {
+	Selectors PrizeLogSelectors
+}
+
+func NewPrizeLogHtmlParser() *PrizeLogHtmlParser {
+	return &PrizeLogHtmlParser{
+		Selectors: DefaultPrizeLogSelectors,
+	}
+}
+
+func selectionText(selection *goquery.Selection) string {
+	return helpers.CollapseWhitespace(selection.First().Text())
+}
+
+// Item cells usually hold an image and a caption; fall back to the image's alt text if there's no caption
+func prizeName(selection *goquery.Selection) string {
+	name := helpers.CollapseWhitespace(selection.Text())
+	if name == "" {
+		name = helpers.CollapseWhitespace(selection.Find("img").AttrOr("alt", ""))
+	}
+	return name
+}
+
+func canonicalArena(arena string) string {
+	arenas := helpers.BattledomeCatalogueInstance().ArenaNames()
+	index := slices.IndexFunc(arenas, func(knownArena string) bool {
+		return strings.EqualFold(knownArena, arena)
+	})
+	if index == -1 {
+		return arena
+	}
+	return arenas[index]
+}
+
+// Returns one battle per fight on the page, in page order. Metadata the page doesn't show is left empty.
+func (p *PrizeLogHtmlParser) Parse(filePath string) ([]*models.Battle, error) {
+	if !helpers.IsFileExists(filePath) {
+		return nil, fmt.Errorf("saved prize log page does not exist: %s", filePath)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open file: %q", filePath)
+	}
+	defer file.Close()
+
+	doc, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse %q as HTML", filePath)
+	}
+
+	battleSelections := doc.Find(p.Selectors.Battle)
+	if battleSelections.Length() == 0 {
+		battleSelections = doc.Selection
+	}
+
+	battles := []*models.Battle{}
+	battleSelections.Each(func(_ int, battleSelection *goquery.Selection) {
+		battle := &models.Battle{
+			Metadata: models.BattledomeItemMetadata{
+				Arena:      models.Arena(canonicalArena(selectionText(battleSelection.Find(p.Selectors.Arena)))),
+				Challenger: models.Challenger(selectionText(battleSelection.Find(p.Selectors.Challenger))),
+				Difficulty: models.Difficulty(selectionText(battleSelection.Find(p.Selectors.Difficulty))),
+			},
+			Items: models.BattledomeItems{},
+		}
+		battleSelection.Find(p.Selectors.Item).Each(func(_ int, itemSelection *goquery.Selection) {
+			name := prizeName(itemSelection)
+			if name == "" {
+				return
+			}
+			// Pages list each prize once per drop, but a drop file has one line per item
+			itemName := models.ItemName(helpers.ItemAliasesInstance().Canonical(name))
+			if index := slices.IndexFunc(battle.Items, func(item *models.BattledomeItem) bool { return item.Name == itemName }); index != -1 {
+				battle.Items[index].Quantity++
+				return
+			}
+			battle.Items = append(battle.Items, &models.BattledomeItem{
+				Metadata: battle.Metadata,
+				Name:     itemName,
+				Quantity: 1,
+			})
+		})
+		if len(battle.Items) > 0 {
+			battles = append(battles, battle)
+		}
+	})
+
+	if len(battles) == 0 {
+		return nil, fmt.Errorf("no prizes were found in %q; is it a saved Battledome results or prize log page?", filePath)
+	}
+	return battles, nil
+}
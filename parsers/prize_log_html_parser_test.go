@@ -0,0 +1,61 @@
+package parsers
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPrizeLogHtmlParserReadsResultsPage(t *testing.T) {
+	battles, err := NewPrizeLogHtmlParser().Parse(filepath.Join("testdata", "battledome_results.html"))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(battles) != 1 {
+		t.Fatalf("Expected 1 battle, but got %d", len(battles))
+	}
+
+	battle := battles[0]
+	if battle.Metadata.Arena != "Central Arena" || battle.Metadata.Challenger != "Flaming Meerca" || battle.Metadata.Difficulty != "Mighty" {
+		t.Fatalf("Expected Central Arena - Flaming Meerca - Mighty, but got %s", battle.Metadata.String())
+	}
+
+	normalisedItems, err := battle.Items.Normalise()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	shouldHaveItemAndQuantity(normalisedItems, t, "Har Codestone", 2)
+	shouldHaveItemAndQuantity(normalisedItems, t, "Robot Muffin", 1)
+}
+
+func TestPrizeLogHtmlImportRoundTrips(t *testing.T) {
+	battles, err := NewPrizeLogHtmlParser().Parse(filepath.Join("testdata", "prize_log.html"))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(battles) != 2 {
+		t.Fatalf("Expected 2 battles, but got %d", len(battles))
+	}
+
+	filePath := filepath.Join(t.TempDir(), "2025_09_17.txt")
+	dropDataParser := NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t))
+	if err := dropDataParser.Append(filePath, battles[:1]); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err := dropDataParser.Append(filePath, battles[1:]); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	dto, err := dropDataParser.Parse(filePath)
+	if err != nil {
+		t.Fatalf("Expected the imported file to be valid drop data, but got: %s", err)
+	}
+	if len(dto.Battles) != 2 || dto.Battles[1].Metadata.Challenger != "Snow Faerie" {
+		t.Fatalf("Expected both battles to be read back, but got %d", len(dto.Battles))
+	}
+
+	normalisedItems, err := dto.Battles[0].Items.Normalise()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	shouldHaveItemAndQuantity(normalisedItems, t, "Main Codestone", 2)
+}
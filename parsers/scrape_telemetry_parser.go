@@ -0,0 +1,54 @@
+package parsers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/palantir/stacktrace"
+)
+
+type ScrapeTelemetryParser struct{}
+
+func NewScrapeTelemetryParser() *ScrapeTelemetryParser {
+	return &ScrapeTelemetryParser{}
+}
+
+func (p *ScrapeTelemetryParser) Parse(filePath string) ([]helpers.ScrapeRecord, error) {
+	if !helpers.IsFileExists(filePath) {
+		return nil, fmt.Errorf("scrape telemetry file does not exist: %s", filePath)
+	}
+
+	file, err := os.OpenFile(filePath, os.O_RDONLY, 0755)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open file: %q", filePath)
+	}
+	defer file.Close()
+
+	records := []helpers.ScrapeRecord{}
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		record := helpers.ScrapeRecord{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			// Most likely a line cut short by the program being killed mid-write
+			slog.Warn(fmt.Sprintf("Skipping malformed scrape record on line %d of %s: %s", lineNumber, filePath, err))
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read %q", filePath)
+	}
+	return records, nil
+}
@@ -0,0 +1,44 @@
+package parsers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+)
+
+func TestScrapeTelemetryRoundTrip(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	telemetry := helpers.NewScrapeTelemetry(filePath)
+	written := []helpers.ScrapeRecord{
+		{Timestamp: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Url: "https://example.com/a", Item: "Green Apple", Source: "JellyNeo", UserAgent: "agent", StatusCode: 200, LatencyMs: 120, Price: 15},
+		{Timestamp: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC), Url: "https://example.com/b", Item: "Red Apple", Source: "JellyNeo", UserAgent: "agent", StatusCode: 429, LatencyMs: 40, ErrorClass: "rate-limited"},
+	}
+	for _, record := range written {
+		if err := telemetry.Log(record); err != nil {
+			t.Fatalf("%s", err)
+		}
+	}
+
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0755)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	file.WriteString(`{"timestamp":"2025-01-01T14:00`)
+	file.Close()
+
+	records, err := NewScrapeTelemetryParser().Parse(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(records) != len(written) {
+		t.Fatalf("Expected %d records (the truncated line should be skipped), but got %d", len(written), len(records))
+	}
+	for i := range written {
+		if records[i] != written[i] {
+			t.Fatalf("Record %d did not survive the round trip:\n\tExpected: %+v\n\tReceived: %+v", i, written[i], records[i])
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package parsers
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+type WishlistParser struct{}
+
+func NewWishlistParser() *WishlistParser {
+	return &WishlistParser{}
+}
+
+const anyPrefix = "any "
+
+// One "Item Name|Quantity" per line, where the quantity defaults to 1 and the name can be an item group. "any Group
+// Name|Quantity" wants that many of any item in the group. Lines starting with # are ignored.
+func (p *WishlistParser) Parse(filePath string) (models.Wishlist, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open wishlist: %s", filePath)
+	}
+	defer file.Close()
+
+	wishlist := models.Wishlist{}
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entry := models.WishlistEntry{Quantity: 1}
+		name, rawQuantity, hasQuantity := strings.Cut(line, "|")
+		if hasQuantity {
+			entry.Quantity, err = strconv.Atoi(strings.TrimSpace(rawQuantity))
+			if err != nil || entry.Quantity <= 0 {
+				return nil, fmt.Errorf("%s:%d: quantity must be a positive integer but was %q", filePath, lineNumber, strings.TrimSpace(rawQuantity))
+			}
+		}
+		name = helpers.CollapseWhitespace(name)
+		if len(name) > len(anyPrefix) && strings.EqualFold(name[:len(anyPrefix)], anyPrefix) {
+			entry.IsAny = true
+			name = strings.TrimSpace(name[len(anyPrefix):])
+		}
+		entry.Name = name
+		wishlist = append(wishlist, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read wishlist: %s", filePath)
+	}
+	if len(wishlist) == 0 {
+		return nil, fmt.Errorf("%s: the wishlist is empty", filePath)
+	}
+	return wishlist, nil
+}
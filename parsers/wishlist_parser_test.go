@@ -0,0 +1,42 @@
+package parsers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+func TestWishlistParser(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "wishlist.txt")
+	content := "# Codestones for Mystery Island training\nBrown Codestones|2\n\nany  Red Codestones | 3\nKasuki Lu Plushie\n"
+	if err := os.WriteFile(filePath, []byte(content), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	wishlist, err := NewWishlistParser().Parse(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	expected := models.Wishlist{
+		{Name: "Brown Codestones", Quantity: 2},
+		{Name: "Red Codestones", Quantity: 3, IsAny: true},
+		{Name: "Kasuki Lu Plushie", Quantity: 1},
+	}
+	if !slices.Equal(wishlist, expected) {
+		t.Fatalf("Expected %v, but got %v", expected, wishlist)
+	}
+}
+
+func TestWishlistParserRejectsBadQuantities(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "wishlist.txt")
+	if err := os.WriteFile(filePath, []byte("Brown Codestones|0\n"), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if _, err := NewWishlistParser().Parse(filePath); err == nil {
+		t.Fatalf("Expected a quantity of 0 to be rejected")
+	}
+}
@@ -3,16 +3,19 @@ package services
 import (
 	"fmt"
 	"math/rand/v2"
+	"slices"
 	"sort"
 	"sync"
 
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
 	"github.com/palantir/stacktrace"
 	"github.com/schollz/progressbar/v3"
 )
 
 type BattledomeItemWeights interface {
-	ItemWeights(arena string) ([]models.BattledomeItemWeight, error)
+	ItemWeights(metadata models.BattledomeItemMetadata) ([]models.BattledomeItemWeight, error)
+	PoolMetadata(metadata models.BattledomeItemMetadata) (models.BattledomeItemMetadata, error)
 }
 
 type BattledomeItemGenerationService struct {
@@ -25,69 +28,89 @@ func NewBattledomeItemGenerationService(battledomeItemWeights BattledomeItemWeig
 	}
 }
 
-func (s *BattledomeItemGenerationService) generateItem(weights []models.BattledomeItemWeight) string {
-	rand.Shuffle(len(weights), func(i int, j int) {
-		weights[i], weights[j] = weights[j], weights[i]
-	})
+// Generation is split into a fixed number of chunks, each with its own random source, so that the same seed always
+// gives the same items however many goroutines actually run
+const generationChunks = 64
+
+// Weights ordered from least to most likely, with the running total of each
+type cumulativeWeights struct {
+	names  []string
+	totals []float64
+}
+
+func newCumulativeWeights(weights []models.BattledomeItemWeight) cumulativeWeights {
+	weights = slices.Clone(weights)
 	sort.SliceStable(weights, func(i int, j int) bool {
 		return weights[i].Weight < weights[j].Weight
 	})
+	cumulative := cumulativeWeights{}
 	total := 0.0
 	for _, weight := range weights {
-		total += weight.Weight * 100
+		total += weight.Weight
+		cumulative.names = append(cumulative.names, weight.Name)
+		cumulative.totals = append(cumulative.totals, total)
 	}
-	sample := float64(1 + rand.IntN(int(total)))
-	tot := 0.0
-	for _, weight := range weights {
-		tot += weight.Weight * 100
-		if tot >= sample {
-			return weight.Name
-		}
+	return cumulative
+}
+
+func (w cumulativeWeights) generateItem(random *rand.Rand) string {
+	sample := random.Float64() * w.totals[len(w.totals)-1]
+	i, _ := slices.BinarySearch(w.totals, sample)
+	if i == len(w.totals) {
+		panic(fmt.Errorf("failed to generate an item - this should not happen; total was %f, sample was %f", w.totals[len(w.totals)-1], sample))
 	}
-	panic(fmt.Errorf("failed to generate an item - this should not happen; total was %f, sample was %f", total, sample))
+	return w.names[i]
 }
 
-func (s *BattledomeItemGenerationService) Items(arena models.Arena, count int) (models.NormalisedBattledomeItems, error) {
-	weights, err := s.BattledomeItemWeights.ItemWeights(string(arena))
+// Items are generated deterministically from the seed, including the challenger's own prizes if the fight has any
+func (s *BattledomeItemGenerationService) Items(metadata models.BattledomeItemMetadata, count int, seed uint64) (models.NormalisedBattledomeItems, error) {
+	weights, err := s.BattledomeItemWeights.ItemWeights(metadata)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "failed to get item weights for %q", arena)
+		return nil, stacktrace.Propagate(err, "failed to get item weights for %s", metadata)
 	}
-
-	progressBarMutex := &sync.Mutex{}
-	itemChannel := make(chan string, count)
-	wg := &sync.WaitGroup{}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("there are no item weights for %s", metadata)
+	}
+	cumulative := newCumulativeWeights(weights)
 
 	progressBar := progressbar.Default(int64(count))
-	for i := 0; i < count; i++ {
+	chunkCounts := make([]map[string]int32, generationChunks)
+	wg := &sync.WaitGroup{}
+	for chunk := 0; chunk < generationChunks; chunk++ {
 		wg.Add(1)
-		go func(p *progressbar.ProgressBar) {
+		go func(chunk int) {
 			defer wg.Done()
 
-			item := s.generateItem(append([]models.BattledomeItemWeight(nil), weights...))
-			itemChannel <- item
-			progressBarMutex.Lock()
-			defer progressBarMutex.Unlock()
-			p.Add(1)
-		}(progressBar)
+			random := rand.New(rand.NewPCG(seed, uint64(chunk)))
+			chunkSize := count/generationChunks + helpers.When(chunk < count%generationChunks, 1, 0)
+			counts := map[string]int32{}
+			for i := 0; i < chunkSize; i++ {
+				counts[cumulative.generateItem(random)]++
+				if (i+1)%10_000 == 0 {
+					progressBar.Add(10_000)
+				}
+			}
+			progressBar.Add(chunkSize % 10_000)
+			chunkCounts[chunk] = counts
+		}(chunk)
 	}
 	wg.Wait()
-	close(itemChannel)
-
-	itemNames := []models.ItemName{}
-	for range itemChannel {
-		itemNames = append(itemNames, models.ItemName(<-itemChannel))
-	}
 
+	generatedMetadata := models.GeneratedMetadata(metadata.Arena).BattledomeItemMetadata
 	items := models.NormalisedBattledomeItems{}
-	for _, generatedItem := range itemNames {
-		item, isInItems := items[generatedItem]
-		if !isInItems {
-			items[generatedItem] = &models.BattledomeItem{
-				Name:     generatedItem,
-				Quantity: 1,
+	for _, counts := range chunkCounts {
+		for name, quantity := range counts {
+			itemName := models.ItemName(name)
+			item, isInItems := items[itemName]
+			if !isInItems {
+				items[itemName] = &models.BattledomeItem{
+					Metadata: generatedMetadata,
+					Name:     itemName,
+					Quantity: quantity,
+				}
+			} else {
+				item.Quantity += quantity
 			}
-		} else {
-			item.Quantity++
 		}
 	}
 
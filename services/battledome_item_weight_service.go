@@ -1,6 +1,9 @@
 package services
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
@@ -21,12 +24,94 @@ func NewBattledomeItemWeightService(savedBattledomeItemWeights SavedBattledomeIt
 	}
 }
 
-func (s *BattledomeItemWeightService) ItemWeights(arena string) ([]models.BattledomeItemWeight, error) {
+func (s *BattledomeItemWeightService) allItemWeights() ([]models.BattledomeItemWeight, error) {
 	weights, err := s.SavedBattledomeItemWeights.Parse(constants.ItemWeightsFilePath())
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to parse %q as item weights", constants.ItemWeightsFilePath())
 	}
-	return helpers.Filter(weights, func(weight models.BattledomeItemWeight) bool {
-		return weight.Arena == arena
-	}), nil
+	return weights, nil
+}
+
+// Every prize pool in the weights file: each arena's, and each challenger's that has prizes of their own
+func (s *BattledomeItemWeightService) Pools() ([]models.BattledomeItemMetadata, error) {
+	weights, err := s.allItemWeights()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get item weights")
+	}
+	pools := []models.BattledomeItemMetadata{}
+	for _, weight := range weights {
+		if !slices.Contains(pools, weight.Pool()) {
+			pools = append(pools, weight.Pool())
+		}
+	}
+	return pools, nil
+}
+
+// The most specific prize pool that applies to a fight: the challenger's pool for that difficulty, then their pool for
+// any difficulty, then just the arena's
+func (s *BattledomeItemWeightService) PoolMetadata(metadata models.BattledomeItemMetadata) (models.BattledomeItemMetadata, error) {
+	weights, err := s.allItemWeights()
+	if err != nil {
+		return models.BattledomeItemMetadata{}, stacktrace.Propagate(err, "failed to get item weights")
+	}
+	candidates := []models.BattledomeItemMetadata{
+		metadata,
+		{Arena: metadata.Arena, Challenger: metadata.Challenger},
+	}
+	for _, candidate := range candidates {
+		if candidate.Challenger == "" {
+			continue
+		}
+		if slices.ContainsFunc(weights, func(weight models.BattledomeItemWeight) bool {
+			return weight.Pool() == candidate
+		}) {
+			return candidate, nil
+		}
+	}
+	return models.BattledomeItemMetadata{Arena: metadata.Arena}, nil
+}
+
+// The weights of every item that can drop in a fight. If the fight has a challenger pool (see PoolMetadata), the arena's
+// weights are scaled down to make room for the challenger's items.
+func (s *BattledomeItemWeightService) ItemWeights(metadata models.BattledomeItemMetadata) ([]models.BattledomeItemWeight, error) {
+	weights, err := s.allItemWeights()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get item weights")
+	}
+	arenaWeights := helpers.Filter(weights, func(weight models.BattledomeItemWeight) bool {
+		return weight.Pool() == models.BattledomeItemMetadata{Arena: metadata.Arena}
+	})
+
+	pool, err := s.PoolMetadata(metadata)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get prize pool for %s", metadata)
+	}
+	if pool.Challenger == "" {
+		return arenaWeights, nil
+	}
+
+	challengerWeights := helpers.Filter(weights, func(weight models.BattledomeItemWeight) bool {
+		return weight.Pool() == pool
+	})
+	challengerTotal := helpers.Sum(helpers.Map(challengerWeights, func(weight models.BattledomeItemWeight) float64 {
+		return weight.Weight
+	}))
+	if challengerTotal > 1 {
+		return nil, fmt.Errorf("the drop rates of the items in %s add up to more than 100%% (%s%%)", pool, helpers.FormatPercentage(challengerTotal))
+	}
+	arenaTotal := helpers.Sum(helpers.Map(arenaWeights, func(weight models.BattledomeItemWeight) float64 {
+		return weight.Weight
+	}))
+	if arenaTotal == 0 {
+		if challengerTotal < 1 {
+			return nil, fmt.Errorf("%s has its own prizes but %s has no drop rates to make up the other %s%%", pool, metadata.Arena, helpers.FormatPercentage(1-challengerTotal))
+		}
+		return challengerWeights, nil
+	}
+
+	scaledWeights := helpers.Map(arenaWeights, func(weight models.BattledomeItemWeight) models.BattledomeItemWeight {
+		weight.Weight = weight.Weight / arenaTotal * (1 - challengerTotal)
+		return weight
+	})
+	return append(scaledWeights, challengerWeights...), nil
 }
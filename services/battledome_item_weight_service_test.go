@@ -0,0 +1,38 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+func TestItemWeightsMakeRoomForChallengerPrizes(t *testing.T) {
+	target := NewBattledomeItemWeightService(&fakeItemWeights{weights: []models.BattledomeItemWeight{
+		{Arena: "Central Arena", Name: "Robot Muffin", Weight: 0.75},
+		{Arena: "Central Arena", Name: "Orn Codestone", Weight: 0.25},
+		{Arena: "Central Arena", Challenger: "Kasuki Lu", Name: "Nimmo Battle Cry", Weight: 0.2},
+	}})
+	weights, err := target.ItemWeights(models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Kasuki Lu", Difficulty: "Mighty"})
+	if err != nil {
+		t.Fatalf("Failed to get item weights: %s", err)
+	}
+	expected := map[string]float64{"Robot Muffin": 0.6, "Orn Codestone": 0.2, "Nimmo Battle Cry": 0.2}
+	if len(weights) != len(expected) {
+		t.Fatalf("Expected %d weights, but got %v", len(expected), weights)
+	}
+	for _, weight := range weights {
+		if math.Abs(weight.Weight-expected[weight.Name]) > 1e-9 {
+			t.Fatalf("Expected %s to have weight %f, but got %f", weight.Name, expected[weight.Name], weight.Weight)
+		}
+	}
+}
+
+func TestItemWeightsNeedArenaWeightsUnderAPartialChallengerPool(t *testing.T) {
+	target := NewBattledomeItemWeightService(&fakeItemWeights{weights: []models.BattledomeItemWeight{
+		{Arena: "Central Arena", Challenger: "Kasuki Lu", Name: "Nimmo Battle Cry", Weight: 0.2},
+	}})
+	if _, err := target.ItemWeights(models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Kasuki Lu"}); err == nil {
+		t.Fatalf("Expected an error when there are no arena weights to make up the rest of the drops")
+	}
+}
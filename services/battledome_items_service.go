@@ -1,7 +1,8 @@
 package services
 
 import (
-	"strings"
+	"fmt"
+	"log/slog"
 
 	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
@@ -9,77 +10,126 @@ import (
 	"github.com/palantir/stacktrace"
 )
 
-type GeneratedBattledomeItems interface {
-	Items(arena models.Arena, count int) (models.NormalisedBattledomeItems, error)
-}
-
-type SavedGeneratedBattledomeItems interface {
-	Parse(filePath string) (models.NormalisedBattledomeItems, error)
-	Save(items models.NormalisedBattledomeItems, filePath string) error
-}
-
 type SavedBattledomeItems interface {
 	Parse(filePath string) (*models.BattledomeItemsDto, error)
 }
 
 type BattledomeItemsService struct {
-	GeneratedBattledomeItems
-	SavedGeneratedBattledomeItems
-	SavedBattledomeItems
+	*GeneratedDropsCacheService
+	*DropDataStore
+	// If set, only drops recorded by this contributor are used
+	ContributorFilter string
+	// Duplicate pairs that have already been warned about, so each is only logged once
+	reportedDuplicates map[DuplicateDropFiles]bool
 }
 
 func NewBattledomeItemsService(
-	generatedBattledomeItems GeneratedBattledomeItems,
-	generatedBattledomeItemParser SavedGeneratedBattledomeItems,
-	battledomeItemDropDataParser SavedBattledomeItems,
+	generatedDropsCacheService *GeneratedDropsCacheService,
+	dropDataStore *DropDataStore,
 ) *BattledomeItemsService {
 	return &BattledomeItemsService{
-		GeneratedBattledomeItems:      generatedBattledomeItems,
-		SavedGeneratedBattledomeItems: generatedBattledomeItemParser,
-		SavedBattledomeItems:          battledomeItemDropDataParser,
+		GeneratedDropsCacheService: generatedDropsCacheService,
+		DropDataStore:              dropDataStore,
+		reportedDuplicates:         map[DuplicateDropFiles]bool{},
 	}
 }
 
-func (s *BattledomeItemsService) AllDrops() (map[models.Arena]models.BattledomeItems, error) {
-	files, err := helpers.FilesInFolder(constants.BattledomeDropsFolder)
-	if err != nil {
-		// Could be due to inconsistent caller, try going down one level
-		newPath := strings.Replace(constants.BattledomeDropsFolder, "../", "", 1)
-		files, err = helpers.FilesInFolder(newPath)
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "failed to get files in %q", newPath)
-		}
+// Recorded battles matching the query in the order they were fought, restricted to ContributorFilter if it is set
+func (s *BattledomeItemsService) Battles(query DropDataQuery) ([]*models.Battle, error) {
+	if err := s.checkForDuplicates(); err != nil {
+		return nil, err
+	}
+	if s.ContributorFilter != "" {
+		query.Contributor = s.ContributorFilter
 	}
 
-	itemsByArena := map[models.Arena]models.BattledomeItems{}
-	for _, file := range files {
-		dto, err := s.SavedBattledomeItems.Parse(constants.DropDataFilePath(file))
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "failed to parse %q as battledome drop data", file)
-		}
-		_, exists := itemsByArena[models.Arena(dto.Metadata.Arena)]
-		if !exists {
-			itemsByArena[dto.Metadata.Arena] = models.BattledomeItems{}
-		}
+	battles, err := s.DropDataStore.Battles(query)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to query drop data")
+	}
+	return battles, nil
+}
 
-		itemsByArena[dto.Metadata.Arena] = append(itemsByArena[dto.Metadata.Arena], dto.Items...)
+// Recorded drops matching the query, restricted to ContributorFilter if it is set
+func (s *BattledomeItemsService) Drops(query DropDataQuery) (models.BattledomeItems, error) {
+	battles, err := s.Battles(query)
+	if err != nil {
+		return nil, err
 	}
+	return helpers.FlatMap(battles, func(battle *models.Battle) []*models.BattledomeItem {
+		return battle.Items
+	}), nil
+}
 
+func (s *BattledomeItemsService) AllDrops() (map[models.Arena]models.BattledomeItems, error) {
+	allDrops, err := s.Drops(DropDataQuery{})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get all drops")
+	}
+	itemsByArena := map[models.Arena]models.BattledomeItems{}
+	for _, item := range allDrops {
+		itemsByArena[item.Metadata.Arena] = append(itemsByArena[item.Metadata.Arena], item)
+	}
 	return itemsByArena, nil
 }
 
-func (s *BattledomeItemsService) DropsByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, error) {
+func (s *BattledomeItemsService) checkForDuplicates() error {
+	duplicates, err := s.DropDataStore.Duplicates()
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to check for duplicate drop files")
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	if constants.ShouldFailOnDuplicateDropFiles {
+		return stacktrace.Propagate(&DuplicateDropFilesError{Duplicates: duplicates}, "drop data contains duplicate files")
+	}
+	for _, duplicate := range duplicates {
+		if s.reportedDuplicates[duplicate] {
+			continue
+		}
+		s.reportedDuplicates[duplicate] = true
+		slog.Warn(fmt.Sprintf("Possible duplicate drop files; their drops will be counted twice: %s", duplicate.String()))
+	}
+	return nil
+}
+
+// Everyone who has recorded drops, ignoring ContributorFilter
+func (s *BattledomeItemsService) Contributors() ([]string, error) {
+	contributorFilter := s.ContributorFilter
+	s.ContributorFilter = ""
+	defer func() {
+		s.ContributorFilter = contributorFilter
+	}()
+
 	allDrops, err := s.AllDrops()
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get all drops")
 	}
+	contributors := map[string]any{}
+	for _, items := range allDrops {
+		for _, item := range items {
+			for contributor := range item.Contributors {
+				contributors[contributor] = nil
+			}
+		}
+	}
+	return helpers.OrderBy(helpers.Keys(contributors), func(contributor string) string {
+		return contributor
+	}), nil
+}
 
-	arenaItems := allDrops[metadata.Arena]
-	matchingArenaDrops := helpers.Filter(arenaItems, func(item *models.BattledomeItem) bool {
-		return item.Metadata == metadata
+func (s *BattledomeItemsService) DropsByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, error) {
+	matchingDrops, err := s.Drops(DropDataQuery{
+		Arena:      metadata.Arena,
+		Challenger: metadata.Challenger,
+		Difficulty: metadata.Difficulty,
 	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get drops for %s", metadata)
+	}
 
-	return models.BattledomeItems(matchingArenaDrops).Normalise()
+	return matchingDrops.Normalise()
 }
 
 func (s *BattledomeItemsService) DropsGroupedByMetadata() (map[models.BattledomeItemMetadata]models.NormalisedBattledomeItems, error) {
@@ -105,35 +155,31 @@ func (s *BattledomeItemsService) DropsGroupedByMetadata() (map[models.Battledome
 }
 
 func (s *BattledomeItemsService) DropsByArena(arena models.Arena) (models.NormalisedBattledomeItems, error) {
-	allDrops, err := s.AllDrops()
+	arenaDrops, err := s.Drops(DropDataQuery{Arena: arena})
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "failed to get all drops")
+		return nil, stacktrace.Propagate(err, "failed to get drops for %q", arena)
 	}
-	normalisedDrops, err := allDrops[arena].Normalise()
+	normalisedDrops, err := arenaDrops.Normalise()
 	if err != nil {
-		return nil, helpers.PropagateWithSerialisedValue(err, "failed to normalise %s", "failed to normalise items; additional encountered an error while trying to serialise the item for logging: %s", allDrops[arena])
+		return nil, helpers.PropagateWithSerialisedValue(err, "failed to normalise %s", "failed to normalise items; additional encountered an error while trying to serialise the item for logging: %s", arenaDrops)
 	}
 	return normalisedDrops, nil
 }
 
+// Generated drops from the arena's own prize pool
 func (s *BattledomeItemsService) GeneratedDropsByArena(arena models.Arena) (models.NormalisedBattledomeItems, error) {
-	if helpers.IsFileExists(constants.GeneratedDropsFilePath(string(arena))) {
-		parsedDrops, err := s.SavedGeneratedBattledomeItems.Parse(constants.GeneratedDropsFilePath(string(arena)))
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "failed to parse %q as battledome drops", arena)
-		}
-
-		return parsedDrops, nil
-	} else {
-		items, err := s.GeneratedBattledomeItems.Items(arena, constants.NumberOfItemsToGenerate)
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "failed to generate items for %q", arena)
-		}
-
-		if s.SavedGeneratedBattledomeItems.Save(items, constants.GeneratedDropsFilePath(string(arena))); err != nil {
-			return nil, stacktrace.Propagate(err, "falled to save generated drops to %q", constants.GeneratedDropsFilePath(string(arena)))
-		}
+	generatedDrops, err := s.GeneratedDropsCacheService.Drops(models.BattledomeItemMetadata{Arena: arena})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get generated drops for %q", arena)
+	}
+	return generatedDrops, nil
+}
 
-		return items, nil
+// Generated drops for a fight, including the challenger's own prizes if the weights file has any for them
+func (s *BattledomeItemsService) GeneratedDropsByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, error) {
+	generatedDrops, err := s.GeneratedDropsCacheService.Drops(metadata)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get generated drops for %s", metadata)
 	}
+	return generatedDrops, nil
 }
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+type BootstrapMethod string
+
+const (
+	// The spread of the profit of one resampled day, rather than a confidence interval for the mean
+	DayBootstrap        BootstrapMethod = "day"
+	PercentileBootstrap BootstrapMethod = "percentile"
+	// Bias-corrected and accelerated
+	BCaBootstrap         BootstrapMethod = "bca"
+	PercentileTBootstrap BootstrapMethod = "percentile-t"
+)
+
+var BootstrapMethods = []BootstrapMethod{DayBootstrap, PercentileBootstrap, BCaBootstrap, PercentileTBootstrap}
+
+func ParseBootstrapMethod(method string) (BootstrapMethod, error) {
+	for _, bootstrapMethod := range BootstrapMethods {
+		if strings.EqualFold(string(bootstrapMethod), method) {
+			return bootstrapMethod, nil
+		}
+	}
+	return "", fmt.Errorf("unknown bootstrap method %q (expected one of %s)", method, strings.Join(helpers.Map(BootstrapMethods, func(method BootstrapMethod) string {
+		return string(method)
+	}), ", "))
+}
+
+// Resamples are split into a fixed number of chunks, each with its own random source, so that the same seed always
+// gives the same results however many workers actually run
+const bootstrapChunks = 64
+
+// Bootstraps profit intervals for a day of drops, and simulates profit over longer periods. Intervals are cached by the
+// values and quantities of the drops, so the same data valued the same way is only resampled once.
+type BootstrapService struct {
+	Method  BootstrapMethod
+	Seed    uint64
+	samples int
+	workers int
+	cache   sync.Map
+}
+
+// Uses every CPU if workers is not positive
+func NewBootstrapService(method BootstrapMethod, samples int, workers int, seed uint64) *BootstrapService {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &BootstrapService{
+		Method:  method,
+		Seed:    seed,
+		samples: samples,
+		workers: workers,
+	}
+}
+
+// Drops grouped by value, so a resample only has to draw how many of each value there are rather than every drop
+type weightedValues struct {
+	// Ascending
+	values []float64
+	counts []int64
+	total  int64
+}
+
+func newWeightedValues(items models.NormalisedBattledomeItems, value func(item *models.BattledomeItem) float64) weightedValues {
+	countsByValue := map[float64]int64{}
+	for _, item := range items {
+		if item.Name == "nothing" || item.Quantity <= 0 {
+			continue
+		}
+		countsByValue[value(item)] += int64(item.Quantity)
+	}
+
+	weighted := weightedValues{values: helpers.Keys(countsByValue)}
+	slices.Sort(weighted.values)
+	for _, value := range weighted.values {
+		weighted.counts = append(weighted.counts, countsByValue[value])
+		weighted.total += countsByValue[value]
+	}
+	return weighted
+}
+
+func (w weightedValues) sum(counts []int64) float64 {
+	sum := 0.0
+	for i, value := range w.values {
+		sum += float64(counts[i]) * value
+	}
+	return sum
+}
+
+func (w weightedValues) mean(counts []int64) float64 {
+	return w.sum(counts) / float64(w.total)
+}
+
+// The standard error of the mean
+func (w weightedValues) standardError(counts []int64, mean float64) float64 {
+	if w.total < 2 {
+		return 0.0
+	}
+	sumOfSquares := 0.0
+	for i, value := range w.values {
+		sumOfSquares += float64(counts[i]) * (value - mean) * (value - mean)
+	}
+	return math.Sqrt(sumOfSquares/float64(w.total-1)) / math.Sqrt(float64(w.total))
+}
+
+// Draws how many of each value a resample of the given size has, one binomial at a time
+func (w weightedValues) resample(source rand.Source, counts []int64, size int64) {
+	remaining := size
+	remainingWeight := w.total
+	for i, count := range w.counts {
+		if remaining == 0 || i == len(w.counts)-1 {
+			counts[i] = remaining
+			remaining = 0
+			continue
+		}
+		drawn := int64(distuv.Binomial{
+			N:   float64(remaining),
+			P:   min(1.0, float64(count)/float64(remainingWeight)),
+			Src: source,
+		}.Rand())
+		counts[i] = drawn
+		remaining -= drawn
+		remainingWeight -= count
+	}
+}
+
+func (w weightedValues) key(method BootstrapMethod, samples int, seed uint64, alpha float64) string {
+	lines := []string{fmt.Sprintf("%s|%d|%d|%g", method, samples, seed, alpha)}
+	for i, value := range w.values {
+		lines = append(lines, fmt.Sprintf("%g|%d", value, w.counts[i]))
+	}
+	hash := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+type bootstrapReplicates struct {
+	// Sorted in ascending order
+	means []float64
+	// Studentised means, sorted in ascending order; only filled in for percentile-t
+	ts []float64
+}
+
+// Runs every chunk of resamples on the workers; each chunk gets its own seeded source, the index of its first resample
+// and a buffer for the counts of its resamples, and handles every bootstrapChunks-th resample from there
+func (s *BootstrapService) inChunks(weighted weightedValues, resample func(source rand.Source, first int, counts []int64)) {
+	semaphore := make(chan struct{}, s.workers)
+	wg := &sync.WaitGroup{}
+	for chunk := 0; chunk < bootstrapChunks; chunk++ {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(chunk int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			resample(rand.NewSource(s.Seed*bootstrapChunks+uint64(chunk)), chunk, make([]int64, len(weighted.values)))
+		}(chunk)
+	}
+	wg.Wait()
+}
+
+func (s *BootstrapService) replicates(weighted weightedValues, method BootstrapMethod, mean float64) bootstrapReplicates {
+	means := make([]float64, s.samples)
+	ts := make([]float64, s.samples)
+	isStudentised := method == PercentileTBootstrap
+
+	s.inChunks(weighted, func(source rand.Source, first int, counts []int64) {
+		for i := first; i < s.samples; i += bootstrapChunks {
+			if method == DayBootstrap {
+				weighted.resample(source, counts, constants.BattledomeDropsPerDay)
+				means[i] = weighted.sum(counts) / constants.BattledomeDropsPerDay
+				continue
+			}
+			weighted.resample(source, counts, weighted.total)
+			means[i] = weighted.mean(counts)
+			if !isStudentised {
+				continue
+			}
+			standardError := weighted.standardError(counts, means[i])
+			if standardError == 0 {
+				ts[i] = math.NaN()
+				continue
+			}
+			ts[i] = (means[i] - mean) / standardError
+		}
+	})
+
+	slices.Sort(means)
+	ts = slices.DeleteFunc(ts, math.IsNaN)
+	slices.Sort(ts)
+	return bootstrapReplicates{means: means, ts: ts}
+}
+
+// Leave-one-out estimate of how fast the standard error changes with the mean; drops of the same value all leave the
+// same mean behind, so there is one jackknife value per distinct value
+func (w weightedValues) acceleration() float64 {
+	sum := 0.0
+	for i, value := range w.values {
+		sum += float64(w.counts[i]) * value
+	}
+	leaveOneOutMeans := helpers.Map(w.values, func(value float64) float64 {
+		return (sum - value) / float64(w.total-1)
+	})
+	jackknifeMean := 0.0
+	for i, leaveOneOutMean := range leaveOneOutMeans {
+		jackknifeMean += float64(w.counts[i]) * leaveOneOutMean
+	}
+	jackknifeMean /= float64(w.total)
+
+	numerator := 0.0
+	denominator := 0.0
+	for i, leaveOneOutMean := range leaveOneOutMeans {
+		difference := jackknifeMean - leaveOneOutMean
+		numerator += float64(w.counts[i]) * difference * difference * difference
+		denominator += float64(w.counts[i]) * difference * difference
+	}
+	if denominator == 0 {
+		return 0.0
+	}
+	return numerator / (6 * math.Pow(denominator, 1.5))
+}
+
+func percentileInterval(replicates bootstrapReplicates, alpha float64) (float64, float64) {
+	return helpers.Percentile(replicates.means, alpha/2), helpers.Percentile(replicates.means, 1-alpha/2)
+}
+
+// Falls back to the percentile interval if every resample lands on the same side of the mean
+func bcaInterval(weighted weightedValues, replicates bootstrapReplicates, mean float64, alpha float64) (float64, float64) {
+	below, _ := slices.BinarySearch(replicates.means, mean)
+	proportionBelow := float64(below) / float64(len(replicates.means))
+	if proportionBelow == 0 || proportionBelow == 1 {
+		return percentileInterval(replicates, alpha)
+	}
+
+	biasCorrection := distuv.UnitNormal.Quantile(proportionBelow)
+	acceleration := weighted.acceleration()
+	adjust := func(p float64) float64 {
+		z := biasCorrection + distuv.UnitNormal.Quantile(p)
+		return distuv.UnitNormal.CDF(biasCorrection + z/(1-acceleration*z))
+	}
+	return helpers.Percentile(replicates.means, adjust(alpha/2)), helpers.Percentile(replicates.means, adjust(1-alpha/2))
+}
+
+func percentileTInterval(weighted weightedValues, replicates bootstrapReplicates, mean float64, alpha float64) (float64, float64) {
+	if len(replicates.ts) == 0 {
+		return percentileInterval(replicates, alpha)
+	}
+	standardError := weighted.standardError(weighted.counts, mean)
+	return mean - helpers.Percentile(replicates.ts, 1-alpha/2)*standardError, mean - helpers.Percentile(replicates.ts, alpha/2)*standardError
+}
+
+func (s *BootstrapService) interval(weighted weightedValues, method BootstrapMethod, alpha float64) (float64, float64, error) {
+	if weighted.total == 0 {
+		return 0.0, 0.0, nil
+	}
+	if s.samples <= 0 {
+		return 0.0, 0.0, fmt.Errorf("the number of bootstrap samples must be positive but was %d", s.samples)
+	}
+
+	key := weighted.key(method, s.samples, s.Seed, alpha)
+	if cached, exists := s.cache.Load(key); exists {
+		bounds := cached.([2]float64)
+		return bounds[0], bounds[1], nil
+	}
+
+	mean := weighted.mean(weighted.counts)
+	var leftBound, rightBound float64
+	if len(weighted.values) == 1 {
+		leftBound, rightBound = mean, mean
+	} else {
+		replicates := s.replicates(weighted, method, mean)
+		switch method {
+		case DayBootstrap, PercentileBootstrap:
+			leftBound, rightBound = percentileInterval(replicates, alpha)
+		case BCaBootstrap:
+			leftBound, rightBound = bcaInterval(weighted, replicates, mean, alpha)
+		case PercentileTBootstrap:
+			leftBound, rightBound = percentileTInterval(weighted, replicates, mean, alpha)
+		default:
+			return 0.0, 0.0, fmt.Errorf("unknown bootstrap method %q", method)
+		}
+	}
+
+	leftBound *= constants.BattledomeDropsPerDay
+	rightBound *= constants.BattledomeDropsPerDay
+	s.cache.Store(key, [2]float64{leftBound, rightBound})
+	return leftBound, rightBound, nil
+}
+
+func (s *BootstrapService) ProfitConfidenceInterval(valuation valuations.ItemValuation, items models.NormalisedBattledomeItems) (float64, float64, error) {
+	return s.interval(newWeightedValues(items, func(item *models.BattledomeItem) float64 {
+		return valuation.Value(string(item.Name))
+	}), s.Method, constants.SignificanceLevel)
+}
+
+// Always a confidence interval for the mean profit of a day: by the chosen method, or BCa if the chosen method is the
+// spread of a day
+func (s *BootstrapService) MeanProfitConfidenceInterval(valuation valuations.ItemValuation, items models.NormalisedBattledomeItems) (float64, float64, error) {
+	return s.interval(newWeightedValues(items, func(item *models.BattledomeItem) float64 {
+		return valuation.Value(string(item.Name))
+	}), helpers.When(s.Method == DayBootstrap, BCaBootstrap, s.Method), constants.SignificanceLevel)
+}
+
+// Challenger-specific drops count as worthless, as they do for ArenaMeanDropsProfit
+func (s *BootstrapService) ArenaProfitConfidenceInterval(valuation valuations.ItemValuation, items models.NormalisedBattledomeItems, generatedItems models.NormalisedBattledomeItems) (float64, float64, error) {
+	return s.interval(newWeightedValues(items, func(item *models.BattledomeItem) float64 {
+		if !item.IsArenaDrop(generatedItems) {
+			return 0.0
+		}
+		return valuation.Value(string(item.Name))
+	}), s.Method, constants.SignificanceLevel)
+}
+
+// Simulates a period of the given number of days once for every bootstrap sample, by resampling the drops
+func (s *BootstrapService) ProfitDistribution(valuation valuations.ItemValuation, items models.NormalisedBattledomeItems, days int) *models.ProfitDistribution {
+	weighted := newWeightedValues(items, func(item *models.BattledomeItem) float64 {
+		return valuation.Value(string(item.Name))
+	})
+	distribution := &models.ProfitDistribution{Days: days}
+	if weighted.total == 0 || s.samples <= 0 {
+		return distribution
+	}
+
+	distribution.Profits = make([]float64, s.samples)
+	s.inChunks(weighted, func(source rand.Source, first int, counts []int64) {
+		for i := first; i < s.samples; i += bootstrapChunks {
+			weighted.resample(source, counts, int64(days*constants.BattledomeDropsPerDay))
+			distribution.Profits[i] = weighted.sum(counts)
+		}
+	})
+	slices.Sort(distribution.Profits)
+	return distribution
+}
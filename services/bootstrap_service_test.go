@@ -0,0 +1,81 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+)
+
+func TestBootstrapIsReproducibleWhateverTheWorkers(t *testing.T) {
+	valuation := fixedValuation{"Robot Muffin": 100, "Cursed Wand of Shadow": 2_000, "Ridiculously Heavy Battle Hammer": 50_000}
+	items := normalisedItems(map[string]int32{"Robot Muffin": 900, "Cursed Wand of Shadow": 90, "Ridiculously Heavy Battle Hammer": 10})
+
+	for _, method := range BootstrapMethods {
+		firstLeft, firstRight, err := NewBootstrapService(method, 2_000, 1, 7).ProfitConfidenceInterval(valuation, items)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		secondLeft, secondRight, err := NewBootstrapService(method, 2_000, 8, 7).ProfitConfidenceInterval(valuation, items)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if firstLeft != secondLeft || firstRight != secondRight {
+			t.Fatalf("Expected the same %s interval from the same seed, but got [%f, %f] and [%f, %f]", method, firstLeft, firstRight, secondLeft, secondRight)
+		}
+	}
+
+	first := NewBootstrapService(DayBootstrap, 2_000, 1, 7).ProfitDistribution(valuation, items, 30)
+	second := NewBootstrapService(DayBootstrap, 2_000, 8, 7).ProfitDistribution(valuation, items, 30)
+	if !slices.Equal(first.Profits, second.Profits) {
+		t.Fatalf("Expected the same simulated seasons from the same seed, but got means of %f and %f", first.Mean(), second.Mean())
+	}
+}
+
+func TestDayBootstrapIsTheSpreadOfADay(t *testing.T) {
+	valuation := fixedValuation{"Robot Muffin": 100, "Cursed Wand of Shadow": 2_000}
+	items := normalisedItems(map[string]int32{"Robot Muffin": 8_000, "Cursed Wand of Shadow": 2_000})
+
+	// A day makes 1,500 NP plus 1,900 NP for each of its wands, which number Binomial(15, 0.2): none 3.5% of the time,
+	// and at most 6 of them 98.2% of the time
+	leftBound, rightBound, err := NewBootstrapService(DayBootstrap, 5_000, 0, 1).ProfitConfidenceInterval(valuation, items)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if leftBound != 1_500 || rightBound != 12_900 {
+		t.Fatalf("Expected the day interval to be [1500, 12900], but got [%f, %f]", leftBound, rightBound)
+	}
+}
+
+func TestBootstrapIntervalsAgreeWithTheNormalApproximation(t *testing.T) {
+	valuation := fixedValuation{"Robot Muffin": 100, "Cursed Wand of Shadow": 2_000}
+	items := normalisedItems(map[string]int32{"Robot Muffin": 8_000, "Cursed Wand of Shadow": 2_000})
+
+	// Mean drop is 480 NP with a standard error of sqrt(0.16 * 1,900^2 / 10,000) = 7.6 NP
+	mean := 480.0 * constants.BattledomeDropsPerDay
+	halfWidth := 1.96 * 7.6 * constants.BattledomeDropsPerDay
+	for _, method := range []BootstrapMethod{PercentileBootstrap, BCaBootstrap, PercentileTBootstrap} {
+		leftBound, rightBound, err := NewBootstrapService(method, 5_000, 0, 1).ProfitConfidenceInterval(valuation, items)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if math.Abs(leftBound-(mean-halfWidth)) > 0.1*halfWidth || math.Abs(rightBound-(mean+halfWidth)) > 0.1*halfWidth {
+			t.Fatalf("Expected the %s interval to be about [%f, %f], but got [%f, %f]", method, mean-halfWidth, mean+halfWidth, leftBound, rightBound)
+		}
+	}
+}
+
+func TestBootstrapIgnoresChallengerDropsInArenaIntervals(t *testing.T) {
+	valuation := fixedValuation{"Robot Muffin": 100, "Kasuki Lu Plushie": 1_000_000}
+	items := normalisedItems(map[string]int32{"Robot Muffin": 90, "Kasuki Lu Plushie": 10})
+	generatedItems := normalisedItems(map[string]int32{"Robot Muffin": 10})
+
+	leftBound, rightBound, err := NewBootstrapService(PercentileBootstrap, 1_000, 0, 1).ArenaProfitConfidenceInterval(valuation, items, generatedItems)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if rightBound > 100*constants.BattledomeDropsPerDay || leftBound < 0 {
+		t.Fatalf("Expected the plushies to be worth nothing, but got [%f, %f]", leftBound, rightBound)
+	}
+}
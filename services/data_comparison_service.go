@@ -1,15 +1,16 @@
 package services
 
 import (
-	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
 	"github.com/palantir/stacktrace"
 )
 
 type BattledomeItems interface {
 	DropsByArena(arena models.Arena) (models.NormalisedBattledomeItems, error)
 	GeneratedDropsByArena(arena models.Arena) (models.NormalisedBattledomeItems, error)
+	GeneratedDropsByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, error)
 	DropsByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, error)
 	DropsGroupedByMetadata() (map[models.BattledomeItemMetadata]models.NormalisedBattledomeItems, error)
 }
@@ -30,9 +31,9 @@ func (s *DataComparisonService) CompareByMetadata(metadata models.BattledomeItem
 		return nil, nil, stacktrace.Propagate(err, "failed to get drops by metadata for %q", metadata.String())
 	}
 
-	generatedData, err = s.BattledomeItems.GeneratedDropsByArena(metadata.Arena)
+	generatedData, err = s.BattledomeItems.GeneratedDropsByMetadata(metadata)
 	if err != nil {
-		return nil, nil, stacktrace.Propagate(err, "failed to generate drops by arena for %q", metadata.Arena)
+		return nil, nil, stacktrace.Propagate(err, "failed to generate drops for %q", metadata.String())
 	}
 
 	return realData, generatedData, nil
@@ -52,14 +53,14 @@ func (s *DataComparisonService) CompareArena(arena models.Arena) (realData model
 	return
 }
 
-func (s *DataComparisonService) CompareAllChallengers(itemPriceCache caches.ItemPriceCache) (challengerData []models.NormalisedBattledomeItems, err error) {
+func (s *DataComparisonService) CompareAllChallengers(valuation valuations.ItemValuation) (challengerData []models.NormalisedBattledomeItems, err error) {
 	data, err := s.BattledomeItems.DropsGroupedByMetadata()
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get drops grouped by metadata")
 	}
 
 	challengerData = helpers.OrderByDescending(helpers.Values(data), func(normalisedItems models.NormalisedBattledomeItems) float64 {
-		meanDropsProfit, err := normalisedItems.MeanDropsProfit(itemPriceCache)
+		meanDropsProfit, err := normalisedItems.MeanDropsProfit(valuation)
 		if err != nil {
 			return 0.0
 		}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+type SavedDropRecords interface {
+	Parse(filePath string) ([]models.DropRecord, error)
+	Save(records []models.DropRecord, filePath string) error
+}
+
+type SavedDropDataFiles interface {
+	Parse(filePath string) (*models.BattledomeItemsDto, error)
+	Save(dto *models.BattledomeItemsDto, filePath string) error
+}
+
+// Converts between drop files and the JSON/CSV interchange formats
+type DropDataExchangeService struct {
+	JsonDropRecords          SavedDropRecords
+	CsvDropRecords           SavedDropRecords
+	StrictSavedDropDataFiles SavedDropDataFiles
+}
+
+func NewDropDataExchangeService(jsonDropRecordParser SavedDropRecords, csvDropRecordParser SavedDropRecords, strictBattledomeItemDropDataParser SavedDropDataFiles) *DropDataExchangeService {
+	return &DropDataExchangeService{
+		JsonDropRecords:          jsonDropRecordParser,
+		CsvDropRecords:           csvDropRecordParser,
+		StrictSavedDropDataFiles: strictBattledomeItemDropDataParser,
+	}
+}
+
+func (s *DropDataExchangeService) dropRecords(format string) (SavedDropRecords, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return s.JsonDropRecords, nil
+	case "csv":
+		return s.CsvDropRecords, nil
+	default:
+		return nil, fmt.Errorf("unknown drop record format %q; expected json or csv", format)
+	}
+}
+
+// Files are exported as they are, even if they have problems lint would complain about; files that aren't named after a
+// date are skipped, since every record needs one. If contributorFilter is set, only
+// that contributor's files are exported. Returns the number of records written.
+func (s *DropDataExchangeService) Export(dropDataFolderPath string, format string, outputFilePath string, contributorFilter string) (int, error) {
+	dropRecords, err := s.dropRecords(format)
+	if err != nil {
+		return 0, err
+	}
+
+	files, err := helpers.FilesInFolder(dropDataFolderPath)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "failed to get files in %q", dropDataFolderPath)
+	}
+	slices.Sort(files)
+
+	records := []models.DropRecord{}
+	for _, file := range files {
+		if _, err := time.Parse(constants.DropDataFileNameLayout, file); err != nil {
+			slog.Warn(fmt.Sprintf("%s is not named after a date (%s); skipping it", file, constants.DropDataFileNameLayout))
+			continue
+		}
+
+		dto, err := s.StrictSavedDropDataFiles.Parse(filepath.Join(dropDataFolderPath, file))
+		if err != nil {
+			if _, ok := stacktrace.RootCause(err).(*models.DropDataParseErrors); !ok {
+				return 0, stacktrace.Propagate(err, "failed to parse %q as battledome drop data", file)
+			}
+			slog.Warn(fmt.Sprintf("%s has problems (see lint); exporting it as it is", file))
+		}
+
+		if contributorFilter != "" && !strings.EqualFold(models.ContributorName(dto.Metadata.Contributor), contributorFilter) {
+			continue
+		}
+
+		fileRecords, err := dto.Records()
+		if err != nil {
+			return 0, stacktrace.Propagate(err, "failed to convert %q to drop records", file)
+		}
+		records = append(records, fileRecords...)
+	}
+
+	if err = dropRecords.Save(records, outputFilePath); err != nil {
+		return 0, stacktrace.Propagate(err, "failed to save drop records to %s", outputFilePath)
+	}
+	return len(records), nil
+}
+
+// Writes one drop file per day in the input. Existing files are only replaced if shouldOverwrite is set, and nothing is
+// written unless every day passes the strict parser, apart from days with fewer drops than the limit. The days are
+// written to a scratch folder first and moved into place from there, and if moving any of them fails the ones already
+// moved are put back as they were. Returns the names of the files written.
+func (s *DropDataExchangeService) Import(format string, inputFilePath string, dropDataFolderPath string, shouldOverwrite bool) ([]string, error) {
+	dropRecords, err := s.dropRecords(format)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := dropRecords.Parse(inputFilePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse %s as %s drop records", inputFilePath, format)
+	}
+	dtos, err := models.DtosFromRecords(records)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to group the records in %s into days", inputFilePath)
+	}
+
+	if !shouldOverwrite {
+		existingFiles := helpers.Filter(helpers.Map(dtos, func(dto *models.BattledomeItemsDto) string {
+			return dto.Metadata.Source
+		}), func(file string) bool {
+			return helpers.IsFileExists(filepath.Join(dropDataFolderPath, file))
+		})
+		if len(existingFiles) > 0 {
+			return nil, fmt.Errorf("%s already exist; use --overwrite to replace them", strings.Join(existingFiles, ", "))
+		}
+	}
+
+	// In the drop data folder, so the files can be renamed into place
+	scratchFolderPath, err := os.MkdirTemp(dropDataFolderPath, ".import")
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to create a scratch folder in %s", dropDataFolderPath)
+	}
+	defer os.RemoveAll(scratchFolderPath)
+
+	problems := []models.DropDataDiagnostic{}
+	for _, dto := range dtos {
+		scratchFilePath := filepath.Join(scratchFolderPath, dto.Metadata.Source)
+		if err := s.StrictSavedDropDataFiles.Save(dto, scratchFilePath); err != nil {
+			return nil, stacktrace.Propagate(err, "failed to write a copy of %s", dto.Metadata.Source)
+		}
+		err := strictProblems(s.StrictSavedDropDataFiles, scratchFilePath, totalItemQuantity(dto.Battles))
+		if err != nil && !isDropDataParseErrors(err) {
+			return nil, stacktrace.Propagate(err, "failed to check %s", dto.Metadata.Source)
+		}
+		if err != nil {
+			problems = append(problems, stacktrace.RootCause(err).(*models.DropDataParseErrors).Diagnostics...)
+		}
+	}
+	if len(problems) > 0 {
+		return nil, stacktrace.Propagate(&models.DropDataParseErrors{Diagnostics: problems}, "refusing to import %s", filepath.Base(inputFilePath))
+	}
+
+	originals := map[string][]byte{}
+	for _, dto := range dtos {
+		dropDataFilePath := filepath.Join(dropDataFolderPath, dto.Metadata.Source)
+		if !helpers.IsFileExists(dropDataFilePath) {
+			continue
+		}
+		content, err := os.ReadFile(dropDataFilePath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to read %s", dropDataFilePath)
+		}
+		originals[dto.Metadata.Source] = content
+	}
+
+	writtenFiles := []string{}
+	for _, dto := range dtos {
+		err := os.Rename(filepath.Join(scratchFolderPath, dto.Metadata.Source), filepath.Join(dropDataFolderPath, dto.Metadata.Source))
+		if err == nil {
+			writtenFiles = append(writtenFiles, dto.Metadata.Source)
+			continue
+		}
+		for _, file := range writtenFiles {
+			filePath := filepath.Join(dropDataFolderPath, file)
+			if original, exists := originals[file]; exists {
+				err = errors.Join(err, os.WriteFile(filePath, original, 0644))
+			} else {
+				err = errors.Join(err, os.Remove(filePath))
+			}
+		}
+		return nil, stacktrace.Propagate(err, "failed to write %s, so nothing was imported", dto.Metadata.Source)
+	}
+	return writtenFiles, nil
+}
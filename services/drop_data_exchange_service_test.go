@@ -0,0 +1,81 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/parsers"
+)
+
+func TestExportSkipsFilesNotNamedAfterADate(t *testing.T) {
+	folder := t.TempDir()
+	writeDropFile(t, folder, "2025_01_01.txt", "# Kept\n$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\nBri Codestone|10\n")
+	writeDropFile(t, folder, "notes.txt", "Not drop data\n")
+
+	target := NewDropDataExchangeService(parsers.NewDropRecordJsonParser(), parsers.NewDropRecordCsvParser(), parsers.NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)))
+	count, err := target.Export(folder, "json", filepath.Join(t.TempDir(), "drops.json"), "")
+	if err != nil {
+		t.Fatalf("Expected notes.txt to be skipped, but the export failed: %s", err)
+	}
+	if count != 3 {
+		t.Fatalf("Expected a comment record and two item records, but got %d records", count)
+	}
+}
+
+func TestImportRefusesDaysThatFailTheStrictParser(t *testing.T) {
+	exported := t.TempDir()
+	writeDropFile(t, exported, "2025_01_01.txt", "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\n")
+	writeDropFile(t, exported, "2025_01_02.txt", "$ARENA:Centrl Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\nBri Codestone|11\n")
+
+	target := NewDropDataExchangeService(parsers.NewDropRecordJsonParser(), parsers.NewDropRecordCsvParser(), parsers.NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)))
+	inputFilePath := filepath.Join(t.TempDir(), "drops.json")
+	if _, err := target.Export(exported, "json", inputFilePath, ""); err != nil {
+		t.Fatalf("Failed to export: %s", err)
+	}
+
+	folder := t.TempDir()
+	_, err := target.Import("json", inputFilePath, folder, false)
+	if err == nil || !strings.Contains(err.Error(), `unknown arena "Centrl Arena"`) || !strings.Contains(err.Error(), "expected 15 drops but found 16") {
+		t.Fatalf("Expected the import to be refused for the uncatalogued arena and the extra drop, but got %v", err)
+	}
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		t.Fatalf("Failed to read the drop data folder: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("Expected a refused import to write nothing, but found %d entries", len(entries))
+	}
+}
+
+func TestImportWritesEveryDay(t *testing.T) {
+	exported := t.TempDir()
+	writeDropFile(t, exported, "2025_01_01.txt", "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\n")
+	writeDropFile(t, exported, "2025_01_02.txt", "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\nBri Codestone|10\n")
+
+	target := NewDropDataExchangeService(parsers.NewDropRecordJsonParser(), parsers.NewDropRecordCsvParser(), parsers.NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)))
+	inputFilePath := filepath.Join(t.TempDir(), "drops.json")
+	if _, err := target.Export(exported, "json", inputFilePath, ""); err != nil {
+		t.Fatalf("Failed to export: %s", err)
+	}
+
+	folder := t.TempDir()
+	writtenFiles, err := target.Import("json", inputFilePath, folder, false)
+	if err != nil {
+		t.Fatalf("Failed to import: %s", err)
+	}
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		t.Fatalf("Failed to read the drop data folder: %s", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	expected := []string{"2025_01_01.txt", "2025_01_02.txt"}
+	if !slices.Equal(writtenFiles, expected) || !slices.Equal(names, expected) {
+		t.Fatalf("Expected only %v to be written, but wrote %v and the folder has %v", expected, writtenFiles, names)
+	}
+}
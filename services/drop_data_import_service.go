@@ -0,0 +1,177 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+type SavedPrizeLogs interface {
+	Parse(filePath string) ([]*models.Battle, error)
+}
+
+type DropDataWriter interface {
+	Append(filePath string, battles []*models.Battle) error
+}
+
+type DropDataImportService struct {
+	SavedPrizeLogs
+	DropDataWriter
+	StrictSavedBattledomeItems SavedBattledomeItems
+}
+
+func NewDropDataImportService(prizeLogHtmlParser SavedPrizeLogs, dropDataWriter DropDataWriter, strictBattledomeItemDropDataParser SavedBattledomeItems) *DropDataImportService {
+	return &DropDataImportService{
+		SavedPrizeLogs:             prizeLogHtmlParser,
+		DropDataWriter:             dropDataWriter,
+		StrictSavedBattledomeItems: strictBattledomeItemDropDataParser,
+	}
+}
+
+// Non-empty fields of overrides replace whatever the page showed, for pages that don't show everything
+func applyMetadataOverrides(metadata models.BattledomeItemMetadata, overrides models.BattledomeItemMetadata) models.BattledomeItemMetadata {
+	if overrides.Arena != "" {
+		metadata.Arena = overrides.Arena
+	}
+	if overrides.Challenger != "" {
+		metadata.Challenger = overrides.Challenger
+	}
+	if overrides.Difficulty != "" {
+		metadata.Difficulty = overrides.Difficulty
+	}
+	return metadata
+}
+
+func isDropDataParseErrors(err error) bool {
+	_, ok := stacktrace.RootCause(err).(*models.DropDataParseErrors)
+	return ok
+}
+
+func totalItemQuantity(battles []*models.Battle) int {
+	return helpers.Sum(helpers.Map(battles, func(battle *models.Battle) int {
+		return battle.TotalItemQuantity()
+	}))
+}
+
+// Refuses battles that are already in the drop file, that would take the day over its drop limit, or that would leave
+// the file with problems the strict parser reports. A day with fewer drops than the limit is fine, since the rest of
+// its fights may not have been imported yet.
+func (s *DropDataImportService) checkImport(dropDataFilePath string, battles []*models.Battle) error {
+	fileName := filepath.Base(dropDataFilePath)
+	existingContent := []byte{}
+	existingBattles := []*models.Battle{}
+	if helpers.IsFileExists(dropDataFilePath) {
+		content, err := os.ReadFile(dropDataFilePath)
+		if err != nil {
+			return stacktrace.Propagate(err, "failed to read %s", dropDataFilePath)
+		}
+		existingContent = content
+
+		dto, err := s.StrictSavedBattledomeItems.Parse(dropDataFilePath)
+		if err != nil && !isDropDataParseErrors(err) {
+			return stacktrace.Propagate(err, "failed to parse %s", dropDataFilePath)
+		}
+		existingBattles = dto.Battles
+	}
+
+	existingSignatures := map[string]bool{}
+	for _, battle := range existingBattles {
+		existingSignatures[battleSignature(battle)] = true
+	}
+	for i, battle := range battles {
+		if existingSignatures[battleSignature(battle)] {
+			return fmt.Errorf("battle %d (%s) is already in %s", i+1, battle.String(), fileName)
+		}
+	}
+
+	existingDrops := totalItemQuantity(existingBattles)
+	importedDrops := totalItemQuantity(battles)
+	if existingDrops+importedDrops > constants.BattledomeDropsPerDay {
+		return fmt.Errorf("%s already has %d drop(s), so another %d would go over the limit of %d a day", fileName, existingDrops, importedDrops, constants.BattledomeDropsPerDay)
+	}
+
+	// Try the append on a copy first, so that the real file is only touched if the result is clean
+	scratchFolderPath, err := os.MkdirTemp("", "battledome_import")
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to create a scratch folder")
+	}
+	defer os.RemoveAll(scratchFolderPath)
+	scratchFilePath := filepath.Join(scratchFolderPath, fileName)
+	if err := os.WriteFile(scratchFilePath, existingContent, 0644); err != nil {
+		return stacktrace.Propagate(err, "failed to copy %s", dropDataFilePath)
+	}
+	if err := s.DropDataWriter.Append(scratchFilePath, battles); err != nil {
+		return stacktrace.Propagate(err, "failed to write imported battles to a copy of %s", fileName)
+	}
+	return strictProblems(s.StrictSavedBattledomeItems, scratchFilePath, existingDrops+importedDrops)
+}
+
+// The problems the strict parser finds in a drop file with the given number of drops, other than it having fewer drops
+// than a day should, since the rest of the day's fights may not have been recorded yet
+func strictProblems(strictSavedBattledomeItems SavedBattledomeItems, dropDataFilePath string, drops int) error {
+	_, err := strictSavedBattledomeItems.Parse(dropDataFilePath)
+	if err == nil {
+		return nil
+	}
+	if !isDropDataParseErrors(err) {
+		return stacktrace.Propagate(err, "failed to parse %s", dropDataFilePath)
+	}
+
+	incompleteDay := models.DropDataDiagnostic{
+		File:    filepath.Base(dropDataFilePath),
+		Message: models.DropCountMessage(constants.BattledomeDropsPerDay, drops),
+	}
+	problems := helpers.Filter(stacktrace.RootCause(err).(*models.DropDataParseErrors).Diagnostics, func(diagnostic models.DropDataDiagnostic) bool {
+		return drops > constants.BattledomeDropsPerDay || diagnostic != incompleteDay
+	})
+	if len(problems) > 0 {
+		return &models.DropDataParseErrors{Diagnostics: problems}
+	}
+	return nil
+}
+
+// Reads the battles off a saved results/prize log page and appends them to the drop file for the given date, as long as
+// the result passes checkImport. Returns the path of the drop file and the battles that were written to it.
+func (s *DropDataImportService) ImportHtml(htmlFilePath string, dropDataFolderPath string, date time.Time, overrides models.BattledomeItemMetadata) (string, []*models.Battle, error) {
+	battles, err := s.SavedPrizeLogs.Parse(htmlFilePath)
+	if err != nil {
+		return "", nil, stacktrace.Propagate(err, "failed to read battles from %s", htmlFilePath)
+	}
+
+	for i, battle := range battles {
+		battle.Metadata = applyMetadataOverrides(battle.Metadata, overrides)
+		for _, item := range battle.Items {
+			item.Metadata = battle.Metadata
+		}
+
+		missing := []string{}
+		if battle.Metadata.Arena == "" {
+			missing = append(missing, "arena (--arena)")
+		}
+		if battle.Metadata.Challenger == "" {
+			missing = append(missing, "challenger (--challenger)")
+		}
+		if battle.Metadata.Difficulty == "" {
+			missing = append(missing, "difficulty (--difficulty)")
+		}
+		if len(missing) > 0 {
+			return "", nil, fmt.Errorf("battle %d in %s does not show the %s; please provide it", i+1, filepath.Base(htmlFilePath), strings.Join(missing, ", "))
+		}
+	}
+
+	dropDataFilePath := filepath.Join(dropDataFolderPath, date.Format(constants.DropDataFileNameLayout))
+	if err := s.checkImport(dropDataFilePath, battles); err != nil {
+		return "", nil, stacktrace.Propagate(err, "refusing to import %s into %s", filepath.Base(htmlFilePath), filepath.Base(dropDataFilePath))
+	}
+	if err := s.DropDataWriter.Append(dropDataFilePath, battles); err != nil {
+		return "", nil, stacktrace.Propagate(err, "failed to write imported battles to %s", dropDataFilePath)
+	}
+	return dropDataFilePath, battles, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/parsers"
+)
+
+type fixedPrizeLogs struct {
+	battles []*models.Battle
+}
+
+func (l *fixedPrizeLogs) Parse(filePath string) ([]*models.Battle, error) {
+	return l.battles, nil
+}
+
+func prizeLogBattle(arena models.Arena, quantities map[string]int32) *models.Battle {
+	return fightBattle(models.BattledomeItemMetadata{Arena: arena, Challenger: "Flaming Meerca", Difficulty: "Mighty"}, quantities)
+}
+
+func importHtml(t *testing.T, folder string, battles ...*models.Battle) error {
+	parser := parsers.NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t))
+	target := NewDropDataImportService(&fixedPrizeLogs{battles: battles}, parser, parser)
+	_, _, err := target.ImportHtml("prize_log.html", folder, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), models.BattledomeItemMetadata{})
+	return err
+}
+
+func TestImportHtmlAppendsToAnIncompleteDay(t *testing.T) {
+	folder := t.TempDir()
+	if err := importHtml(t, folder, prizeLogBattle("Central Arena", map[string]int32{"Orn Codestone": 5})); err != nil {
+		t.Fatalf("Failed to import the first battle: %s", err)
+	}
+	if err := importHtml(t, folder, prizeLogBattle("Central Arena", map[string]int32{"Bri Codestone": 10})); err != nil {
+		t.Fatalf("Failed to import the second battle: %s", err)
+	}
+
+	dto, err := parsers.NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)).Parse(filepath.Join(folder, "2025_01_01.txt"))
+	if err != nil {
+		t.Fatalf("Expected the completed day to pass the strict parser, but got %s", err)
+	}
+	if len(dto.Battles) != 2 {
+		t.Fatalf("Expected both battles to be in the file, but got %d", len(dto.Battles))
+	}
+}
+
+func TestImportHtmlRefusesBadBattles(t *testing.T) {
+	existing := "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\n"
+	cases := map[string]struct {
+		battle   *models.Battle
+		expected string
+	}{
+		"duplicate":      {prizeLogBattle("Central Arena", map[string]int32{"Orn Codestone": 5}), "is already in 2025_01_01.txt"},
+		"over the limit": {prizeLogBattle("Central Arena", map[string]int32{"Bri Codestone": 11}), "would go over the limit of 15 a day"},
+		"uncatalogued":   {prizeLogBattle("Centrl Arena", map[string]int32{"Bri Codestone": 1}), `unknown arena "Centrl Arena"`},
+	}
+	for name, testCase := range cases {
+		folder := t.TempDir()
+		writeDropFile(t, folder, "2025_01_01.txt", existing)
+
+		err := importHtml(t, folder, testCase.battle)
+		if err == nil || !strings.Contains(err.Error(), testCase.expected) {
+			t.Fatalf("Expected the %s battle to be refused with %q, but got %v", name, testCase.expected, err)
+		}
+		content, err := os.ReadFile(filepath.Join(folder, "2025_01_01.txt"))
+		if err != nil {
+			t.Fatalf("Failed to read the drop file: %s", err)
+		}
+		if string(content) != existing {
+			t.Fatalf("Expected a refused %s battle to leave the drop file alone, but it became %q", name, string(content))
+		}
+	}
+}
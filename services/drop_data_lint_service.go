@@ -0,0 +1,475 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	maxSuggestionDistance = 4
+)
+
+type LintSeverity int
+
+const (
+	LintError LintSeverity = iota
+	LintWarning
+	LintInfo
+)
+
+func (s LintSeverity) String() string {
+	switch s {
+	case LintError:
+		return "error"
+	case LintWarning:
+		return "warning"
+	case LintInfo:
+		return "info"
+	default:
+		return "?"
+	}
+}
+
+type LintFinding struct {
+	Severity LintSeverity
+	models.DropDataDiagnostic
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.Severity, f.DropDataDiagnostic.String())
+}
+
+type LintReport struct {
+	FilesChecked int
+	Findings     []LintFinding
+	FixedFiles   []string
+}
+
+func (r *LintReport) Count(severity LintSeverity) int {
+	return helpers.Count(r.Findings, func(finding LintFinding) bool {
+		return finding.Severity == severity
+	})
+}
+
+func (r *LintReport) HasErrors() bool {
+	return r.Count(LintError) > 0
+}
+
+// Unknown arenas, challengers and difficulties are reported by the strict parser, which checks them against the
+// battledome catalogue
+type DropDataLintService struct {
+	StrictSavedBattledomeItems SavedBattledomeItems
+	SavedBattledomeItemWeights
+	ItemAliases         *helpers.ItemAliases
+	BattledomeCatalogue *helpers.BattledomeCatalogue
+}
+
+func NewDropDataLintService(strictBattledomeItemDropDataParser SavedBattledomeItems, savedBattledomeItemWeights SavedBattledomeItemWeights, itemAliases *helpers.ItemAliases, battledomeCatalogue *helpers.BattledomeCatalogue) *DropDataLintService {
+	return &DropDataLintService{
+		StrictSavedBattledomeItems: strictBattledomeItemDropDataParser,
+		SavedBattledomeItemWeights: savedBattledomeItemWeights,
+		ItemAliases:                itemAliases,
+		BattledomeCatalogue:        battledomeCatalogue,
+	}
+}
+
+// What the rest of the dataset says a name should look like
+type lintVocabulary struct {
+	// Keyed on the lower-cased name
+	items        map[string]string
+	arenas       map[string]string
+	challengers  map[string]string
+	difficulties map[string]string
+
+	// Items that are in the weights file or were seen in more than one file
+	knownItems     map[string]bool
+	knownItemNames []string
+}
+
+type lintedFile struct {
+	name        string
+	lines       []string
+	dto         *models.BattledomeItemsDto
+	diagnostics []models.DropDataDiagnostic
+}
+
+func readDropDataLines(filePath string) ([]string, bool, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, false, stacktrace.Propagate(err, "failed to read %s", filePath)
+	}
+	text := strings.ReplaceAll(strings.TrimPrefix(string(content), "\uFEFF"), "\r\n", "\n")
+	hasTrailingNewline := strings.HasSuffix(text, "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n"), hasTrailingNewline, nil
+}
+
+func (s *DropDataLintService) parseAll(folderPath string, files []string) ([]*lintedFile, error) {
+	linted := []*lintedFile{}
+	for _, file := range files {
+		filePath := filepath.Join(folderPath, file)
+		lines, _, err := readDropDataLines(filePath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to read %q", file)
+		}
+
+		dto, err := s.StrictSavedBattledomeItems.Parse(filePath)
+		diagnostics := []models.DropDataDiagnostic{}
+		if err != nil {
+			parseErrors, ok := stacktrace.RootCause(err).(*models.DropDataParseErrors)
+			if !ok {
+				return nil, stacktrace.Propagate(err, "failed to parse %q as battledome drop data", file)
+			}
+			diagnostics = parseErrors.Diagnostics
+		}
+
+		linted = append(linted, &lintedFile{
+			name:        file,
+			lines:       lines,
+			dto:         dto,
+			diagnostics: diagnostics,
+		})
+	}
+	return linted, nil
+}
+
+// Picks the most common spelling of each name, ignoring case
+func canonicalCasing(names []string, preferred []string) map[string]string {
+	counts := map[string]int{}
+	for _, name := range names {
+		counts[name]++
+	}
+
+	canonical := map[string]string{}
+	for _, name := range helpers.OrderBy(helpers.Distinct(names), func(name string) string { return name }) {
+		key := strings.ToLower(name)
+		current, exists := canonical[key]
+		if !exists || counts[name] > counts[current] {
+			canonical[key] = name
+		}
+	}
+	for _, name := range preferred {
+		canonical[strings.ToLower(name)] = name
+	}
+	return canonical
+}
+
+func (s *DropDataLintService) vocabulary(linted []*lintedFile) (*lintVocabulary, error) {
+	weights, err := s.SavedBattledomeItemWeights.Parse(constants.ItemWeightsFilePath())
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse %q as item weights", constants.ItemWeightsFilePath())
+	}
+
+	trustedItems := helpers.Map(weights, func(weight models.BattledomeItemWeight) string {
+		return weight.Name
+	})
+	trustedItems = append(trustedItems, s.BattledomeCatalogue.ExtraDropNames()...)
+	catalogueChallengers := helpers.FlatMap(s.BattledomeCatalogue.ArenaNames(), s.BattledomeCatalogue.ChallengerNames)
+
+	itemNames := []string{}
+	challengerNames := []string{}
+	difficultyNames := []string{}
+	filesPerItem := map[string]int{}
+	for _, file := range linted {
+		if file.dto == nil {
+			continue
+		}
+		battleMetadata := helpers.Distinct(helpers.Map(file.dto.Battles, func(battle *models.Battle) models.BattledomeItemMetadata {
+			return battle.Metadata
+		}))
+		for _, metadata := range battleMetadata {
+			challengerNames = append(challengerNames, string(metadata.Challenger))
+			difficultyNames = append(difficultyNames, string(metadata.Difficulty))
+		}
+		for _, itemName := range helpers.Distinct(helpers.Map(file.dto.Items, func(item *models.BattledomeItem) string {
+			return string(item.Name)
+		})) {
+			itemNames = append(itemNames, itemName)
+			filesPerItem[itemName]++
+		}
+	}
+
+	vocabulary := &lintVocabulary{
+		items:        canonicalCasing(itemNames, trustedItems),
+		arenas:       canonicalCasing(nil, s.BattledomeCatalogue.ArenaNames()),
+		challengers:  canonicalCasing(challengerNames, catalogueChallengers),
+		difficulties: canonicalCasing(difficultyNames, s.BattledomeCatalogue.Difficulties),
+		knownItems:   map[string]bool{},
+	}
+	for _, itemName := range trustedItems {
+		vocabulary.knownItems[itemName] = true
+	}
+	for itemName, count := range filesPerItem {
+		if count > 1 {
+			vocabulary.knownItems[itemName] = true
+		}
+	}
+	vocabulary.knownItemNames = helpers.Map(helpers.ToSlice(vocabulary.knownItems), func(tuple helpers.Tuple) string {
+		return tuple.Elements[0].(string)
+	})
+	slices.Sort(vocabulary.knownItemNames)
+	return vocabulary, nil
+}
+
+func canonicalOrSelf(canonical map[string]string, name string) string {
+	if match, exists := canonical[strings.ToLower(name)]; exists {
+		return match
+	}
+	return name
+}
+
+// Only ever changes whitespace and casing; anything that looks wrong in another way is left for a human. Blank lines
+// and comments are kept so that the layout of the file survives
+func (v *lintVocabulary) normaliseLine(line string) string {
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "", strings.HasPrefix(line, "#"):
+		return line
+	case strings.HasPrefix(line, "$"):
+		separatorIndex := strings.Index(line, ":")
+		if separatorIndex == -1 {
+			return line
+		}
+		key := strings.ToUpper(strings.TrimSpace(line[:separatorIndex]))
+		value := helpers.CollapseWhitespace(line[separatorIndex+1:])
+		switch strings.ToLower(key) {
+		case "$arena":
+			value = canonicalOrSelf(v.arenas, value)
+		case "$challenger":
+			value = canonicalOrSelf(v.challengers, value)
+		case "$difficulty":
+			value = canonicalOrSelf(v.difficulties, value)
+		}
+		return key + ":" + value
+	default:
+		separatorIndex := strings.LastIndex(line, "|")
+		if separatorIndex == -1 {
+			return line
+		}
+		itemName := canonicalOrSelf(v.items, helpers.CollapseWhitespace(line[:separatorIndex]))
+		return itemName + "|" + strings.TrimSpace(line[separatorIndex+1:])
+	}
+}
+
+func (s *DropDataLintService) fix(folderPath string, file string, vocabulary *lintVocabulary) (bool, error) {
+	filePath := filepath.Join(folderPath, file)
+	lines, hasTrailingNewline, err := readDropDataLines(filePath)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "failed to read %q", file)
+	}
+
+	fixedLines := helpers.Map(lines, vocabulary.normaliseLine)
+	if slices.Equal(lines, fixedLines) {
+		return false, nil
+	}
+
+	fixed := strings.Join(fixedLines, "\n") + helpers.When(hasTrailingNewline, "\n", "")
+	if err = os.WriteFile(filePath, []byte(fixed), 0644); err != nil {
+		return false, stacktrace.Propagate(err, "failed to write fixes to %q", file)
+	}
+	return true, nil
+}
+
+// 1-based line number of the first line matching the predicate, or 0 if there isn't one
+func lineNumberOf(lines []string, predicate func(string) bool) int {
+	for i, line := range lines {
+		if predicate(line) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func metadataLineNumber(lines []string, key string, value string) int {
+	return lineNumberOf(lines, func(line string) bool {
+		line = strings.TrimSpace(line)
+		separatorIndex := strings.Index(line, ":")
+		return separatorIndex != -1 &&
+			strings.ToLower(strings.TrimSpace(line[:separatorIndex])) == key &&
+			strings.TrimSpace(line[separatorIndex+1:]) == value
+	})
+}
+
+// The item name as written on the line, or "" if it isn't an item line
+func rawItemName(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "$") || strings.HasPrefix(line, "#") {
+		return ""
+	}
+	separatorIndex := strings.LastIndex(line, "|")
+	if separatorIndex == -1 {
+		return ""
+	}
+	return strings.TrimSpace(line[:separatorIndex])
+}
+
+func (s *DropDataLintService) itemLineNumber(lines []string, itemName models.ItemName) int {
+	return lineNumberOf(lines, func(line string) bool {
+		rawName := rawItemName(line)
+		return rawName != "" && s.ItemAliases.Canonical(rawName) == string(itemName)
+	})
+}
+
+func didYouMean(name string, candidates []string) string {
+	suggestions := helpers.ClosestMatches(name, candidates, maxSuggestionDistance)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("; did you mean %q?", suggestions[0])
+}
+
+func (s *DropDataLintService) lintFile(file *lintedFile, vocabulary *lintVocabulary) []LintFinding {
+	findings := helpers.Map(file.diagnostics, func(diagnostic models.DropDataDiagnostic) LintFinding {
+		return LintFinding{Severity: LintError, DropDataDiagnostic: diagnostic}
+	})
+	if file.dto == nil {
+		return findings
+	}
+
+	finding := func(severity LintSeverity, line int, message string) LintFinding {
+		return LintFinding{
+			Severity: severity,
+			DropDataDiagnostic: models.DropDataDiagnostic{
+				File:    file.name,
+				Line:    line,
+				Column:  helpers.When(line == 0, 0, 1),
+				Message: message,
+			},
+		}
+	}
+
+	for i, line := range file.lines {
+		rawName := rawItemName(line)
+		if rawName == "" {
+			continue
+		}
+		if canonicalName := s.ItemAliases.Canonical(rawName); canonicalName != rawName {
+			findings = append(findings, finding(LintInfo, i+1, fmt.Sprintf("item %q was read as %q", rawName, canonicalName)))
+		}
+	}
+
+	for _, item := range file.dto.Items {
+		if vocabulary.knownItems[string(item.Name)] {
+			continue
+		}
+		findings = append(findings, finding(LintWarning, s.itemLineNumber(file.lines, item.Name), fmt.Sprintf("item %q has never been seen before%s", item.Name, didYouMean(string(item.Name), vocabulary.knownItemNames))))
+	}
+
+	return findings
+}
+
+func lintDuplicates(linted []*lintedFile) []LintFinding {
+	fingerprints := []dropFileFingerprint{}
+	for _, file := range linted {
+		if file.dto == nil {
+			continue
+		}
+		fingerprints = append(fingerprints, newDropFileFingerprint(file.name, []byte(strings.Join(file.lines, "\n")), file.dto))
+	}
+	return helpers.Map(findDuplicateDropFiles(fingerprints), func(duplicate DuplicateDropFiles) LintFinding {
+		return LintFinding{
+			Severity: helpers.When(constants.ShouldFailOnDuplicateDropFiles, LintError, LintWarning),
+			DropDataDiagnostic: models.DropDataDiagnostic{
+				File:    duplicate.Second,
+				Message: fmt.Sprintf("looks like a copy of %s (%s)", duplicate.First, helpers.When(duplicate.IsExact, "identical content", "same metadata and drops")),
+			},
+		}
+	})
+}
+
+func lintDates(files []string) []LintFinding {
+	findings := []LintFinding{}
+	dates := []time.Time{}
+	for _, file := range files {
+		date, err := time.Parse(constants.DropDataFileNameLayout, file)
+		if err != nil {
+			findings = append(findings, LintFinding{
+				Severity: LintWarning,
+				DropDataDiagnostic: models.DropDataDiagnostic{
+					File:    file,
+					Message: fmt.Sprintf("file name is not a date in the form %s", constants.DropDataFileNameLayout),
+				},
+			})
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	slices.SortFunc(dates, func(first time.Time, second time.Time) int {
+		return first.Compare(second)
+	})
+	for i := 1; i < len(dates); i++ {
+		missingDays := int(dates[i].Sub(dates[i-1]).Hours()/24) - 1
+		if missingDays <= 0 {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			Severity: LintWarning,
+			DropDataDiagnostic: models.DropDataDiagnostic{
+				File: dates[i].Format(constants.DropDataFileNameLayout),
+				Message: fmt.Sprintf("%d day(s) missing since %s (%s to %s)",
+					missingDays,
+					dates[i-1].Format(constants.DropDataFileNameLayout),
+					dates[i-1].AddDate(0, 0, 1).Format(time.DateOnly),
+					dates[i].AddDate(0, 0, -1).Format(time.DateOnly),
+				),
+			},
+		})
+	}
+	return findings
+}
+
+// Checks every file in the folder; with shouldFix, whitespace and casing problems are fixed in place first
+func (s *DropDataLintService) Lint(folderPath string, shouldFix bool) (*LintReport, error) {
+	files, err := helpers.FilesInFolder(folderPath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get files in %q", folderPath)
+	}
+	slices.Sort(files)
+
+	linted, err := s.parseAll(folderPath, files)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse drop data")
+	}
+	vocabulary, err := s.vocabulary(linted)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to build vocabulary of known names")
+	}
+
+	report := &LintReport{
+		FilesChecked: len(files),
+		Findings:     []LintFinding{},
+		FixedFiles:   []string{},
+	}
+	if shouldFix {
+		for _, file := range files {
+			isFixed, err := s.fix(folderPath, file, vocabulary)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "failed to fix %q", file)
+			}
+			if isFixed {
+				report.FixedFiles = append(report.FixedFiles, file)
+			}
+		}
+		if len(report.FixedFiles) > 0 {
+			if linted, err = s.parseAll(folderPath, files); err != nil {
+				return nil, stacktrace.Propagate(err, "failed to parse drop data after fixing it")
+			}
+		}
+	}
+
+	for _, file := range linted {
+		report.Findings = append(report.Findings, s.lintFile(file, vocabulary)...)
+	}
+	report.Findings = append(report.Findings, lintDates(files)...)
+	report.Findings = append(report.Findings, lintDuplicates(linted)...)
+	return report, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/parsers"
+)
+
+func testBattledomeCatalogue(t *testing.T) *helpers.BattledomeCatalogue {
+	catalogue, err := helpers.NewBattledomeCatalogue(constants.BattledomeCatalogueFilePath())
+	if err != nil {
+		t.Fatalf("Failed to load the battledome catalogue: %s", err)
+	}
+	return catalogue
+}
+
+func newTestDropDataLintService(t *testing.T) *DropDataLintService {
+	itemAliases := helpers.NewItemAliases(filepath.Join(t.TempDir(), "aliases.txt"))
+	itemAliases.AddCanonicalNames("Orn Codestone", "Bri Codestone")
+	return NewDropDataLintService(
+		parsers.NewStrictBattledomeItemDropDataParser(testBattledomeCatalogue(t)),
+		&fakeItemWeights{weights: []models.BattledomeItemWeight{
+			{Arena: "Central Arena", Name: "Orn Codestone", Weight: 1},
+			{Arena: "Central Arena", Name: "Bri Codestone", Weight: 1},
+		}},
+		itemAliases,
+		testBattledomeCatalogue(t),
+	)
+}
+
+func lintFindings(report *LintReport) []string {
+	return helpers.Map(report.Findings, func(finding LintFinding) string {
+		return finding.String()
+	})
+}
+
+func TestLintReportsFindingsBySeverity(t *testing.T) {
+	folder := t.TempDir()
+	writeDropFile(t, folder, "2025_01_01.txt", "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\nBri Codestone|10\n")
+	writeDropFile(t, folder, "2025_01_03.txt", "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\norn codestone|10\nOrn Codestonx|4\n")
+
+	report, err := newTestDropDataLintService(t).Lint(folder, false)
+	if err != nil {
+		t.Fatalf("Failed to lint: %s", err)
+	}
+	expected := []string{
+		"error: 2025_01_03.txt: expected 15 drops but found 14",
+		`info: 2025_01_03.txt:4:1: item "orn codestone" was read as "Orn Codestone"`,
+		`warning: 2025_01_03.txt:5:1: item "Orn Codestonx" has never been seen before; did you mean "Orn Codestone"?`,
+		"warning: 2025_01_03.txt: 1 day(s) missing since 2025_01_01.txt (2025-01-02 to 2025-01-02)",
+	}
+	if !slices.Equal(lintFindings(report), expected) {
+		t.Fatalf("Expected findings %q, but got %q", expected, lintFindings(report))
+	}
+	if report.FilesChecked != 2 || report.Count(LintError) != 1 || report.Count(LintWarning) != 2 || report.Count(LintInfo) != 1 {
+		t.Fatalf("Expected 2 files with 1 error, 2 warnings and 1 info, but got %d files with %d, %d and %d", report.FilesChecked, report.Count(LintError), report.Count(LintWarning), report.Count(LintInfo))
+	}
+	if !report.HasErrors() {
+		t.Fatalf("Expected the report to fail the lint")
+	}
+}
+
+func TestLintPassesCleanDropData(t *testing.T) {
+	folder := t.TempDir()
+	writeDropFile(t, folder, "2025_01_01.txt", "$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\nOrn Codestone|5\nBri Codestone|10\n")
+
+	report, err := newTestDropDataLintService(t).Lint(folder, false)
+	if err != nil {
+		t.Fatalf("Failed to lint: %s", err)
+	}
+	if len(report.Findings) != 0 || report.HasErrors() {
+		t.Fatalf("Expected no findings, but got %q", lintFindings(report))
+	}
+}
+
+func TestLintFixKeepsBlankLinesAndComments(t *testing.T) {
+	folder := t.TempDir()
+	writeDropFile(t, folder, "2025_01_01.txt", "# Morning fights\n  $arena :  central arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\n\norn   codestone |5\n   \nBri Codestone|10\n")
+
+	report, err := newTestDropDataLintService(t).Lint(folder, true)
+	if err != nil {
+		t.Fatalf("Failed to lint: %s", err)
+	}
+	if !slices.Equal(report.FixedFiles, []string{"2025_01_01.txt"}) {
+		t.Fatalf("Expected 2025_01_01.txt to be fixed, but fixed %v", report.FixedFiles)
+	}
+	if report.HasErrors() {
+		t.Fatalf("Expected no errors after fixing, but got %q", lintFindings(report))
+	}
+
+	content, err := os.ReadFile(filepath.Join(folder, "2025_01_01.txt"))
+	if err != nil {
+		t.Fatalf("Failed to read the fixed file: %s", err)
+	}
+	expected := "# Morning fights\n$ARENA:Central Arena\n$CHALLENGER:Flaming Meerca\n$DIFFICULTY:Mighty\n\nOrn Codestone|5\n\nBri Codestone|10\n"
+	if string(content) != expected {
+		t.Fatalf("Expected the fixed file to be %q, but got %q", expected, string(content))
+	}
+
+	report, err = newTestDropDataLintService(t).Lint(folder, true)
+	if err != nil {
+		t.Fatalf("Failed to lint: %s", err)
+	}
+	if len(report.FixedFiles) != 0 {
+		t.Fatalf("Expected an already fixed file to be left alone, but fixed %v", report.FixedFiles)
+	}
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+// A drop file as it was when it was last parsed
+type IndexedDropFile struct {
+	Name    string
+	ModTime time.Time
+	Size    int64
+	// See dropFileContentHash
+	ContentHash string
+	// See dropFileSignature
+	Signature string
+	// Zero if the file name isn't a date
+	Date time.Time
+	// Items is left empty when saved, as it is just every battle's items
+	Dto *models.BattledomeItemsDto
+}
+
+func (f *IndexedDropFile) fingerprint() dropFileFingerprint {
+	return dropFileFingerprint{
+		file:        f.Name,
+		contentHash: f.ContentHash,
+		signature:   f.Signature,
+	}
+}
+
+type dropDataIndex struct {
+	Version int
+	// Hash of the files that affect how drop files are parsed, e.g. the item aliases
+	DependencyHash string
+	Files          map[string]*IndexedDropFile
+}
+
+// Empty fields match anything. From and To are inclusive and only match files named after a date.
+type DropDataQuery struct {
+	Arena       models.Arena
+	Challenger  models.Challenger
+	Difficulty  models.Difficulty
+	Contributor string
+	From        time.Time
+	To          time.Time
+}
+
+func (q DropDataQuery) matchesFile(file *IndexedDropFile) bool {
+	if q.Contributor != "" && !strings.EqualFold(models.ContributorName(file.Dto.Metadata.Contributor), q.Contributor) {
+		return false
+	}
+	if (!q.From.IsZero() || !q.To.IsZero()) && file.Date.IsZero() {
+		return false
+	}
+	if !q.From.IsZero() && file.Date.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && file.Date.After(q.To) {
+		return false
+	}
+	return true
+}
+
+func (q DropDataQuery) matchesBattle(battle *models.Battle) bool {
+	return (q.Arena == "" || battle.Metadata.Arena == q.Arena) &&
+		(q.Challenger == "" || battle.Metadata.Challenger == q.Challenger) &&
+		(q.Difficulty == "" || battle.Metadata.Difficulty == q.Difficulty)
+}
+
+// Keeps every parsed drop file in an index on disk, so that only files that have changed since the last run are
+// parsed again
+type DropDataStore struct {
+	SavedBattledomeItems
+	folderPath    string
+	indexFilePath string
+	index         *dropDataIndex
+}
+
+func NewDropDataStore(battledomeItemDropDataParser SavedBattledomeItems, folderPath string, indexFilePath string) *DropDataStore {
+	return &DropDataStore{
+		SavedBattledomeItems: battledomeItemDropDataParser,
+		folderPath:           folderPath,
+		indexFilePath:        indexFilePath,
+	}
+}
+
+func dependencyHash() string {
+	hash := sha256.New()
+	for _, filePath := range []string{constants.ItemAliasesFilePath(), constants.ItemWeightsFilePath(), constants.BattledomeCatalogueFilePath(), constants.ItemGroupsFilePath()} {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		hash.Write([]byte(filePath))
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func newDropDataIndex() *dropDataIndex {
+	return &dropDataIndex{
+		Version:        constants.DropDataIndexVersion,
+		DependencyHash: dependencyHash(),
+		Files:          map[string]*IndexedDropFile{},
+	}
+}
+
+func (s *DropDataStore) load() (*dropDataIndex, error) {
+	file, err := os.Open(s.indexFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return newDropDataIndex(), nil
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to open %q", s.indexFilePath)
+	}
+	defer file.Close()
+
+	index := &dropDataIndex{}
+	if err := gob.NewDecoder(file).Decode(index); err != nil {
+		slog.Warn(fmt.Sprintf("Drop data index at %q could not be read and will be rebuilt: %s", s.indexFilePath, err))
+		return newDropDataIndex(), nil
+	}
+	if index.Version != constants.DropDataIndexVersion || index.DependencyHash != dependencyHash() {
+		return newDropDataIndex(), nil
+	}
+	for _, indexedFile := range index.Files {
+		indexedFile.Dto.Items = helpers.FlatMap(indexedFile.Dto.Battles, func(battle *models.Battle) []*models.BattledomeItem {
+			return battle.Items
+		})
+	}
+	return index, nil
+}
+
+func (s *DropDataStore) save() error {
+	temporaryFilePath := s.indexFilePath + ".tmp"
+	file, err := os.Create(temporaryFilePath)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to create %q", temporaryFilePath)
+	}
+
+	files := map[string]*IndexedDropFile{}
+	for name, indexedFile := range s.index.Files {
+		dto := *indexedFile.Dto
+		dto.Items = nil
+		indexedFileWithoutItems := *indexedFile
+		indexedFileWithoutItems.Dto = &dto
+		files[name] = &indexedFileWithoutItems
+	}
+	err = gob.NewEncoder(file).Encode(&dropDataIndex{
+		Version:        s.index.Version,
+		DependencyHash: s.index.DependencyHash,
+		Files:          files,
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temporaryFilePath)
+		return stacktrace.Propagate(err, "failed to write drop data index to %q", temporaryFilePath)
+	}
+	if err := os.Rename(temporaryFilePath, s.indexFilePath); err != nil {
+		return stacktrace.Propagate(err, "failed to replace %q", s.indexFilePath)
+	}
+	return nil
+}
+
+// Brings the index up to date with the folder, parsing new and changed files and dropping deleted ones.
+// Files are considered unchanged if their size and modification time match; if those differ but the content hash
+// doesn't, the file isn't parsed again either.
+func (s *DropDataStore) Refresh() error {
+	if s.index == nil {
+		index, err := s.load()
+		if err != nil {
+			return stacktrace.Propagate(err, "failed to load drop data index")
+		}
+		s.index = index
+	}
+
+	files, err := helpers.FilesInFolder(s.folderPath)
+	if err != nil {
+		return stacktrace.Propagate(err, "failed to get files in %q", s.folderPath)
+	}
+
+	hasChanged := false
+	for _, file := range files {
+		filePath := filepath.Join(s.folderPath, file)
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return stacktrace.Propagate(err, "failed to stat %q", file)
+		}
+		indexedFile, isIndexed := s.index.Files[file]
+		if isIndexed && indexedFile.Size == info.Size() && indexedFile.ModTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		hasChanged = true
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return stacktrace.Propagate(err, "failed to read %q", file)
+		}
+		contentHash := dropFileContentHash(content)
+		if isIndexed && indexedFile.ContentHash == contentHash {
+			indexedFile.Size = info.Size()
+			indexedFile.ModTime = info.ModTime()
+			continue
+		}
+
+		dto, err := s.SavedBattledomeItems.Parse(filePath)
+		if err != nil {
+			return stacktrace.Propagate(err, "failed to parse %q as battledome drop data", file)
+		}
+		date, err := time.Parse(constants.DropDataFileNameLayout, file)
+		if err != nil {
+			date = time.Time{}
+		}
+		s.index.Files[file] = &IndexedDropFile{
+			Name:        file,
+			ModTime:     info.ModTime(),
+			Size:        info.Size(),
+			ContentHash: contentHash,
+			Signature:   dropFileSignature(dto),
+			Date:        date,
+			Dto:         dto,
+		}
+	}
+	for file := range s.index.Files {
+		if !slices.Contains(files, file) {
+			hasChanged = true
+			delete(s.index.Files, file)
+		}
+	}
+
+	if hasChanged {
+		if err := s.save(); err != nil {
+			return stacktrace.Propagate(err, "failed to save drop data index")
+		}
+	}
+	return nil
+}
+
+// Every indexed file, ordered by name
+func (s *DropDataStore) Files() ([]*IndexedDropFile, error) {
+	if err := s.Refresh(); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to refresh drop data index")
+	}
+	return helpers.OrderBy(helpers.Values(s.index.Files), func(file *IndexedDropFile) string {
+		return file.Name
+	}), nil
+}
+
+// The battles that match the query, ordered by file name
+func (s *DropDataStore) Battles(query DropDataQuery) ([]*models.Battle, error) {
+	files, err := s.Files()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get indexed drop files")
+	}
+	battles := []*models.Battle{}
+	for _, file := range files {
+		if !query.matchesFile(file) {
+			continue
+		}
+		battles = append(battles, helpers.Filter(file.Dto.Battles, query.matchesBattle)...)
+	}
+	return battles, nil
+}
+
+func (s *DropDataStore) Duplicates() ([]DuplicateDropFiles, error) {
+	files, err := s.Files()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get indexed drop files")
+	}
+	return findDuplicateDropFiles(helpers.Map(files, func(file *IndexedDropFile) dropFileFingerprint {
+		return file.fingerprint()
+	})), nil
+}
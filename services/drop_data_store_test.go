@@ -0,0 +1,77 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+// Reads the arena from the first line and one item per line after that
+type countingDropDataParser struct {
+	parsedFiles []string
+}
+
+func (p *countingDropDataParser) Parse(filePath string) (*models.BattledomeItemsDto, error) {
+	p.parsedFiles = append(p.parsedFiles, filepath.Base(filePath))
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	dto := &models.BattledomeItemsDto{}
+	dto.Metadata.Source = filepath.Base(filePath)
+	battle := dto.StartBattle()
+	battle.Metadata.Arena = models.Arena(lines[0])
+	for _, line := range lines[1:] {
+		battle.Items = append(battle.Items, &models.BattledomeItem{Name: models.ItemName(line), Quantity: 1})
+	}
+	dto.InheritMetadata()
+	return dto, nil
+}
+
+func writeDropFile(t *testing.T, folder string, name string, content string) {
+	if err := os.WriteFile(filepath.Join(folder, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %s", name, err)
+	}
+}
+
+func TestDropDataStoreOnlyParsesChangedFiles(t *testing.T) {
+	folder := t.TempDir()
+	indexFilePath := filepath.Join(t.TempDir(), "index.gob")
+	writeDropFile(t, folder, "2025_01_01.txt", "Central Arena\nOrn Codestone\nRed Apple")
+	writeDropFile(t, folder, "2025_01_02.txt", "Ugga Dome\nBri Codestone")
+
+	parser := &countingDropDataParser{}
+	if err := NewDropDataStore(parser, folder, indexFilePath).Refresh(); err != nil {
+		t.Fatalf("Failed to build index: %s", err)
+	}
+	if len(parser.parsedFiles) != 2 {
+		t.Fatalf("Expected both files to be parsed, but parsed %v", parser.parsedFiles)
+	}
+
+	writeDropFile(t, folder, "2025_01_02.txt", "Ugga Dome\nMau Codestone")
+	parser = &countingDropDataParser{}
+	store := NewDropDataStore(parser, folder, indexFilePath)
+	battles, err := store.Battles(DropDataQuery{Arena: "Central Arena"})
+	if err != nil {
+		t.Fatalf("Failed to query index: %s", err)
+	}
+	if len(parser.parsedFiles) != 1 || parser.parsedFiles[0] != "2025_01_02.txt" {
+		t.Fatalf("Expected only the changed file to be parsed, but parsed %v", parser.parsedFiles)
+	}
+	if len(battles) != 1 || len(battles[0].Items) != 2 {
+		t.Fatalf("Expected one Central Arena battle with two items, but got %v", battles)
+	}
+
+	battles, err = store.Battles(DropDataQuery{From: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Failed to query index: %s", err)
+	}
+	if len(battles) != 1 || battles[0].Items[0].Name != "Mau Codestone" {
+		t.Fatalf("Expected the updated 2025_01_02.txt to be the only match, but got %v", battles)
+	}
+}
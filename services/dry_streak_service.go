@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+type RecordedBattles interface {
+	Battles(query DropDataQuery) ([]*models.Battle, error)
+}
+
+// How long it has been since an item dropped, and how unusual that is
+type DryStreakService struct {
+	RecordedBattles
+	BattledomeItemWeights
+	ItemGroups *helpers.ItemGroups
+}
+
+func NewDryStreakService(recordedBattles RecordedBattles, battledomeItemWeights BattledomeItemWeights, itemGroups *helpers.ItemGroups) *DryStreakService {
+	return &DryStreakService{
+		RecordedBattles:       recordedBattles,
+		BattledomeItemWeights: battledomeItemWeights,
+		ItemGroups:            itemGroups,
+	}
+}
+
+// Matches any item in the group if item names an item group, and otherwise just the item
+func (s *DryStreakService) itemMatcher(item string) (string, func(itemName models.ItemName) bool) {
+	if group, exists := s.ItemGroups.Group(item); exists {
+		return group.Name, func(itemName models.ItemName) bool {
+			return group.Contains(string(itemName))
+		}
+	}
+	canonicalName := helpers.ItemAliasesInstance().Canonical(item)
+	return canonicalName, func(itemName models.ItemName) bool {
+		return strings.EqualFold(string(itemName), canonicalName)
+	}
+}
+
+// The chance of any one drop in the fight being the item, according to the datamined weights
+func (s *DryStreakService) predictedRate(metadata models.BattledomeItemMetadata, isItem func(itemName models.ItemName) bool) (float64, error) {
+	weights, err := s.BattledomeItemWeights.ItemWeights(metadata)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "failed to get item weights for %s", metadata)
+	}
+	total := helpers.Sum(helpers.Map(weights, func(weight models.BattledomeItemWeight) float64 {
+		return weight.Weight
+	}))
+	if total <= 0 {
+		return 0, nil
+	}
+	itemTotal := helpers.Sum(helpers.Map(helpers.Filter(weights, func(weight models.BattledomeItemWeight) bool {
+		return isItem(models.ItemName(weight.Name))
+	}), func(weight models.BattledomeItemWeight) float64 {
+		return weight.Weight
+	}))
+	return itemTotal / total, nil
+}
+
+// For every challenger in the arena if the metadata has no challenger
+func (s *DryStreakService) DryStreak(item string, metadata models.BattledomeItemMetadata) (*models.DryStreakReport, error) {
+	itemName, isItem := s.itemMatcher(item)
+	battles, err := s.RecordedBattles.Battles(DropDataQuery{
+		Arena:      metadata.Arena,
+		Challenger: metadata.Challenger,
+		Difficulty: metadata.Difficulty,
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get battles for %s", metadata.String())
+	}
+	if len(battles) == 0 {
+		return nil, fmt.Errorf("there are no recorded drops for %s", metadata.String())
+	}
+
+	report := models.NewDryStreakReport(itemName, metadata, battles, isItem)
+	predictedRate, err := s.predictedRate(metadata, isItem)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get the predicted drop rate of %q", itemName)
+	}
+	report.Predicted = models.NewDryStreakOdds(predictedRate, report.CurrentStreak, constants.DryStreakPercentiles)
+	report.Actual = models.NewDryStreakOdds(float64(report.Occurrences)/float64(report.Drops), report.CurrentStreak, constants.DryStreakPercentiles)
+	return report, nil
+}
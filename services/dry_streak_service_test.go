@@ -0,0 +1,61 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type fixedBattles []*models.Battle
+
+func (b fixedBattles) Battles(query DropDataQuery) ([]*models.Battle, error) {
+	return b, nil
+}
+
+type fixedWeights []models.BattledomeItemWeight
+
+func (w fixedWeights) ItemWeights(metadata models.BattledomeItemMetadata) ([]models.BattledomeItemWeight, error) {
+	return w, nil
+}
+
+func (w fixedWeights) PoolMetadata(metadata models.BattledomeItemMetadata) (models.BattledomeItemMetadata, error) {
+	return models.BattledomeItemMetadata{Arena: metadata.Arena}, nil
+}
+
+func battle(quantities map[string]int32) *models.Battle {
+	return fightBattle(models.BattledomeItemMetadata{}, quantities)
+}
+
+func TestDryStreak(t *testing.T) {
+	target := NewDryStreakService(fixedBattles{
+		battle(map[string]int32{"Robot Muffin": 15}),
+		battle(map[string]int32{"Robot Muffin": 14, "Har Codestone": 1}),
+		battle(map[string]int32{"Robot Muffin": 15}),
+		battle(map[string]int32{"Robot Muffin": 15}),
+		battle(map[string]int32{"Robot Muffin": 13, "Bri Codestone": 2}),
+		battle(map[string]int32{"Robot Muffin": 15}),
+	}, fixedWeights{
+		{Arena: "Central Arena", Name: "Robot Muffin", Weight: 9},
+		{Arena: "Central Arena", Name: "Har Codestone", Weight: 0.5},
+		{Arena: "Central Arena", Name: "Bri Codestone", Weight: 0.5},
+	}, &helpers.ItemGroups{Groups: []*helpers.ItemGroup{helpers.NewItemGroup("Brown Codestones", "Har Codestone", "Bri Codestone")}})
+
+	report, err := target.DryStreak("brown codestones", models.BattledomeItemMetadata{Arena: "Central Arena"})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if report.Item != "Brown Codestones" || report.Drops != 90 || report.Occurrences != 3 {
+		t.Fatalf("Expected 3 Brown Codestones out of 90 drops, but got %d %s out of %d", report.Occurrences, report.Item, report.Drops)
+	}
+	if report.CurrentStreak != 15 || report.LongestStreak != 30 {
+		t.Fatalf("Expected a current streak of 15 and a longest streak of 30, but got %d and %d", report.CurrentStreak, report.LongestStreak)
+	}
+	if math.Abs(report.Predicted.Rate-0.1) > 1e-9 || math.Abs(report.Actual.Rate-1.0/30) > 1e-9 {
+		t.Fatalf("Expected drop rates of 10%% predicted and 3.33%% actual, but got %f and %f", report.Predicted.Rate, report.Actual.Rate)
+	}
+	if math.Abs(report.Predicted.ChanceOfCurrentStreak-math.Pow(0.9, 15)) > 1e-9 {
+		t.Fatalf("Expected a %f chance of going 15 drops without one, but got %f", math.Pow(0.9, 15), report.Predicted.ChanceOfCurrentStreak)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+// Two drop files that look like the same day saved twice
+type DuplicateDropFiles struct {
+	First  string
+	Second string
+	// False if only the metadata and items match, e.g. the comments or item order differ
+	IsExact bool
+}
+
+func (d DuplicateDropFiles) String() string {
+	return fmt.Sprintf("%s and %s %s", d.First, d.Second, helpers.When(d.IsExact, "are identical", "have the same metadata and drops"))
+}
+
+type DuplicateDropFilesError struct {
+	Duplicates []DuplicateDropFiles
+}
+
+func (e *DuplicateDropFilesError) Error() string {
+	return fmt.Sprintf("found %d pair(s) of duplicate drop files: %s", len(e.Duplicates), strings.Join(helpers.Map(e.Duplicates, func(duplicate DuplicateDropFiles) string {
+		return duplicate.String()
+	}), "; "))
+}
+
+type dropFileFingerprint struct {
+	file        string
+	contentHash string
+	// The metadata and item multiset of every battle, ignoring item order, comments and whitespace
+	signature string
+}
+
+func newDropFileFingerprint(file string, content []byte, dto *models.BattledomeItemsDto) dropFileFingerprint {
+	return dropFileFingerprint{
+		file:        file,
+		contentHash: dropFileContentHash(content),
+		signature:   dropFileSignature(dto),
+	}
+}
+
+// Ignores line endings and leading or trailing whitespace
+func dropFileContentHash(content []byte) string {
+	normalisedContent := strings.TrimSpace(strings.ReplaceAll(string(content), "\r\n", "\n"))
+	contentHash := sha256.Sum256([]byte(normalisedContent))
+	return hex.EncodeToString(contentHash[:])
+}
+
+// The metadata and item multiset of a battle
+func battleSignature(battle *models.Battle) string {
+	quantities := map[models.ItemName]int32{}
+	for _, item := range battle.Items {
+		quantities[item.Name] += item.Quantity
+	}
+	items := []string{}
+	for name, quantity := range quantities {
+		items = append(items, fmt.Sprintf("%s=%d", name, quantity))
+	}
+	slices.Sort(items)
+	return fmt.Sprintf("%s:%s", battle.Metadata.String(), strings.Join(items, ","))
+}
+
+func dropFileSignature(dto *models.BattledomeItemsDto) string {
+	return strings.Join(helpers.Map(dto.Battles, battleSignature), ";")
+}
+
+// Every pair of files with the same fingerprint, ordered by file name
+func findDuplicateDropFiles(fingerprints []dropFileFingerprint) []DuplicateDropFiles {
+	fingerprints = slices.Clone(fingerprints)
+	slices.SortFunc(fingerprints, func(first dropFileFingerprint, second dropFileFingerprint) int {
+		return strings.Compare(first.file, second.file)
+	})
+
+	duplicates := []DuplicateDropFiles{}
+	for i, first := range fingerprints {
+		for _, second := range fingerprints[i+1:] {
+			isExact := first.contentHash == second.contentHash
+			if !isExact && (first.signature == "" || first.signature != second.signature) {
+				continue
+			}
+			duplicates = append(duplicates, DuplicateDropFiles{
+				First:   first.file,
+				Second:  second.file,
+				IsExact: isExact,
+			})
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+func dropFileDto(arena string, items map[string]int32) *models.BattledomeItemsDto {
+	metadata := models.BattledomeItemMetadata{Arena: models.Arena(arena), Challenger: "Flaming Meerca", Difficulty: "Mighty"}
+	return &models.BattledomeItemsDto{Battles: []*models.Battle{fightBattle(metadata, items)}}
+}
+
+func TestFindDuplicateDropFiles(t *testing.T) {
+	items := map[string]int32{"Orn Codestone": 2, "Chocolate Creme Pie": 1}
+	fingerprints := []dropFileFingerprint{
+		newDropFileFingerprint("2025_01_03.txt", []byte("# reordered\nChocolate Creme Pie|1\nOrn Codestone|2"), dropFileDto("Central Arena", items)),
+		newDropFileFingerprint("2025_01_01.txt", []byte("Orn Codestone|2\r\nChocolate Creme Pie|1\r\n"), dropFileDto("Central Arena", items)),
+		newDropFileFingerprint("2025_01_02.txt", []byte("Orn Codestone|2\nChocolate Creme Pie|1"), dropFileDto("Central Arena", items)),
+		newDropFileFingerprint("2025_01_04.txt", []byte("$ARENA:Ugga Dome\nOrn Codestone|2\nChocolate Creme Pie|1\n"), dropFileDto("Ugga Dome", items)),
+	}
+
+	duplicates := findDuplicateDropFiles(fingerprints)
+	expected := []DuplicateDropFiles{
+		{First: "2025_01_01.txt", Second: "2025_01_02.txt", IsExact: true},
+		{First: "2025_01_01.txt", Second: "2025_01_03.txt", IsExact: false},
+		{First: "2025_01_02.txt", Second: "2025_01_03.txt", IsExact: false},
+	}
+	if len(duplicates) != len(expected) {
+		t.Fatalf("Expected %d duplicate pairs, but found %d: %v", len(expected), len(duplicates), duplicates)
+	}
+	for i := range expected {
+		if duplicates[i] != expected[i] {
+			t.Fatalf("Expected pair %d to be %v, but it was %v", i, expected[i], duplicates[i])
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+type GeneratedBattledomeItems interface {
+	Items(metadata models.BattledomeItemMetadata, count int, seed uint64) (models.NormalisedBattledomeItems, error)
+}
+
+type SavedGeneratedBattledomeItems interface {
+	Parse(filePath string) (models.NormalisedBattledomeItems, error)
+	Save(items models.NormalisedBattledomeItems, provenance models.GeneratedDropsProvenance, filePath string) error
+	Provenance(filePath string) (*models.GeneratedDropsProvenance, error)
+}
+
+type GeneratedDropsCacheEntry struct {
+	FileName string
+	Size     int64
+	// Nil if the file was generated before provenance was recorded
+	Provenance *models.GeneratedDropsProvenance
+	// True if the file wouldn't be used because its inputs have since changed
+	IsStale bool
+}
+
+// Generated drops are expensive to make, so they're saved in files named after the inputs they were generated from
+type GeneratedDropsCacheService struct {
+	GeneratedBattledomeItems
+	SavedGeneratedBattledomeItems
+	BattledomeItemWeights
+	folderPath string
+	seed       uint64
+	count      int
+}
+
+func NewGeneratedDropsCacheService(
+	generatedBattledomeItems GeneratedBattledomeItems,
+	generatedBattledomeItemParser SavedGeneratedBattledomeItems,
+	battledomeItemWeights BattledomeItemWeights,
+	folderPath string,
+	seed uint64,
+	count int,
+) *GeneratedDropsCacheService {
+	return &GeneratedDropsCacheService{
+		GeneratedBattledomeItems:      generatedBattledomeItems,
+		SavedGeneratedBattledomeItems: generatedBattledomeItemParser,
+		BattledomeItemWeights:         battledomeItemWeights,
+		folderPath:                    folderPath,
+		seed:                          seed,
+		count:                         count,
+	}
+}
+
+func (s *GeneratedDropsCacheService) weightsHash(pool models.BattledomeItemMetadata) (string, error) {
+	weights, err := s.BattledomeItemWeights.ItemWeights(pool)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "failed to get item weights for %s", pool)
+	}
+	lines := helpers.OrderBy(helpers.Map(weights, func(weight models.BattledomeItemWeight) string {
+		return fmt.Sprintf("%s|%g", weight.Name, weight.Weight)
+	}), func(line string) string {
+		return line
+	})
+	hash := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// The provenance that generated drops for the pool should have with the current weights and settings
+func (s *GeneratedDropsCacheService) currentProvenance(pool models.BattledomeItemMetadata) (models.GeneratedDropsProvenance, error) {
+	weightsHash, err := s.weightsHash(pool)
+	if err != nil {
+		return models.GeneratedDropsProvenance{}, stacktrace.Propagate(err, "failed to hash item weights for %s", pool)
+	}
+	return models.GeneratedDropsProvenance{
+		Pool:             pool,
+		WeightsHash:      weightsHash,
+		GeneratorVersion: constants.GeneratedDropsGeneratorVersion,
+		Seed:             s.seed,
+		Count:            s.count,
+	}, nil
+}
+
+func fileName(provenance models.GeneratedDropsProvenance) string {
+	return constants.GeneratedDropsFileName(strings.ReplaceAll(provenance.PoolName(), " > ", " "), provenance.Key())
+}
+
+// Generated drops for a fight, generating and saving them if there aren't any for the current inputs yet. Fights share
+// the generated drops of their arena unless the challenger has a prize pool of their own. Files generated from older
+// inputs for the same pool are deleted.
+func (s *GeneratedDropsCacheService) Drops(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, error) {
+	pool, err := s.BattledomeItemWeights.PoolMetadata(metadata)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get prize pool for %s", metadata)
+	}
+	provenance, err := s.currentProvenance(pool)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to work out what the generated drops for %s should be generated from", pool)
+	}
+	filePath := filepath.Join(s.folderPath, fileName(provenance))
+
+	if helpers.IsFileExists(filePath) {
+		savedProvenance, err := s.SavedGeneratedBattledomeItems.Provenance(filePath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to read provenance of %q", filePath)
+		}
+		if savedProvenance != nil && savedProvenance.Key() == provenance.Key() {
+			parsedDrops, err := s.SavedGeneratedBattledomeItems.Parse(filePath)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "failed to parse %q as battledome drops", filePath)
+			}
+			return parsedDrops, nil
+		}
+	}
+
+	slog.Info(fmt.Sprintf("Generating drops for %s", provenance))
+	items, err := s.GeneratedBattledomeItems.Items(pool, provenance.Count, provenance.Seed)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to generate items for %s", pool)
+	}
+	provenance.GeneratedAt = time.Now()
+	if err := s.SavedGeneratedBattledomeItems.Save(items, provenance, filePath); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to save generated drops to %q", filePath)
+	}
+
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to list generated drops")
+	}
+	for _, entry := range entries {
+		if entry.IsStale && entry.Provenance != nil && entry.Provenance.Pool == pool {
+			if err := os.Remove(filepath.Join(s.folderPath, entry.FileName)); err != nil {
+				return nil, stacktrace.Propagate(err, "failed to delete stale generated drops %q", entry.FileName)
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// Every generated drops file, ordered by name
+func (s *GeneratedDropsCacheService) Entries() ([]*GeneratedDropsCacheEntry, error) {
+	files, err := helpers.FilesInFolder(s.folderPath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get files in %q", s.folderPath)
+	}
+
+	entries := []*GeneratedDropsCacheEntry{}
+	for _, file := range files {
+		if !strings.HasPrefix(file, constants.GeneratedDropsFileNamePrefix) {
+			continue
+		}
+		filePath := filepath.Join(s.folderPath, file)
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to stat %q", file)
+		}
+		provenance, err := s.SavedGeneratedBattledomeItems.Provenance(filePath)
+		if err != nil {
+			// Treat it as stale so that it can still be cleared
+			slog.Warn(fmt.Sprintf("Failed to read provenance of %q: %s", file, err))
+			provenance = nil
+		}
+
+		isStale := true
+		if provenance != nil {
+			currentPool, err := s.BattledomeItemWeights.PoolMetadata(provenance.Pool)
+			isStale = err != nil || currentPool != provenance.Pool
+			if !isStale {
+				currentProvenance, err := s.currentProvenance(provenance.Pool)
+				isStale = err != nil || currentProvenance.Key() != provenance.Key() || file != fileName(*provenance)
+			}
+		}
+		entries = append(entries, &GeneratedDropsCacheEntry{
+			FileName:   file,
+			Size:       info.Size(),
+			Provenance: provenance,
+			IsStale:    isStale,
+		})
+	}
+	return helpers.OrderBy(entries, func(entry *GeneratedDropsCacheEntry) string {
+		return entry.FileName
+	}), nil
+}
+
+// Deletes generated drops files, or only the stale ones, returning what was deleted
+func (s *GeneratedDropsCacheService) Clear(isOnlyStale bool) ([]*GeneratedDropsCacheEntry, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to list generated drops")
+	}
+
+	cleared := []*GeneratedDropsCacheEntry{}
+	for _, entry := range entries {
+		if isOnlyStale && !entry.IsStale {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.folderPath, entry.FileName)); err != nil {
+			return nil, stacktrace.Propagate(err, "failed to delete %q", entry.FileName)
+		}
+		cleared = append(cleared, entry)
+	}
+	return cleared, nil
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/parsers"
+)
+
+type fakeItemWeights struct {
+	weights []models.BattledomeItemWeight
+}
+
+func (w *fakeItemWeights) Parse(filePath string) ([]models.BattledomeItemWeight, error) {
+	return w.weights, nil
+}
+
+type countingGenerator struct {
+	*BattledomeItemGenerationService
+	calls int
+}
+
+func (g *countingGenerator) Items(metadata models.BattledomeItemMetadata, count int, seed uint64) (models.NormalisedBattledomeItems, error) {
+	g.calls++
+	return g.BattledomeItemGenerationService.Items(metadata, count, seed)
+}
+
+func TestGeneratedDropsAreDeterministic(t *testing.T) {
+	target := NewBattledomeItemGenerationService(NewBattledomeItemWeightService(&fakeItemWeights{weights: []models.BattledomeItemWeight{
+		{Arena: "Central Arena", Name: "Red Apple", Weight: 3},
+		{Arena: "Central Arena", Name: "Orn Codestone", Weight: 1},
+	}}))
+	arena := models.BattledomeItemMetadata{Arena: "Central Arena"}
+	first, err := target.Items(arena, 1_000, 7)
+	if err != nil {
+		t.Fatalf("Failed to generate items: %s", err)
+	}
+	second, err := target.Items(arena, 1_000, 7)
+	if err != nil {
+		t.Fatalf("Failed to generate items: %s", err)
+	}
+	if first["Red Apple"].Quantity+first["Orn Codestone"].Quantity != 1_000 {
+		t.Fatalf("Expected 1,000 items, but got %d and %d", first["Red Apple"].Quantity, first["Orn Codestone"].Quantity)
+	}
+	if first["Red Apple"].Quantity != second["Red Apple"].Quantity {
+		t.Fatalf("Expected the same seed to give the same items, but got %d and %d Red Apples", first["Red Apple"].Quantity, second["Red Apple"].Quantity)
+	}
+}
+
+func TestGeneratedDropsCacheIsInvalidatedByWeights(t *testing.T) {
+	folder := t.TempDir()
+	weights := &fakeItemWeights{weights: []models.BattledomeItemWeight{
+		{Arena: "Central Arena", Name: "Red Apple", Weight: 1},
+	}}
+	weightService := NewBattledomeItemWeightService(weights)
+	generator := &countingGenerator{BattledomeItemGenerationService: NewBattledomeItemGenerationService(weightService)}
+	newTarget := func() *GeneratedDropsCacheService {
+		return NewGeneratedDropsCacheService(generator, parsers.NewGeneratedBattledomeItemParser(), weightService, folder, 1, 100)
+	}
+	arena := models.BattledomeItemMetadata{Arena: "Central Arena"}
+
+	if _, err := newTarget().Drops(arena); err != nil {
+		t.Fatalf("Failed to get generated drops: %s", err)
+	}
+	drops, err := newTarget().Drops(arena)
+	if err != nil {
+		t.Fatalf("Failed to get generated drops: %s", err)
+	}
+	if generator.calls != 1 || drops["Red Apple"].Quantity != 100 {
+		t.Fatalf("Expected the saved drops to be reused, but the generator was called %d time(s)", generator.calls)
+	}
+
+	weights.weights = append(weights.weights, models.BattledomeItemWeight{Arena: "Central Arena", Name: "Orn Codestone", Weight: 1})
+	if _, err := newTarget().Drops(arena); err != nil {
+		t.Fatalf("Failed to get generated drops: %s", err)
+	}
+	if generator.calls != 2 {
+		t.Fatalf("Expected changed weights to regenerate drops, but the generator was called %d time(s)", generator.calls)
+	}
+	entries, err := newTarget().Entries()
+	if err != nil {
+		t.Fatalf("Failed to list generated drops: %s", err)
+	}
+	if len(entries) != 1 || entries[0].IsStale {
+		t.Fatalf("Expected only the current generated drops to be kept, but found %d file(s)", len(entries))
+	}
+}
+
+func TestGeneratedDropsIncludeChallengerPool(t *testing.T) {
+	weightService := NewBattledomeItemWeightService(&fakeItemWeights{weights: []models.BattledomeItemWeight{
+		{Arena: "Central Arena", Name: "Red Apple", Weight: 0.5},
+		{Arena: "Central Arena", Name: "Orn Codestone", Weight: 0.5},
+		{Arena: "Central Arena", Challenger: "Flaming Meerca", Name: "Meerca Plushie", Weight: 0.2},
+	}})
+	fight := models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Flaming Meerca", Difficulty: "Mighty"}
+
+	pool, err := weightService.PoolMetadata(fight)
+	if err != nil {
+		t.Fatalf("Failed to get prize pool: %s", err)
+	}
+	if pool != (models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Flaming Meerca"}) {
+		t.Fatalf("Expected the challenger's pool for any difficulty to be used, but got %v", pool)
+	}
+	weights, err := weightService.ItemWeights(fight)
+	if err != nil {
+		t.Fatalf("Failed to get item weights: %s", err)
+	}
+	for _, weight := range weights {
+		expected := map[string]float64{"Red Apple": 0.4, "Orn Codestone": 0.4, "Meerca Plushie": 0.2}[weight.Name]
+		if math.Abs(weight.Weight-expected) > 1e-9 {
+			t.Fatalf("Expected %s to have a weight of %f, but it was %f", weight.Name, expected, weight.Weight)
+		}
+	}
+
+	target := NewGeneratedDropsCacheService(NewBattledomeItemGenerationService(weightService), parsers.NewGeneratedBattledomeItemParser(), weightService, t.TempDir(), 1, 1_000)
+	challengerDrops, err := target.Drops(fight)
+	if err != nil {
+		t.Fatalf("Failed to get generated drops: %s", err)
+	}
+	arenaDrops, err := target.Drops(models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Koi Warrior", Difficulty: "Mighty"})
+	if err != nil {
+		t.Fatalf("Failed to get generated drops: %s", err)
+	}
+	if _, exists := challengerDrops["Meerca Plushie"]; !exists {
+		t.Fatalf("Expected the challenger's prizes to be generated for a fight against them")
+	}
+	if _, exists := arenaDrops["Meerca Plushie"]; exists {
+		t.Fatalf("Expected the challenger's prizes not to be generated for a fight against someone else")
+	}
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	ObservedGoalRates  = "observed"
+	DataminedGoalRates = "datamined"
+)
+
+type BattledomeItemPools interface {
+	Pools() ([]models.BattledomeItemMetadata, error)
+	ItemWeights(metadata models.BattledomeItemMetadata) ([]models.BattledomeItemWeight, error)
+}
+
+type RecordedFights interface {
+	DropsGroupedByMetadata() (map[models.BattledomeItemMetadata]models.NormalisedBattledomeItems, error)
+}
+
+// Simulates days of drops until a wishlist is complete, to find the fight that completes it fastest
+type GoalService struct {
+	BattledomeItemPools
+	RecordedFights
+	ItemGroups *helpers.ItemGroups
+	Seed       uint64
+	// Recorded fights with fewer drops are left out of observed plans
+	MinimumSamples int
+	simulations    int
+	maxDays        int
+}
+
+func NewGoalService(battledomeItemPools BattledomeItemPools, recordedFights RecordedFights, itemGroups *helpers.ItemGroups, simulations int, maxDays int, minimumSamples int, seed uint64) *GoalService {
+	return &GoalService{
+		BattledomeItemPools: battledomeItemPools,
+		RecordedFights:      recordedFights,
+		ItemGroups:          itemGroups,
+		Seed:                seed,
+		simulations:         simulations,
+		maxDays:             maxDays,
+		MinimumSamples:      minimumSamples,
+	}
+}
+
+// The chance of each item being any one drop in a fight
+type goalDropRates struct {
+	metadata models.BattledomeItemMetadata
+	samples  int
+	rates    map[models.ItemName]float64
+}
+
+func (s *GoalService) dataminedRates() ([]goalDropRates, error) {
+	pools, err := s.BattledomeItemPools.Pools()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get prize pools")
+	}
+	fights := []goalDropRates{}
+	for _, pool := range pools {
+		weights, err := s.BattledomeItemPools.ItemWeights(pool)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get item weights for %s", pool.String())
+		}
+		total := helpers.Sum(helpers.Map(weights, func(weight models.BattledomeItemWeight) float64 {
+			return weight.Weight
+		}))
+		rates := map[models.ItemName]float64{}
+		for _, weight := range weights {
+			rates[models.ItemName(weight.Name)] += weight.Weight / total
+		}
+		fights = append(fights, goalDropRates{metadata: pool, rates: rates})
+	}
+	return fights, nil
+}
+
+// Each recorded fight, and each arena's fights together, with at least minimumSamples drops
+func (s *GoalService) observedRates() ([]goalDropRates, error) {
+	dropsByFight, err := s.RecordedFights.DropsGroupedByMetadata()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get recorded drops")
+	}
+	quantitiesByFight := map[models.BattledomeItemMetadata]map[models.ItemName]int{}
+	addQuantities := func(metadata models.BattledomeItemMetadata, items models.NormalisedBattledomeItems) {
+		if _, exists := quantitiesByFight[metadata]; !exists {
+			quantitiesByFight[metadata] = map[models.ItemName]int{}
+		}
+		for name, item := range items {
+			quantitiesByFight[metadata][name] += int(item.Quantity)
+		}
+	}
+	for metadata, items := range dropsByFight {
+		addQuantities(metadata, items)
+		addQuantities(models.BattledomeItemMetadata{Arena: metadata.Arena}, items)
+	}
+
+	fights := []goalDropRates{}
+	for metadata, quantities := range quantitiesByFight {
+		total := helpers.Sum(helpers.Values(quantities))
+		if total < s.MinimumSamples {
+			continue
+		}
+		rates := map[models.ItemName]float64{}
+		for name, quantity := range quantities {
+			rates[name] = float64(quantity) / float64(total)
+		}
+		fights = append(fights, goalDropRates{metadata: metadata, samples: total, rates: rates})
+	}
+	return fights, nil
+}
+
+type goalRequirement struct {
+	name     string
+	quantity int
+	// Any item of a group will do
+	isAny   bool
+	matches func(itemName models.ItemName) bool
+}
+
+func exactItem(name string) func(itemName models.ItemName) bool {
+	return func(itemName models.ItemName) bool {
+		return strings.EqualFold(string(itemName), name)
+	}
+}
+
+// Every item of a group is a requirement of its own, including pattern matches among the items that can drop
+func (s *GoalService) requirements(wishlist models.Wishlist, rates map[models.ItemName]float64) []goalRequirement {
+	requirements := []goalRequirement{}
+	for _, entry := range wishlist {
+		group, isGroup := s.ItemGroups.Group(entry.Name)
+		if !isGroup {
+			name := helpers.ItemAliasesInstance().Canonical(entry.Name)
+			requirements = append(requirements, goalRequirement{name: name, quantity: entry.Quantity, matches: exactItem(name)})
+			continue
+		}
+		if entry.IsAny {
+			requirements = append(requirements, goalRequirement{name: "any " + group.Name, quantity: entry.Quantity, isAny: true, matches: func(itemName models.ItemName) bool {
+				return group.Contains(string(itemName))
+			}})
+			continue
+		}
+
+		names := group.ItemNames()
+		for itemName := range rates {
+			if group.Contains(string(itemName)) && !slices.ContainsFunc(names, func(name string) bool {
+				return strings.EqualFold(name, string(itemName))
+			}) {
+				names = append(names, string(itemName))
+			}
+		}
+		if len(names) == 0 {
+			// Nothing in the group can drop, so the group itself is missing
+			names = []string{group.Name}
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			requirements = append(requirements, goalRequirement{name: name, quantity: entry.Quantity, matches: exactItem(name)})
+		}
+	}
+	return requirements
+}
+
+// Only the drops that count towards a requirement are simulated; the drops in between are skipped geometrically
+type goalSimulator struct {
+	requirements []goalRequirement
+	// Ascending running totals of the rates of the items that count towards a requirement
+	cumulativeRates []float64
+	// The requirements each of those items can count towards, those that need that item before those that take any item
+	// of a group
+	itemRequirements [][]int
+	maxDrops         int
+}
+
+func newGoalSimulator(requirements []goalRequirement, rates map[models.ItemName]float64, maxDays int) (*goalSimulator, []string) {
+	simulator := &goalSimulator{
+		requirements: requirements,
+		maxDrops:     maxDays * constants.BattledomeDropsPerDay,
+	}
+	itemNames := helpers.OrderBy(helpers.Keys(rates), func(itemName models.ItemName) string {
+		return string(itemName)
+	})
+	isMet := make([]bool, len(requirements))
+	total := 0.0
+	for _, itemName := range itemNames {
+		if rates[itemName] <= 0 {
+			continue
+		}
+		indices := []int{}
+		for i, requirement := range requirements {
+			if requirement.matches(itemName) {
+				indices = append(indices, i)
+				isMet[i] = true
+			}
+		}
+		if len(indices) == 0 {
+			continue
+		}
+		slices.SortStableFunc(indices, func(first int, second int) int {
+			return helpers.When(requirements[first].isAny == requirements[second].isAny, 0, helpers.When(requirements[first].isAny, 1, -1))
+		})
+		total += rates[itemName]
+		simulator.cumulativeRates = append(simulator.cumulativeRates, total)
+		simulator.itemRequirements = append(simulator.itemRequirements, indices)
+	}
+
+	missing := []string{}
+	for i, requirement := range requirements {
+		if !isMet[i] {
+			missing = append(missing, requirement.name)
+		}
+	}
+	return simulator, missing
+}
+
+// Days until every requirement is met, or false if it takes longer than maxDays. Each drop counts towards the first
+// requirement it can that still needs it, so a drop wanted by itself isn't used up on a requirement any item of its
+// group would do for.
+func (s *goalSimulator) simulate(random *rand.Rand) (int, bool) {
+	counts := make([]int, len(s.requirements))
+	remaining := len(s.requirements)
+	relevantRate := s.cumulativeRates[len(s.cumulativeRates)-1]
+	drops := 0
+	for remaining > 0 {
+		if relevantRate < 1 {
+			drops += int(math.Floor(math.Log(1-random.Float64()) / math.Log1p(-relevantRate)))
+		}
+		drops++
+		if drops > s.maxDrops {
+			return 0, false
+		}
+
+		item := min(sort.SearchFloat64s(s.cumulativeRates, random.Float64()*relevantRate), len(s.cumulativeRates)-1)
+		for _, i := range s.itemRequirements[item] {
+			if counts[i] < s.requirements[i].quantity {
+				counts[i]++
+				if counts[i] == s.requirements[i].quantity {
+					remaining--
+				}
+				break
+			}
+		}
+	}
+	return (drops + constants.BattledomeDropsPerDay - 1) / constants.BattledomeDropsPerDay, true
+}
+
+func (s *GoalService) plan(wishlist models.Wishlist, fight goalDropRates) models.GoalPlan {
+	plan := models.GoalPlan{
+		Metadata: fight.metadata,
+		Samples:  fight.samples,
+	}
+	simulator, missing := newGoalSimulator(s.requirements(wishlist, fight.rates), fight.rates, s.maxDays)
+	if len(missing) > 0 {
+		plan.MissingItems = missing
+		return plan
+	}
+
+	// Each fight has its own random source, so its plan doesn't depend on which other fights are simulated
+	hash := fnv.New64a()
+	hash.Write([]byte(fight.metadata.String()))
+	random := rand.New(rand.NewPCG(s.Seed, hash.Sum64()))
+
+	days := []int{}
+	unfinished := 0
+	for range s.simulations {
+		if simulatedDays, isFinished := simulator.simulate(random); isFinished {
+			days = append(days, simulatedDays)
+		} else {
+			unfinished++
+		}
+	}
+	slices.Sort(days)
+	plan.ChanceOfFinishing = float64(len(days)) / float64(s.simulations)
+	if len(days) > 0 {
+		plan.ExpectedDays = float64(helpers.Sum(days)+unfinished*s.maxDays) / float64(s.simulations)
+	}
+	for _, percentile := range constants.GoalPercentiles {
+		// Unfinished simulations count as the slowest
+		percentileDays := models.PercentileDays{Percentile: percentile}
+		if rank := max(int(math.Ceil(percentile*float64(s.simulations)))-1, 0); rank < len(days) {
+			percentileDays.Days = days[rank]
+		}
+		plan.Percentiles = append(plan.Percentiles, percentileDays)
+	}
+	return plan
+}
+
+// rates is ObservedGoalRates or DataminedGoalRates
+func (s *GoalService) Plan(wishlist models.Wishlist, rates string) (*models.GoalReport, error) {
+	if len(wishlist) == 0 {
+		return nil, fmt.Errorf("the wishlist is empty")
+	}
+
+	var fights []goalDropRates
+	var err error
+	switch rates {
+	case ObservedGoalRates:
+		fights, err = s.observedRates()
+	case DataminedGoalRates:
+		fights, err = s.dataminedRates()
+	default:
+		return nil, fmt.Errorf("unknown drop rates %q (expected %s or %s)", rates, ObservedGoalRates, DataminedGoalRates)
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get %s drop rates", rates)
+	}
+
+	plans := make([]models.GoalPlan, len(fights))
+	wg := &sync.WaitGroup{}
+	for i, fight := range fights {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			plans[i] = s.plan(wishlist, fight)
+		}()
+	}
+	wg.Wait()
+
+	// Fights that always finish first, fastest first, then the likeliest to finish
+	slices.SortStableFunc(plans, func(first models.GoalPlan, second models.GoalPlan) int {
+		if first.ChanceOfFinishing != second.ChanceOfFinishing {
+			return helpers.When(first.ChanceOfFinishing > second.ChanceOfFinishing, -1, 1)
+		}
+		if first.ExpectedDays != second.ExpectedDays {
+			return helpers.When(first.ExpectedDays < second.ExpectedDays, -1, 1)
+		}
+		return strings.Compare(first.Metadata.FightName(), second.Metadata.FightName())
+	})
+	return &models.GoalReport{
+		Wishlist:    wishlist,
+		Rates:       rates,
+		Simulations: s.simulations,
+		MaxDays:     s.maxDays,
+		Percentiles: constants.GoalPercentiles,
+		Plans:       plans,
+	}, nil
+}
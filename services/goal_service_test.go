@@ -0,0 +1,113 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type fixedPools map[models.Arena][]models.BattledomeItemWeight
+
+func (p fixedPools) Pools() ([]models.BattledomeItemMetadata, error) {
+	pools := []models.BattledomeItemMetadata{}
+	for arena := range p {
+		pools = append(pools, models.BattledomeItemMetadata{Arena: arena})
+	}
+	return pools, nil
+}
+
+func (p fixedPools) ItemWeights(metadata models.BattledomeItemMetadata) ([]models.BattledomeItemWeight, error) {
+	return p[metadata.Arena], nil
+}
+
+func newTestGoalService() *GoalService {
+	return NewGoalService(fixedPools{
+		"Central Arena": {
+			{Arena: "Central Arena", Name: "Har Codestone", Weight: 1},
+			{Arena: "Central Arena", Name: "Bri Codestone", Weight: 1},
+		},
+		"Frost Arena": {
+			{Arena: "Frost Arena", Name: "Har Codestone", Weight: 1},
+		},
+	}, nil, &helpers.ItemGroups{Groups: []*helpers.ItemGroup{helpers.NewItemGroup("Brown Codestones", "Har Codestone", "Bri Codestone")}}, 1_000, 100, 0, 1)
+}
+
+func TestGoalNeedsEveryItemOfAGroup(t *testing.T) {
+	report, err := newTestGoalService().Plan(models.Wishlist{{Name: "Brown Codestones", Quantity: 15}}, DataminedGoalRates)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if report.Plans[0].Metadata.Arena != "Central Arena" || !report.Plans[0].IsPossible() {
+		t.Fatalf("Expected Central Arena to be the only fight that finishes, but got %s first", report.Plans[0].Metadata.FightName())
+	}
+	if report.Plans[0].ExpectedDays < 2 || report.Plans[0].ChanceOfFinishing != 1 {
+		t.Fatalf("Expected 30 codestones to take at least 2 days, but got %f days", report.Plans[0].ExpectedDays)
+	}
+	if !slices.Equal(report.Plans[1].MissingItems, []string{"Bri Codestone"}) {
+		t.Fatalf("Expected Frost Arena to be missing Bri Codestone, but got %v", report.Plans[1].MissingItems)
+	}
+}
+
+func TestGoalAcceptsAnyItemOfAGroup(t *testing.T) {
+	report, err := newTestGoalService().Plan(models.Wishlist{{Name: "Brown Codestones", Quantity: 15, IsAny: true}}, DataminedGoalRates)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for _, plan := range report.Plans {
+		// Every drop is a brown codestone, so the first day always finishes it
+		if plan.ExpectedDays != 1 || plan.Percentiles[len(plan.Percentiles)-1].Days != 1 {
+			t.Fatalf("Expected %s to always take 1 day, but got %f", plan.Metadata.FightName(), plan.ExpectedDays)
+		}
+	}
+}
+
+func TestGoalIsReproducible(t *testing.T) {
+	wishlist := models.Wishlist{{Name: "Har Codestone", Quantity: 20}, {Name: "Bri Codestone", Quantity: 3}}
+	first, err := newTestGoalService().Plan(wishlist, DataminedGoalRates)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	second, err := newTestGoalService().Plan(wishlist, DataminedGoalRates)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if first.Plans[0].ExpectedDays != second.Plans[0].ExpectedDays {
+		t.Fatalf("Expected the same plan from the same seed, but got %f and %f days", first.Plans[0].ExpectedDays, second.Plans[0].ExpectedDays)
+	}
+}
+
+func TestGoalCountsEachDropOnce(t *testing.T) {
+	report, err := newTestGoalService().Plan(models.Wishlist{{Name: "Har Codestone", Quantity: 15}, {Name: "Brown Codestones", Quantity: 15, IsAny: true}}, DataminedGoalRates)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for _, plan := range report.Plans {
+		// Frost Arena only drops Har Codestones, so it takes two full days of them
+		if plan.Metadata.Arena == "Frost Arena" && (plan.ExpectedDays != 2 || plan.Percentiles[0].Days != 2) {
+			t.Fatalf("Expected 30 codestones from Frost Arena to always take 2 days, but got %f", plan.ExpectedDays)
+		}
+	}
+}
+
+func TestGoalCountsUnfinishedSimulationsAsTheLongest(t *testing.T) {
+	target := NewGoalService(fixedPools{
+		"Central Arena": {
+			{Arena: "Central Arena", Name: "Har Codestone", Weight: 1},
+			{Arena: "Central Arena", Name: "Robot Muffin", Weight: 14},
+		},
+	}, nil, &helpers.ItemGroups{}, 10_000, 2, 0, 1)
+	report, err := target.Plan(models.Wishlist{{Name: "Har Codestone", Quantity: 1}}, DataminedGoalRates)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	// A day has a 1 - (14/15)^15 ≈ 64.5% chance of a Har Codestone, so 64.5% of simulations take 1 day, 22.9% take 2
+	// and the 12.6% that never finish count as 2, for a mean of 1.355 days
+	plan := report.Plans[0]
+	if math.Abs(plan.ChanceOfFinishing-0.874) > 0.02 || math.Abs(plan.ExpectedDays-1.355) > 0.02 {
+		t.Fatalf("Expected an 87.4%% chance of finishing in 1.355 days, but got %f in %f", plan.ChanceOfFinishing, plan.ExpectedDays)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+	"github.com/palantir/stacktrace"
+)
+
+type ComparedBattledomeItems interface {
+	CompareArena(arena models.Arena) (models.NormalisedBattledomeItems, models.NormalisedBattledomeItems, error)
+	CompareByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, models.NormalisedBattledomeItems, error)
+}
+
+// Simulates the spread of profit over a day and a season, for predicted and actual drops
+type ProfitRiskService struct {
+	ComparedBattledomeItems
+	BootstrapService *BootstrapService
+}
+
+func NewProfitRiskService(dataComparisonService ComparedBattledomeItems, bootstrapService *BootstrapService) *ProfitRiskService {
+	return &ProfitRiskService{
+		ComparedBattledomeItems: dataComparisonService,
+		BootstrapService:        bootstrapService,
+	}
+}
+
+func (s *ProfitRiskService) profitRiskSummary(valuation valuations.ItemValuation, items models.NormalisedBattledomeItems, dailyThreshold float64) models.ProfitRiskSummary {
+	risk := func(days int) models.ProfitRisk {
+		return models.NewProfitRisk(s.BootstrapService.ProfitDistribution(valuation, items, days), constants.RiskPercentiles, dailyThreshold*float64(days), constants.SignificanceLevel, constants.NumberOfHistogramBins)
+	}
+	return models.ProfitRiskSummary{
+		Samples:             items.TotalItemQuantity(),
+		TopDropsProfitShare: items.TopDropsProfitShare(valuation, constants.TopDropsFraction),
+		TopDropsFraction:    constants.TopDropsFraction,
+		Day:                 risk(1),
+		Season:              risk(constants.DaysPerSeason),
+	}
+}
+
+// For the whole arena if the metadata has no challenger. dailyThreshold is the daily profit whose chance of not being
+// reached is reported; if it isn't positive the predicted mean daily profit is used.
+func (s *ProfitRiskService) Risk(valuation valuations.ItemValuation, metadata models.BattledomeItemMetadata, dailyThreshold float64) (*models.ProfitRiskReport, error) {
+	var realData, generatedData models.NormalisedBattledomeItems
+	var err error
+	if metadata.Challenger == "" {
+		realData, generatedData, err = s.ComparedBattledomeItems.CompareArena(metadata.Arena)
+	} else {
+		realData, generatedData, err = s.ComparedBattledomeItems.CompareByMetadata(metadata)
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to get drops for %s", metadata.String())
+	}
+
+	if dailyThreshold <= 0 {
+		dailyThreshold, err = generatedData.MeanDropsProfit(valuation)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get predicted mean drops profit")
+		}
+	}
+
+	return &models.ProfitRiskReport{
+		Valuation:  valuation.Description(),
+		Arena:      metadata.Arena,
+		Challenger: metadata.Challenger,
+		Difficulty: metadata.Difficulty,
+		Predicted:  s.profitRiskSummary(valuation, generatedData, dailyThreshold),
+		Actual:     s.profitRiskSummary(valuation, realData, dailyThreshold),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type fixedValuation map[string]float64
+
+func (v fixedValuation) Value(itemName string) float64 {
+	return v[itemName]
+}
+
+func (v fixedValuation) Description() string {
+	return "fixed"
+}
+
+type fixedComparedItems struct {
+	realData      models.NormalisedBattledomeItems
+	generatedData models.NormalisedBattledomeItems
+}
+
+func (c *fixedComparedItems) CompareArena(arena models.Arena) (models.NormalisedBattledomeItems, models.NormalisedBattledomeItems, error) {
+	return c.realData, c.generatedData, nil
+}
+
+func (c *fixedComparedItems) CompareByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, models.NormalisedBattledomeItems, error) {
+	return c.realData, c.generatedData, nil
+}
+
+func normalisedItems(quantities map[string]int32) models.NormalisedBattledomeItems {
+	items := models.NormalisedBattledomeItems{}
+	for name, quantity := range quantities {
+		items[models.ItemName(name)] = &models.BattledomeItem{Name: models.ItemName(name), Quantity: quantity}
+	}
+	return items
+}
+
+func TestProfitRiskOfCertainProfit(t *testing.T) {
+	valuation := fixedValuation{"Robot Muffin": 100, "Ridiculously Heavy Battle Hammer": 9_900}
+	target := NewProfitRiskService(&fixedComparedItems{
+		realData:      normalisedItems(map[string]int32{"Robot Muffin": 99, "Ridiculously Heavy Battle Hammer": 1}),
+		generatedData: normalisedItems(map[string]int32{"Robot Muffin": 10}),
+	}, NewBootstrapService(DayBootstrap, constants.NumberOfBootstrapSamples, 0, 1))
+
+	report, err := target.Risk(valuation, models.BattledomeItemMetadata{Arena: "Central Arena"}, 0)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	predicted := report.Predicted
+	if predicted.Day.Median != 1_500 || predicted.Season.Mean != 45_000 || predicted.Day.ExpectedShortfall != 1_500 {
+		t.Fatalf("Expected every predicted day to make 1,500 NP, but got %+v", predicted.Day)
+	}
+	// The threshold defaults to the predicted mean, which a day can't fall below
+	if predicted.Day.Threshold != 1_500 || predicted.Day.ProbabilityBelowThreshold != 0 {
+		t.Fatalf("Expected no chance of a predicted day below 1,500 NP, but got %+v", predicted.Day)
+	}
+	if len(predicted.Day.Histogram) != 1 || predicted.Day.Histogram[0].Count != constants.NumberOfBootstrapSamples {
+		t.Fatalf("Expected a single histogram bin, but got %+v", predicted.Day.Histogram)
+	}
+
+	// The hammer is the top 1% of actual drops and is worth half of everything
+	if share := report.Actual.TopDropsProfitShare; share != 0.5 {
+		t.Fatalf("Expected the top 1%% of drops to make up half the profit, but got %f", share)
+	}
+
+	// A day only makes more than 1,500 NP if one of its 15 drops is the hammer, which has a 1 - 0.99^15 ≈ 14% chance
+	report, err = target.Risk(valuation, models.BattledomeItemMetadata{Arena: "Central Arena"}, 2_000)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if probability := report.Actual.Day.ProbabilityBelowThreshold; probability < 0.84 || probability > 0.88 {
+		t.Fatalf("Expected about 86%% of actual days to make less than 2,000 NP, but got %f", probability)
+	}
+}
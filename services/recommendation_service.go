@@ -0,0 +1,248 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+	"github.com/palantir/stacktrace"
+)
+
+type RecommendationCriterion string
+
+const (
+	// Ranks by mean profit, however little data it comes from
+	MeanCriterion RecommendationCriterion = "mean"
+	// Ranks by the lower bound of the confidence interval for the mean profit, so fights with few drops are held back
+	LowerBoundCriterion RecommendationCriterion = "lower"
+	// Ranks by the mean shrunk towards the mean of every fight, by less the more drops a fight has
+	PosteriorCriterion RecommendationCriterion = "posterior"
+)
+
+var RecommendationCriteria = []RecommendationCriterion{MeanCriterion, LowerBoundCriterion, PosteriorCriterion}
+
+func ParseRecommendationCriterion(criterion string) (RecommendationCriterion, error) {
+	for _, recommendationCriterion := range RecommendationCriteria {
+		if strings.EqualFold(string(recommendationCriterion), criterion) {
+			return recommendationCriterion, nil
+		}
+	}
+	return "", fmt.Errorf("unknown risk tolerance %q (expected one of %s)", criterion, strings.Join(helpers.Map(RecommendationCriteria, func(criterion RecommendationCriterion) string {
+		return string(criterion)
+	}), ", "))
+}
+
+// Empty challengers or difficulties allow all of them
+type RecommendationConstraints struct {
+	Challengers    []models.Challenger
+	Difficulties   []models.Difficulty
+	MinimumSamples int
+	Criterion      RecommendationCriterion
+	ShortlistSize  int
+}
+
+type ChallengerComparisons interface {
+	CompareAllChallengers(valuation valuations.ItemValuation) ([]models.NormalisedBattledomeItems, error)
+}
+
+type RecommendationService struct {
+	ChallengerComparisons
+	BootstrapService    *BootstrapService
+	BattledomeCatalogue *helpers.BattledomeCatalogue
+}
+
+func NewRecommendationService(challengerComparisons ChallengerComparisons, bootstrapService *BootstrapService, battledomeCatalogue *helpers.BattledomeCatalogue) *RecommendationService {
+	return &RecommendationService{
+		ChallengerComparisons: challengerComparisons,
+		BootstrapService:      bootstrapService,
+		BattledomeCatalogue:   battledomeCatalogue,
+	}
+}
+
+func containsFold[T ~string](values []T, value T) bool {
+	return slices.ContainsFunc(values, func(other T) bool {
+		return strings.EqualFold(string(other), string(value))
+	})
+}
+
+type fightProfit struct {
+	metadata models.BattledomeItemMetadata
+	items    models.NormalisedBattledomeItems
+	samples  int
+	mean     float64
+	// Squared standard error of the mean
+	variance float64
+}
+
+// Estimates how much the true mean profit differs between fights by the method of moments, i.e. the spread of the
+// means less the part of it that is only noise
+func profitPrior(fights []fightProfit) (mean float64, variance float64) {
+	if len(fights) == 0 {
+		return 0.0, 0.0
+	}
+	means := helpers.Map(fights, func(fight fightProfit) float64 {
+		return fight.mean
+	})
+	mean = helpers.Sum(means) / float64(len(means))
+	if len(fights) < 2 {
+		return mean, 0.0
+	}
+	spread := 0.0
+	for _, fightMean := range means {
+		spread += (fightMean - mean) * (fightMean - mean)
+	}
+	spread /= float64(len(means) - 1)
+	noise := helpers.Sum(helpers.Map(fights, func(fight fightProfit) float64 {
+		return fight.variance
+	})) / float64(len(fights))
+	return mean, max(0.0, spread-noise)
+}
+
+// The posterior mean of a normal prior updated with a normal estimate of the fight's mean
+func posteriorProfit(fight fightProfit, priorMean float64, priorVariance float64) float64 {
+	if fight.variance == 0 {
+		return fight.mean
+	}
+	if priorVariance == 0 {
+		return priorMean
+	}
+	return (priorMean/priorVariance + fight.mean/fight.variance) / (1/priorVariance + 1/fight.variance)
+}
+
+func (s *RecommendationService) fightProfit(valuation valuations.ItemValuation, items models.NormalisedBattledomeItems) (fightProfit, error) {
+	metadata, err := items.Metadata()
+	if err != nil {
+		return fightProfit{}, stacktrace.Propagate(err, "failed to get metadata")
+	}
+	mean, err := items.MeanDropsProfit(valuation)
+	if err != nil {
+		return fightProfit{}, stacktrace.Propagate(err, "failed to get mean drops profit for %s", metadata.String())
+	}
+	stdev, err := items.DropsProfitStdev(valuation)
+	if err != nil {
+		return fightProfit{}, stacktrace.Propagate(err, "failed to get profit standard deviation for %s", metadata.String())
+	}
+	samples := items.TotalItemQuantity()
+	days := float64(samples) / constants.BattledomeDropsPerDay
+	return fightProfit{
+		metadata: metadata,
+		items:    items,
+		samples:  samples,
+		mean:     mean,
+		variance: helpers.When(days > 0, stdev*stdev/days, 0.0),
+	}, nil
+}
+
+func (s *RecommendationService) recommendation(valuation valuations.ItemValuation, fight fightProfit, criterion RecommendationCriterion, priorMean float64, priorVariance float64) (models.Recommendation, error) {
+	recommendation := models.Recommendation{
+		Metadata:        fight.metadata,
+		Samples:         fight.samples,
+		MeanProfit:      fight.mean,
+		PosteriorProfit: posteriorProfit(fight, priorMean, priorVariance),
+	}
+	if challenger, exists := s.BattledomeCatalogue.Challenger(string(fight.metadata.Arena), string(fight.metadata.Challenger)); exists {
+		recommendation.Unlock = challenger.Unlock
+	}
+	var err error
+	recommendation.LeftBound, recommendation.RightBound, err = s.BootstrapService.MeanProfitConfidenceInterval(valuation, fight.items)
+	if err != nil {
+		return recommendation, stacktrace.Propagate(err, "failed to get profit confidence interval for %s", fight.metadata.String())
+	}
+
+	switch criterion {
+	case MeanCriterion:
+		recommendation.Score = recommendation.MeanProfit
+	case LowerBoundCriterion:
+		recommendation.Score = recommendation.LeftBound
+	case PosteriorCriterion:
+		recommendation.Score = recommendation.PosteriorProfit
+	default:
+		return recommendation, fmt.Errorf("unknown risk tolerance %q", criterion)
+	}
+
+	totalProfit, err := fight.items.TotalProfit(valuation)
+	if err != nil {
+		return recommendation, stacktrace.Propagate(err, "failed to get total profit for %s", fight.metadata.String())
+	}
+	orderedItems, err := fight.items.ItemsOrderedByProfit(valuation)
+	if err != nil {
+		return recommendation, stacktrace.Propagate(err, "failed to order items by profit for %s", fight.metadata.String())
+	}
+	if len(orderedItems) > 0 && totalProfit > 0 {
+		recommendation.TopItem = orderedItems[0].Name
+		recommendation.TopItemShare = orderedItems[0].Profit(valuation) / totalProfit
+	}
+	return recommendation, nil
+}
+
+// Ranks the recorded fights that meet the constraints by the constraints' criterion
+func (s *RecommendationService) Recommend(valuation valuations.ItemValuation, constraints RecommendationConstraints) (*models.RecommendationReport, error) {
+	challengerData, err := s.ChallengerComparisons.CompareAllChallengers(valuation)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to compare all challengers")
+	}
+
+	report := &models.RecommendationReport{
+		Criterion:      string(constraints.Criterion),
+		MinimumSamples: constraints.MinimumSamples,
+	}
+	fights := []fightProfit{}
+	candidates := []fightProfit{}
+	recordedChallengers := []models.Challenger{}
+	for _, items := range challengerData {
+		fight, err := s.fightProfit(valuation, items)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get profit of fight")
+		}
+		fights = append(fights, fight)
+		recordedChallengers = append(recordedChallengers, fight.metadata.Challenger)
+
+		switch {
+		case len(constraints.Challengers) > 0 && !containsFold(constraints.Challengers, fight.metadata.Challenger):
+			report.UnbeatableChallenger++
+		case len(constraints.Difficulties) > 0 && !containsFold(constraints.Difficulties, fight.metadata.Difficulty):
+			report.LockedDifficulty++
+		case fight.samples < constraints.MinimumSamples:
+			report.TooFewSamples++
+		default:
+			candidates = append(candidates, fight)
+		}
+	}
+	for _, challenger := range constraints.Challengers {
+		if !containsFold(recordedChallengers, challenger) {
+			report.UnknownChallengers = append(report.UnknownChallengers, challenger)
+		}
+	}
+
+	// Every recorded fight informs the prior, whether or not it can be recommended
+	priorMean, priorVariance := profitPrior(fights)
+	report.PriorProfit = priorMean
+	report.Candidates = len(candidates)
+	recommendations := []models.Recommendation{}
+	for _, fight := range candidates {
+		recommendation, err := s.recommendation(valuation, fight, constraints.Criterion, priorMean, priorVariance)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to rank %s", fight.metadata.String())
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+
+	slices.SortStableFunc(recommendations, func(first models.Recommendation, second models.Recommendation) int {
+		if first.Score != second.Score {
+			return helpers.When(first.Score > second.Score, -1, 1)
+		}
+		if first.Samples != second.Samples {
+			return second.Samples - first.Samples
+		}
+		return strings.Compare(first.Metadata.FightName(), second.Metadata.FightName())
+	})
+	if constraints.ShortlistSize > 0 && len(recommendations) > constraints.ShortlistSize {
+		recommendations = recommendations[:constraints.ShortlistSize]
+	}
+	report.Recommendations = recommendations
+	return report, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+)
+
+type fixedChallengers []models.NormalisedBattledomeItems
+
+func (c fixedChallengers) CompareAllChallengers(valuation valuations.ItemValuation) ([]models.NormalisedBattledomeItems, error) {
+	return c, nil
+}
+
+func fightItems(metadata models.BattledomeItemMetadata, quantities map[string]int32) models.NormalisedBattledomeItems {
+	items := normalisedItems(quantities)
+	for _, item := range items {
+		item.Metadata = metadata
+	}
+	return items
+}
+
+func fightBattle(metadata models.BattledomeItemMetadata, quantities map[string]int32) *models.Battle {
+	return &models.Battle{Metadata: metadata, Items: helpers.Values(fightItems(metadata, quantities))}
+}
+
+func newTestRecommendationService() *RecommendationService {
+	return NewRecommendationService(fixedChallengers{
+		// A steady earner with plenty of data
+		fightItems(models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Kasuki Lu", Difficulty: "Mighty"}, map[string]int32{"Robot Muffin": 1_400, "Cursed Wand of Shadow": 100}),
+		// A better mean, but from one lucky drop
+		fightItems(models.BattledomeItemMetadata{Arena: "Ugga Dome", Challenger: "Cybunny Scout", Difficulty: "Mighty"}, map[string]int32{"Robot Muffin": 149, "Cursed Wand of Shadow": 1, "Ridiculously Heavy Battle Hammer": 1}),
+		fightItems(models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Kasuki Lu", Difficulty: "Average"}, map[string]int32{"Robot Muffin": 300}),
+		fightItems(models.BattledomeItemMetadata{Arena: "Frost Arena", Challenger: "Snowager", Difficulty: "Mighty"}, map[string]int32{"Robot Muffin": 300}),
+	}, NewBootstrapService(BootstrapMethod(constants.BootstrapMethod), 1_000, 0, 1), &helpers.BattledomeCatalogue{
+		Arenas: []helpers.CatalogueArena{
+			{Name: "Central Arena", Challengers: []helpers.CatalogueChallenger{{Name: "Kasuki Lu", Unlock: "Beating the Flaming Meerca"}}},
+		},
+	})
+}
+
+var testRecommendationValuation = fixedValuation{"Robot Muffin": 100, "Cursed Wand of Shadow": 2_000, "Ridiculously Heavy Battle Hammer": 200_000}
+
+func TestRecommendationConstraints(t *testing.T) {
+	report, err := newTestRecommendationService().Recommend(testRecommendationValuation, RecommendationConstraints{
+		Challengers:    []models.Challenger{"kasuki lu", "Cybunny Scout", "Nobody"},
+		Difficulties:   []models.Difficulty{"Mighty"},
+		MinimumSamples: 100,
+		Criterion:      MeanCriterion,
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if report.Candidates != 2 || report.UnbeatableChallenger != 1 || report.LockedDifficulty != 1 || report.TooFewSamples != 0 {
+		t.Fatalf("Expected 2 candidates with 1 fight ruled out by challenger and 1 by difficulty, but got %+v", report)
+	}
+	if !slices.Equal(report.UnknownChallengers, []models.Challenger{"Nobody"}) {
+		t.Fatalf("Expected Nobody to be unknown, but got %v", report.UnknownChallengers)
+	}
+	if report.Recommendations[0].Metadata.Arena != "Ugga Dome" {
+		t.Fatalf("Expected the lucky fight to have the best mean, but got %s first", report.Recommendations[0].Metadata.FightName())
+	}
+	if report.Recommendations[1].Unlock != "Beating the Flaming Meerca" || report.Recommendations[0].Unlock != "" {
+		t.Fatalf("Expected only Kasuki Lu to need unlocking, but got %q and %q", report.Recommendations[0].Unlock, report.Recommendations[1].Unlock)
+	}
+}
+
+func TestRecommendationLowerBoundHoldsBackLuckyFights(t *testing.T) {
+	report, err := newTestRecommendationService().Recommend(testRecommendationValuation, RecommendationConstraints{
+		Difficulties:  []models.Difficulty{"Mighty"},
+		Criterion:     LowerBoundCriterion,
+		ShortlistSize: 2,
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(report.Recommendations) != 2 || report.Recommendations[0].Metadata.Arena != "Central Arena" {
+		t.Fatalf("Expected Central Arena to have the best lower bound, but got %v", report.Recommendations)
+	}
+	// A mean of 3,400 NP a day with a standard error of 1,900 × sqrt(1/15 × 14/15) / sqrt(1,500) × 15 ≈ 184 NP, rather
+	// than the 1,500 NP of the 35% of days without a wand
+	if score := report.Recommendations[0].Score; score < 2_950 || score > 3_150 {
+		t.Fatalf("Expected Central Arena to be ranked by the lower bound of its mean, about 3,040 NP, but got %f", score)
+	}
+}
+
+func TestRecommendationPosteriorIsBetweenPriorAndMean(t *testing.T) {
+	report, err := newTestRecommendationService().Recommend(testRecommendationValuation, RecommendationConstraints{Criterion: PosteriorCriterion})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for _, recommendation := range report.Recommendations {
+		low, high := min(recommendation.MeanProfit, report.PriorProfit), max(recommendation.MeanProfit, report.PriorProfit)
+		if recommendation.PosteriorProfit < low-1e-6 || recommendation.PosteriorProfit > high+1e-6 {
+			t.Fatalf("Expected the posterior of %s to be between %f and %f, but got %f", recommendation.Metadata.FightName(), low, high, recommendation.PosteriorProfit)
+		}
+	}
+}
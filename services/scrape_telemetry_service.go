@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/palantir/stacktrace"
+)
+
+type SavedScrapeRecords interface {
+	Parse(filePath string) ([]helpers.ScrapeRecord, error)
+}
+
+type ScrapeTelemetryService struct {
+	SavedScrapeRecords
+}
+
+func NewScrapeTelemetryService(savedScrapeRecords SavedScrapeRecords) *ScrapeTelemetryService {
+	return &ScrapeTelemetryService{
+		SavedScrapeRecords: savedScrapeRecords,
+	}
+}
+
+type ScrapeFailureRate struct {
+	Key             string
+	Requests        int
+	Failures        int
+	FailuresByClass map[string]int
+	TotalLatencyMs  int64
+}
+
+func (r *ScrapeFailureRate) Rate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Failures) / float64(r.Requests)
+}
+
+func (r *ScrapeFailureRate) MeanLatencyMs() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.TotalLatencyMs) / float64(r.Requests)
+}
+
+// The most common reason for failure, or "" if nothing failed
+func (r *ScrapeFailureRate) TopFailureClass() string {
+	topClass := ""
+	for class, count := range r.FailuresByClass {
+		if topClass == "" || count > r.FailuresByClass[topClass] || (count == r.FailuresByClass[topClass] && class < topClass) {
+			topClass = class
+		}
+	}
+	return topClass
+}
+
+func (s *ScrapeTelemetryService) Records() ([]helpers.ScrapeRecord, error) {
+	if !helpers.IsFileExists(constants.ScrapeTelemetryFilePath()) {
+		return []helpers.ScrapeRecord{}, nil
+	}
+
+	records, err := s.SavedScrapeRecords.Parse(constants.ScrapeTelemetryFilePath())
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse %q as scrape telemetry", constants.ScrapeTelemetryFilePath())
+	}
+	return records, nil
+}
+
+func (s *ScrapeTelemetryService) FailureRatesBy(records []helpers.ScrapeRecord, keyFn func(helpers.ScrapeRecord) string) []*ScrapeFailureRate {
+	rates := map[string]*ScrapeFailureRate{}
+	for _, record := range records {
+		key := keyFn(record)
+		rate, exists := rates[key]
+		if !exists {
+			rate = &ScrapeFailureRate{
+				Key:             key,
+				FailuresByClass: map[string]int{},
+			}
+			rates[key] = rate
+		}
+
+		rate.Requests++
+		rate.TotalLatencyMs += record.LatencyMs
+		if record.IsFailure() {
+			rate.Failures++
+			rate.FailuresByClass[record.ErrorClass]++
+		}
+	}
+	return helpers.OrderBy(helpers.Values(rates), func(rate *ScrapeFailureRate) string {
+		return rate.Key
+	})
+}
+
+func (s *ScrapeTelemetryService) FailureRatesBySource(records []helpers.ScrapeRecord) []*ScrapeFailureRate {
+	return s.FailureRatesBy(records, func(record helpers.ScrapeRecord) string {
+		return record.Source
+	})
+}
+
+func (s *ScrapeTelemetryService) FailureRatesByUserAgent(records []helpers.ScrapeRecord) []*ScrapeFailureRate {
+	return helpers.OrderByDescending(s.FailureRatesBy(records, func(record helpers.ScrapeRecord) string {
+		return helpers.When(record.UserAgent == "", "(none)", record.UserAgent)
+	}), func(rate *ScrapeFailureRate) float64 {
+		return rate.Rate()
+	})
+}
+
+func (s *ScrapeTelemetryService) FailureRatesByHour(records []helpers.ScrapeRecord) []*ScrapeFailureRate {
+	return s.FailureRatesBy(records, func(record helpers.ScrapeRecord) string {
+		return fmt.Sprintf("%02d:00", record.Timestamp.Local().Hour())
+	})
+}
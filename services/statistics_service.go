@@ -1,15 +1,73 @@
 package services
 
 import (
+	"fmt"
 	"math"
+	"strings"
 
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
 	"gonum.org/v1/gonum/stat/distuv"
 )
 
-type StatisticsService struct{}
+type BinomialIntervalMethod string
 
-func NewStatisticsService() *StatisticsService {
-	return &StatisticsService{}
+const (
+	ClopperPearsonMethod BinomialIntervalMethod = "clopper-pearson"
+	WilsonMethod         BinomialIntervalMethod = "wilson"
+	AgrestiCoullMethod   BinomialIntervalMethod = "agresti-coull"
+	JeffreysMethod       BinomialIntervalMethod = "jeffreys"
+	MidPMethod           BinomialIntervalMethod = "mid-p"
+)
+
+var BinomialIntervalMethods = []BinomialIntervalMethod{ClopperPearsonMethod, WilsonMethod, AgrestiCoullMethod, JeffreysMethod, MidPMethod}
+
+func ParseBinomialIntervalMethod(method string) (BinomialIntervalMethod, error) {
+	for _, intervalMethod := range BinomialIntervalMethods {
+		if strings.EqualFold(string(intervalMethod), method) {
+			return intervalMethod, nil
+		}
+	}
+	return "", fmt.Errorf("unknown interval method %q (expected one of %s)", method, strings.Join(helpers.Map(BinomialIntervalMethods, func(method BinomialIntervalMethod) string {
+		return string(method)
+	}), ", "))
+}
+
+type StatisticsService struct {
+	// Used by BinomialInterval
+	IntervalMethod BinomialIntervalMethod
+}
+
+func NewStatisticsService(intervalMethod BinomialIntervalMethod) *StatisticsService {
+	return &StatisticsService{
+		IntervalMethod: intervalMethod,
+	}
+}
+
+// Calculates a confidence interval for a drop rate with IntervalMethod
+func (s *StatisticsService) BinomialInterval(x int, n int, alpha float64) (float64, float64, error) {
+	return s.BinomialIntervalWith(s.IntervalMethod, x, n, alpha)
+}
+
+func (s *StatisticsService) BinomialIntervalWith(method BinomialIntervalMethod, x int, n int, alpha float64) (float64, float64, error) {
+	if x < 0 || x > n {
+		return 0, 0, fmt.Errorf("expected between 0 and %d successes but got %d", n, x)
+	}
+
+	switch method {
+	case ClopperPearsonMethod:
+		return s.ClopperPearsonInterval(x, n, alpha)
+	case WilsonMethod:
+		return s.WilsonInterval(x, n, alpha)
+	case AgrestiCoullMethod:
+		return s.AgrestiCoullInterval(x, n, alpha)
+	case JeffreysMethod:
+		return s.JeffreysInterval(x, n, alpha)
+	case MidPMethod:
+		return s.MidPInterval(x, n, alpha)
+	default:
+		return 0, 0, fmt.Errorf("unknown interval method %q", method)
+	}
 }
 
 // Calculates a confidence interval with significance level {alpha}
@@ -38,3 +96,111 @@ func (s *StatisticsService) ClopperPearsonInterval(x int, n int, alpha float64)
 
 	return betaLower.Quantile(alpha / 2), betaUpper.Quantile(1 - alpha/2), nil
 }
+
+// The score interval; close to the nominal coverage on average, though it dips below it for some p
+func (s *StatisticsService) WilsonInterval(x int, n int, alpha float64) (float64, float64, error) {
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	z := distuv.UnitNormal.Quantile(1 - alpha/2)
+	trials := float64(n)
+	centre := (float64(x) + z*z/2) / (trials + z*z)
+	halfWidth := z / (trials + z*z) * math.Sqrt(float64(x)*float64(n-x)/trials+z*z/4)
+	return max(0, centre-halfWidth), min(1, centre+halfWidth), nil
+}
+
+// The Wald interval after adding z²/2 successes and z²/2 failures
+func (s *StatisticsService) AgrestiCoullInterval(x int, n int, alpha float64) (float64, float64, error) {
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	z := distuv.UnitNormal.Quantile(1 - alpha/2)
+	adjustedTrials := float64(n) + z*z
+	adjustedRate := (float64(x) + z*z/2) / adjustedTrials
+	halfWidth := z * math.Sqrt(adjustedRate*(1-adjustedRate)/adjustedTrials)
+	return max(0, adjustedRate-halfWidth), min(1, adjustedRate+halfWidth), nil
+}
+
+// The equal-tailed interval of the posterior under the Jeffreys prior, Beta(1/2, 1/2)
+func (s *StatisticsService) JeffreysInterval(x int, n int, alpha float64) (float64, float64, error) {
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	posterior := distuv.Beta{
+		Alpha: float64(x) + 0.5,
+		Beta:  float64(n-x) + 0.5,
+	}
+	leftBound := helpers.When(x == 0, 0.0, posterior.Quantile(alpha/2))
+	rightBound := helpers.When(x == n, 1.0, posterior.Quantile(1-alpha/2))
+	return leftBound, rightBound, nil
+}
+
+// Finds p in [0, 1] where the decreasing function f crosses alpha
+func bisectDecreasing(f func(p float64) float64, alpha float64) float64 {
+	low, high := 0.0, 1.0
+	for range 100 {
+		middle := (low + high) / 2
+		if f(middle) > alpha {
+			low = middle
+		} else {
+			high = middle
+		}
+	}
+	return (low + high) / 2
+}
+
+// Clopper-Pearson with only half of the probability of x itself counted in each tail, so it's less conservative
+func (s *StatisticsService) MidPInterval(x int, n int, alpha float64) (float64, float64, error) {
+	if n == 0 {
+		return 0, 0, nil
+	}
+
+	// P(X < x) + P(X = x)/2, which shrinks as p grows; the upper tail is 1 minus it
+	lowerTail := func(p float64) float64 {
+		distribution := distuv.Binomial{N: float64(n), P: p}
+		return distribution.CDF(float64(x)) - distribution.Prob(float64(x))/2
+	}
+	leftBound := 0.0
+	if x > 0 {
+		leftBound = bisectDecreasing(lowerTail, 1-alpha/2)
+	}
+	rightBound := 1.0
+	if x < n {
+		rightBound = bisectDecreasing(lowerTail, alpha/2)
+	}
+	return leftBound, rightBound, nil
+}
+
+// How often the method's interval contains the true drop rate p after n drops, worked out from the probability of
+// every number of successes rather than by sampling. Numbers of successes too unlikely to matter are skipped.
+func (s *StatisticsService) Coverage(method BinomialIntervalMethod, n int, p float64, alpha float64) (models.BinomialIntervalCoverage, error) {
+	coverage := models.BinomialIntervalCoverage{
+		Method: string(method),
+		Trials: n,
+		Rate:   p,
+	}
+	distribution := distuv.Binomial{N: float64(n), P: p}
+	spread := 12 * math.Sqrt(float64(n)*p*(1-p))
+	lowest := max(0, int(math.Floor(float64(n)*p-spread)))
+	highest := min(n, int(math.Ceil(float64(n)*p+spread))+1)
+	for x := lowest; x <= highest; x++ {
+		probability := distribution.Prob(float64(x))
+		leftBound, rightBound, err := s.BinomialIntervalWith(method, x, n, alpha)
+		if err != nil {
+			return coverage, err
+		}
+		coverage.MeanWidth += probability * (rightBound - leftBound)
+		switch {
+		case p < leftBound:
+			coverage.BelowInterval += probability
+		case p > rightBound:
+			coverage.AboveInterval += probability
+		default:
+			coverage.Coverage += probability
+		}
+	}
+	return coverage, nil
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBinomialIntervals(t *testing.T) {
+	// 5 drops out of 100
+	expected := map[BinomialIntervalMethod][2]float64{
+		ClopperPearsonMethod: {0.0164, 0.1128},
+		WilsonMethod:         {0.0215, 0.1118},
+		AgrestiCoullMethod:   {0.0187, 0.1146},
+		JeffreysMethod:       {0.0193, 0.1061},
+		MidPMethod:           {0.0185, 0.1073},
+	}
+	target := NewStatisticsService(ClopperPearsonMethod)
+	for method, bounds := range expected {
+		leftBound, rightBound, err := target.BinomialIntervalWith(method, 5, 100, 0.05)
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		if math.Abs(leftBound-bounds[0]) > 5e-4 || math.Abs(rightBound-bounds[1]) > 5e-4 {
+			t.Fatalf("Expected the %s interval to be [%.4f, %.4f], but got [%.4f, %.4f]", method, bounds[0], bounds[1], leftBound, rightBound)
+		}
+	}
+}
+
+func TestClopperPearsonCoverageIsConservative(t *testing.T) {
+	target := NewStatisticsService(ClopperPearsonMethod)
+	for _, n := range []int{150, 1_500} {
+		for _, p := range []float64{0.001, 0.01, 0.05} {
+			coverage, err := target.Coverage(ClopperPearsonMethod, n, p, 0.05)
+			if err != nil {
+				t.Fatalf("%s", err)
+			}
+			if coverage.Coverage < 0.95 || math.Abs(coverage.Coverage+coverage.BelowInterval+coverage.AboveInterval-1) > 1e-6 {
+				t.Fatalf("Expected at least 95%% coverage out of a total of 100%% for n = %d and p = %g, but got %+v", n, p, coverage)
+			}
+		}
+	}
+}
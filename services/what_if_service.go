@@ -0,0 +1,133 @@
+package services
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
+	"github.com/palantir/stacktrace"
+)
+
+// Reranks recorded fights and arenas by mean profit under a price scenario
+type WhatIfService struct {
+	ChallengerComparisons
+	ComparedBattledomeItems
+	BattledomeCatalogue *helpers.BattledomeCatalogue
+}
+
+func NewWhatIfService(challengerComparisons ChallengerComparisons, dataComparisonService ComparedBattledomeItems, battledomeCatalogue *helpers.BattledomeCatalogue) *WhatIfService {
+	return &WhatIfService{
+		ChallengerComparisons:   challengerComparisons,
+		ComparedBattledomeItems: dataComparisonService,
+		BattledomeCatalogue:     battledomeCatalogue,
+	}
+}
+
+// Fills in the ranks from the profits; ties go to the fight with more drops
+func rankWhatIf(rankings []models.WhatIfRanking) []models.WhatIfRanking {
+	rank := func(profit func(ranking models.WhatIfRanking) float64) []models.WhatIfRanking {
+		ranked := slices.Clone(rankings)
+		slices.SortStableFunc(ranked, func(first models.WhatIfRanking, second models.WhatIfRanking) int {
+			if profit(first) != profit(second) {
+				return helpers.When(profit(first) > profit(second), -1, 1)
+			}
+			if first.Samples != second.Samples {
+				return second.Samples - first.Samples
+			}
+			return strings.Compare(first.Metadata.FightName(), second.Metadata.FightName())
+		})
+		return ranked
+	}
+
+	baselineRanks := map[models.BattledomeItemMetadata]int{}
+	for i, ranking := range rank(func(ranking models.WhatIfRanking) float64 { return ranking.BaselineProfit }) {
+		baselineRanks[ranking.Metadata] = i + 1
+	}
+	ranked := rank(func(ranking models.WhatIfRanking) float64 { return ranking.ScenarioProfit })
+	for i := range ranked {
+		ranked[i].BaselineRank = baselineRanks[ranked[i].Metadata]
+		ranked[i].ScenarioRank = i + 1
+	}
+	return ranked
+}
+
+// An arena's profit is worked out as the arena comparison does, so challenger-specific drops count as worthless if
+// constants.ShouldIgnoreChallengerDropsInArenaComparison is set
+func (s *WhatIfService) arenaProfit(valuation valuations.ItemValuation, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) (float64, error) {
+	if constants.ShouldIgnoreChallengerDropsInArenaComparison {
+		return realData.ArenaMeanDropsProfit(valuation, generatedData)
+	}
+	return realData.MeanDropsProfit(valuation)
+}
+
+// Arenas with no recorded drops are left out
+func (s *WhatIfService) arenaRankings(baseline valuations.ItemValuation, scenario valuations.ItemValuation) ([]models.WhatIfRanking, error) {
+	rankings := []models.WhatIfRanking{}
+	for _, arenaName := range s.BattledomeCatalogue.ArenaNames() {
+		arena := models.Arena(arenaName)
+		realData, generatedData, err := s.ComparedBattledomeItems.CompareArena(arena)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to compare arena %q", arenaName)
+		}
+		samples := realData.TotalItemQuantity()
+		if samples == 0 {
+			continue
+		}
+		baselineProfit, err := s.arenaProfit(baseline, realData, generatedData)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get baseline profit for %s", arenaName)
+		}
+		scenarioProfit, err := s.arenaProfit(scenario, realData, generatedData)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get scenario profit for %s", arenaName)
+		}
+		rankings = append(rankings, models.WhatIfRanking{
+			Metadata:       models.BattledomeItemMetadata{Arena: arena},
+			Samples:        samples,
+			BaselineProfit: baselineProfit,
+			ScenarioProfit: scenarioProfit,
+		})
+	}
+	return rankings, nil
+}
+
+func (s *WhatIfService) Compare(baseline valuations.ItemValuation, scenario valuations.ItemValuation) (*models.WhatIfReport, error) {
+	challengerData, err := s.ChallengerComparisons.CompareAllChallengers(baseline)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to compare all challengers")
+	}
+
+	fights := []models.WhatIfRanking{}
+	for _, items := range challengerData {
+		metadata, err := items.Metadata()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get metadata")
+		}
+		baselineProfit, err := items.MeanDropsProfit(baseline)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get baseline profit for %s", metadata.String())
+		}
+		scenarioProfit, err := items.MeanDropsProfit(scenario)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get scenario profit for %s", metadata.String())
+		}
+		fights = append(fights, models.WhatIfRanking{
+			Metadata:       metadata,
+			Samples:        items.TotalItemQuantity(),
+			BaselineProfit: baselineProfit,
+			ScenarioProfit: scenarioProfit,
+		})
+	}
+
+	arenaRankings, err := s.arenaRankings(baseline, scenario)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to rank arenas")
+	}
+	return &models.WhatIfReport{
+		Arenas: rankWhatIf(arenaRankings),
+		Fights: rankWhatIf(fights),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+// The real and the generated drops of each arena
+type fixedArenas map[models.Arena][2]models.NormalisedBattledomeItems
+
+func (a fixedArenas) CompareArena(arena models.Arena) (models.NormalisedBattledomeItems, models.NormalisedBattledomeItems, error) {
+	return a[arena][0], a[arena][1], nil
+}
+
+func (a fixedArenas) CompareByMetadata(metadata models.BattledomeItemMetadata) (models.NormalisedBattledomeItems, models.NormalisedBattledomeItems, error) {
+	return a.CompareArena(metadata.Arena)
+}
+
+func TestWhatIfRanksChange(t *testing.T) {
+	target := NewWhatIfService(fixedChallengers{
+		fightItems(models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Kasuki Lu", Difficulty: "Mighty"}, map[string]int32{"Robot Muffin": 89, "Lu Codestone": 10, "Kasuki Lu Plushie": 1}),
+		fightItems(models.BattledomeItemMetadata{Arena: "Central Arena", Challenger: "Flaming Meerca", Difficulty: "Mighty"}, map[string]int32{"Robot Muffin": 100}),
+		fightItems(models.BattledomeItemMetadata{Arena: "Ugga Dome", Challenger: "Cybunny Scout", Difficulty: "Mighty"}, map[string]int32{"Robot Muffin": 95, "Cursed Wand of Shadow": 5}),
+	}, fixedArenas{
+		"Central Arena": {
+			normalisedItems(map[string]int32{"Robot Muffin": 189, "Lu Codestone": 10, "Kasuki Lu Plushie": 1}),
+			normalisedItems(map[string]int32{"Robot Muffin": 1, "Lu Codestone": 1}),
+		},
+		"Ugga Dome": {
+			normalisedItems(map[string]int32{"Robot Muffin": 95, "Cursed Wand of Shadow": 5}),
+			normalisedItems(map[string]int32{"Robot Muffin": 1, "Cursed Wand of Shadow": 1}),
+		},
+	}, &helpers.BattledomeCatalogue{Arenas: []helpers.CatalogueArena{{Name: "Central Arena"}, {Name: "Ugga Dome"}, {Name: "Frost Arena"}}})
+	baseline := fixedValuation{"Robot Muffin": 100, "Lu Codestone": 8_000, "Cursed Wand of Shadow": 2_000, "Kasuki Lu Plushie": 1_000_000}
+	scenario := fixedValuation{"Robot Muffin": 100, "Lu Codestone": 500, "Cursed Wand of Shadow": 2_000, "Kasuki Lu Plushie": 1_000_000}
+
+	report, err := target.Compare(baseline, scenario)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	// As in the arena comparison, the plushie only drops from Kasuki Lu and so is worthless to Central Arena, which
+	// makes (189 * 100 + 10 * 8,000) / 200 = 494.5 NP a drop before, and 119.5 NP after
+	if len(report.Arenas) != 2 {
+		t.Fatalf("Expected Frost Arena to be left out for having no drops, but got %+v", report.Arenas)
+	}
+	arena := report.Arenas[0]
+	if arena.Metadata.Arena != "Ugga Dome" || arena.BaselineRank != 2 || arena.RankChange() != 1 {
+		t.Fatalf("Expected Ugga Dome to move up from 2nd to 1st, but got %+v", arena)
+	}
+	central := report.Arenas[1]
+	if central.BaselineProfit != 494.5*15 || central.ScenarioProfit != 119.5*15 {
+		t.Fatalf("Expected Central Arena to go from %f to %f NP a day, but got %f to %f", 494.5*15, 119.5*15, central.BaselineProfit, central.ScenarioProfit)
+	}
+	// Fights keep their challenger's drops, so Kasuki Lu stays on top with the plushie
+	if report.Fights[0].Metadata.Challenger != "Kasuki Lu" || report.Fights[0].RankChange() != 0 {
+		t.Fatalf("Expected Kasuki Lu to stay 1st, but got %+v", report.Fights)
+	}
+}
@@ -0,0 +1,65 @@
+package valuations
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+)
+
+// Values codestones by the training they replace rather than what they sell for. Each lesson asks for one stone
+// picked at random from the school's set, so a stone saves the average price of that set. A fixed per-stone value
+// can be given instead.
+type CodestoneTrainingValuation struct {
+	ItemValuation
+	groups []*helpers.ItemGroup
+	// Used for every codestone instead of the average price of its set, if positive
+	stoneValue float64
+	mutex      sync.Mutex
+	// Keyed on group name
+	lessonValues map[string]float64
+}
+
+func NewCodestoneTrainingValuation(itemValuation ItemValuation, groups []*helpers.ItemGroup, stoneValue float64) *CodestoneTrainingValuation {
+	return &CodestoneTrainingValuation{
+		ItemValuation: itemValuation,
+		groups:        groups,
+		stoneValue:    stoneValue,
+		lessonValues:  map[string]float64{},
+	}
+}
+
+func (v *CodestoneTrainingValuation) lessonValue(group *helpers.ItemGroup) float64 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if lessonValue, exists := v.lessonValues[group.Name]; exists {
+		return lessonValue
+	}
+	stones := group.ItemNames()
+	lessonValue := 0.0
+	if len(stones) > 0 {
+		lessonValue = helpers.Sum(helpers.Map(stones, v.ItemValuation.Value)) / float64(len(stones))
+	}
+	v.lessonValues[group.Name] = lessonValue
+	return lessonValue
+}
+
+func (v *CodestoneTrainingValuation) Value(itemName string) float64 {
+	for _, group := range v.groups {
+		if !group.Contains(itemName) {
+			continue
+		}
+		if v.stoneValue > 0 {
+			return v.stoneValue
+		}
+		return v.lessonValue(group)
+	}
+	return v.ItemValuation.Value(itemName)
+}
+
+func (v *CodestoneTrainingValuation) Description() string {
+	if v.stoneValue > 0 {
+		return fmt.Sprintf("%s, with codestones at %s NP each for training", v.ItemValuation.Description(), helpers.FormatFloat(v.stoneValue))
+	}
+	return fmt.Sprintf("%s, with codestones at the average price of their training school's stones", v.ItemValuation.Description())
+}
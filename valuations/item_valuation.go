@@ -0,0 +1,132 @@
+package valuations
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/caches"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+)
+
+// How much an item is worth to whoever farmed it, e.g. what it would sell for
+type ItemValuation interface {
+	Value(itemName string) float64
+	// Shown at the top of the output, so that it's clear how items were valued
+	Description() string
+}
+
+// Items are worth exactly what the price source says
+type MarketValuation struct {
+	caches.ItemPriceCache
+}
+
+func NewMarketValuation(itemPriceCache caches.ItemPriceCache) *MarketValuation {
+	return &MarketValuation{
+		ItemPriceCache: itemPriceCache,
+	}
+}
+
+func (v *MarketValuation) Value(itemName string) float64 {
+	return v.ItemPriceCache.Price(itemName)
+}
+
+func (v *MarketValuation) Description() string {
+	return "market price"
+}
+
+// Items sold through the Shop Wizard have to be priced below everyone else's to sell
+type UndercutValuation struct {
+	ItemValuation
+	// e.g. 0.05 to sell for 5% under the market price
+	undercut float64
+}
+
+func NewUndercutValuation(itemValuation ItemValuation, undercut float64) *UndercutValuation {
+	return &UndercutValuation{
+		ItemValuation: itemValuation,
+		undercut:      undercut,
+	}
+}
+
+func (v *UndercutValuation) Value(itemName string) float64 {
+	return v.ItemValuation.Value(itemName) * (1 - v.undercut)
+}
+
+func (v *UndercutValuation) Description() string {
+	return fmt.Sprintf("%s, undercut by %s%%", v.ItemValuation.Description(), helpers.FormatPercentage(v.undercut))
+}
+
+type LiquidityTier struct {
+	// Items worth at least this much are in the tier
+	MinValue float64 `json:"minValue"`
+	// The fraction of their value lost to how long they take to sell
+	Haircut float64 `json:"haircut"`
+}
+
+// Expensive items rarely sell, so their listed price overstates what they're worth
+type LiquidityValuation struct {
+	ItemValuation
+	// Ordered by MinValue, highest first
+	tiers []LiquidityTier
+}
+
+func NewLiquidityValuation(itemValuation ItemValuation, tiers []LiquidityTier) *LiquidityValuation {
+	orderedTiers := slices.Clone(tiers)
+	slices.SortFunc(orderedTiers, func(first LiquidityTier, second LiquidityTier) int {
+		switch {
+		case first.MinValue > second.MinValue:
+			return -1
+		case first.MinValue < second.MinValue:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return &LiquidityValuation{
+		ItemValuation: itemValuation,
+		tiers:         orderedTiers,
+	}
+}
+
+func (v *LiquidityValuation) Value(itemName string) float64 {
+	value := v.ItemValuation.Value(itemName)
+	for _, tier := range v.tiers {
+		if value >= tier.MinValue {
+			return value * (1 - tier.Haircut)
+		}
+	}
+	return value
+}
+
+func (v *LiquidityValuation) Description() string {
+	haircuts := helpers.Map(v.tiers, func(tier LiquidityTier) string {
+		return fmt.Sprintf("%s%% off %s NP+", helpers.FormatPercentage(tier.Haircut), helpers.FormatFloat(tier.MinValue))
+	})
+	return fmt.Sprintf("%s, less %s", v.ItemValuation.Description(), strings.Join(haircuts, ", "))
+}
+
+// Items that are kept rather than sold are worth whatever their keeper says
+type KeepValuation struct {
+	ItemValuation
+	// Keyed on item name
+	values map[string]float64
+}
+
+func NewKeepValuation(itemValuation ItemValuation, values map[string]float64) *KeepValuation {
+	return &KeepValuation{
+		ItemValuation: itemValuation,
+		values:        values,
+	}
+}
+
+func (v *KeepValuation) Value(itemName string) float64 {
+	if value, exists := v.values[itemName]; exists {
+		return value
+	}
+	return v.ItemValuation.Value(itemName)
+}
+
+func (v *KeepValuation) Description() string {
+	return fmt.Sprintf("%s, with %d item(s) valued for keeping", v.ItemValuation.Description(), len(v.values))
+}
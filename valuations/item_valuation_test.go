@@ -0,0 +1,91 @@
+package valuations
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+)
+
+type fixedItemPriceCache map[string]float64
+
+func (c fixedItemPriceCache) Price(itemName string) float64 {
+	return c[itemName]
+}
+
+func (c fixedItemPriceCache) Close() error {
+	return nil
+}
+
+func shouldBeValuedAt(t *testing.T, valuation ItemValuation, itemName string, expected float64) {
+	if actual := valuation.Value(itemName); math.Abs(actual-expected) > 1e-9 {
+		t.Fatalf("Expected %q to be valued at %f, but it was valued at %f", itemName, expected, actual)
+	}
+}
+
+func TestValuationsAdjustMarketPrices(t *testing.T) {
+	prices := fixedItemPriceCache{
+		"Kew Codestone":                    30_000,
+		"Zed Codestone":                    10_000,
+		"Robot Muffin":                     500,
+		"Ridiculously Heavy Battle Hammer": 2_000_000,
+	}
+	var valuation ItemValuation = NewMarketValuation(prices)
+	valuation = NewUndercutValuation(valuation, 0.1)
+	valuation = NewLiquidityValuation(valuation, []LiquidityTier{{MinValue: 10_000, Haircut: 0.1}, {MinValue: 1_000_000, Haircut: 0.5}})
+	shouldBeValuedAt(t, valuation, "Robot Muffin", 450)
+	shouldBeValuedAt(t, valuation, "Ridiculously Heavy Battle Hammer", 900_000)
+
+	redCodestones := helpers.NewItemGroup("Red Codestones", "Kew Codestone", "Zed Codestone")
+	valuation = NewCodestoneTrainingValuation(valuation, []*helpers.ItemGroup{redCodestones}, 0)
+	// Kew is 30,000 × 0.9 × 0.9 and Zed is 10,000 × 0.9 (below the first tier), and each stone saves the average
+	shouldBeValuedAt(t, valuation, "Zed Codestone", 16_650)
+
+	valuation = NewKeepValuation(valuation, map[string]float64{"Zed Codestone": 12_345})
+	shouldBeValuedAt(t, valuation, "Zed Codestone", 12_345)
+	shouldBeValuedAt(t, valuation, "Kew Codestone", 16_650)
+}
+
+func TestCodestoneTrainingValuationUsesStoneValue(t *testing.T) {
+	redCodestones := helpers.NewItemGroup("Red Codestones", "Kew Codestone", "Zed Codestone")
+	valuation := NewCodestoneTrainingValuation(NewMarketValuation(fixedItemPriceCache{"Kew Codestone": 30_000}), []*helpers.ItemGroup{redCodestones}, 15_000)
+	shouldBeValuedAt(t, valuation, "Kew Codestone", 15_000)
+}
+
+func TestLoadValuationProfilesKeepsBuiltInProfiles(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "profiles.json")
+	content := `{"profiles": [{"name": "resale", "undercut": 0.05}, {"name": "Market", "undercut": 0.01}]}`
+	if err := os.WriteFile(filePath, []byte(content), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	profiles, err := LoadValuationProfiles(filePath)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	names := helpers.Map(profiles, func(profile ValuationProfileConfig) string {
+		return profile.Name
+	})
+	if len(names) != 3 || names[0] != "training" || names[1] != "resale" || names[2] != "Market" {
+		t.Fatalf("Expected the file's market profile to replace the built-in one, but got %q", names)
+	}
+
+	if err := os.WriteFile(filePath, []byte(`{"profiles": [{"name": "greedy", "undercut": 1.5}]}`), 0755); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, err := LoadValuationProfiles(filePath); err == nil {
+		t.Fatalf("Expected an undercut over 100%% to be rejected")
+	}
+}
+
+func TestStoneValueAppliesToAnyProfile(t *testing.T) {
+	prices := fixedItemPriceCache{"Kew Codestone": 30_000, "Robot Muffin": 100}
+	valuation, err := ValuationProfileConfig{Name: "market", StoneValue: 15_000}.Valuation(prices)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	shouldBeValuedAt(t, valuation, "Kew Codestone", 15_000)
+	shouldBeValuedAt(t, valuation, "Robot Muffin", 100)
+}
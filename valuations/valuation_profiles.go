@@ -0,0 +1,150 @@
+package valuations
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/caches"
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/palantir/stacktrace"
+)
+
+// A named way of valuing items. Market prices are adjusted in the order the fields are listed, and training and
+// keep values replace the adjusted price for the items they cover, since those items aren't sold.
+type ValuationProfileConfig struct {
+	Name string `json:"name"`
+	// The fraction under the market price that items are listed at, e.g. 0.05
+	Undercut       float64         `json:"undercut,omitempty"`
+	LiquidityTiers []LiquidityTier `json:"liquidityTiers,omitempty"`
+	// Whether codestones are valued by the training they replace
+	IsTraining bool `json:"training,omitempty"`
+	// Used for every codestone instead of the average price of its training school's stones, if positive; setting it
+	// values codestones for training even if IsTraining isn't set
+	StoneValue float64 `json:"stoneValue,omitempty"`
+	// What items are worth to keep, by item name
+	Keep map[string]float64 `json:"keep,omitempty"`
+}
+
+type ValuationProfilesFile struct {
+	Profiles []ValuationProfileConfig `json:"profiles"`
+}
+
+// Always available, though the profiles file can redefine them
+func builtInValuationProfiles() []ValuationProfileConfig {
+	return []ValuationProfileConfig{
+		{Name: constants.MarketValuationProfile},
+		{Name: constants.TrainingValuationProfile, IsTraining: true},
+	}
+}
+
+// The built-in profiles followed by those in the profiles file, if there is one
+func LoadValuationProfiles(filePath string) ([]ValuationProfileConfig, error) {
+	profiles := builtInValuationProfiles()
+	if !helpers.IsFileExists(filePath) {
+		return profiles, nil
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to read valuation profiles file: %s", filePath)
+	}
+	profilesFile := ValuationProfilesFile{}
+	if err := json.Unmarshal(content, &profilesFile); err != nil {
+		return nil, stacktrace.Propagate(err, "failed to parse valuation profiles file: %s", filePath)
+	}
+
+	for _, profile := range profilesFile.Profiles {
+		if profile.Name == "" {
+			return nil, stacktrace.NewError("every valuation profile in %s needs a name", filePath)
+		}
+		if profile.Undercut < 0 || profile.Undercut >= 1 {
+			return nil, stacktrace.NewError("the undercut of valuation profile %q should be a fraction between 0 and 1, but was %g", profile.Name, profile.Undercut)
+		}
+		for _, tier := range profile.LiquidityTiers {
+			if tier.Haircut < 0 || tier.Haircut > 1 {
+				return nil, stacktrace.NewError("the liquidity haircuts of valuation profile %q should be fractions between 0 and 1, but one was %g", profile.Name, tier.Haircut)
+			}
+		}
+		profiles = helpers.Filter(profiles, func(existing ValuationProfileConfig) bool {
+			return !strings.EqualFold(existing.Name, profile.Name)
+		})
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func trainingCodestoneGroups() ([]*helpers.ItemGroup, error) {
+	groups := []*helpers.ItemGroup{}
+	schools := helpers.OrderBy(helpers.Keys(constants.TrainingSchoolCodestoneGroups), func(school string) string {
+		return school
+	})
+	for _, school := range schools {
+		groupName := constants.TrainingSchoolCodestoneGroups[school]
+		group, exists := helpers.ItemGroupsInstance().Group(groupName)
+		if !exists {
+			return nil, stacktrace.NewError("the %s asks for %s, but there is no item group with that name in %s", school, groupName, constants.ItemGroupsFilePath())
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (c ValuationProfileConfig) Valuation(itemPriceCache caches.ItemPriceCache) (ItemValuation, error) {
+	var valuation ItemValuation = NewMarketValuation(itemPriceCache)
+	if c.Undercut > 0 {
+		valuation = NewUndercutValuation(valuation, c.Undercut)
+	}
+	if len(c.LiquidityTiers) > 0 {
+		valuation = NewLiquidityValuation(valuation, c.LiquidityTiers)
+	}
+	if c.IsTraining || c.StoneValue > 0 {
+		groups, err := trainingCodestoneGroups()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to get the codestones used for training")
+		}
+		valuation = NewCodestoneTrainingValuation(valuation, groups, c.StoneValue)
+	}
+	if len(c.Keep) > 0 {
+		keep := map[string]float64{}
+		for itemName, value := range c.Keep {
+			keep[helpers.ItemAliasesInstance().Canonical(itemName)] = value
+		}
+		valuation = NewKeepValuation(valuation, keep)
+	}
+	return &namedValuation{ItemValuation: valuation, name: c.Name}, nil
+}
+
+type namedValuation struct {
+	ItemValuation
+	name string
+}
+
+func (v *namedValuation) Description() string {
+	return v.name + " (" + v.ItemValuation.Description() + ")"
+}
+
+// The valuation for the named profile in the profiles file, or a built-in one. A positive stoneValue overrides the
+// profile's, and values codestones for training at that amount whichever profile it is.
+func ValuationProfile(name string, itemPriceCache caches.ItemPriceCache, stoneValue float64) (ItemValuation, error) {
+	if name == "" {
+		name = constants.MarketValuationProfile
+	}
+	profiles, err := LoadValuationProfiles(constants.ValuationProfilesFilePath())
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to load valuation profiles")
+	}
+	for _, profile := range profiles {
+		if !strings.EqualFold(profile.Name, name) {
+			continue
+		}
+		if stoneValue > 0 {
+			profile.StoneValue = stoneValue
+		}
+		return profile.Valuation(itemPriceCache)
+	}
+	return nil, stacktrace.NewError("unknown valuation profile %q; expected one of %s", name, strings.Join(helpers.Map(profiles, func(profile ValuationProfileConfig) string {
+		return profile.Name
+	}), ", "))
+}
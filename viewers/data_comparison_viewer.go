@@ -6,12 +6,13 @@ import (
 	"math"
 	"slices"
 	"strconv"
+	"strings"
 
-	"github.com/darienchong/neopets-battledome-analysis/caches"
 	"github.com/darienchong/neopets-battledome-analysis/constants"
 	"github.com/darienchong/neopets-battledome-analysis/helpers"
 	"github.com/darienchong/neopets-battledome-analysis/models"
 	"github.com/darienchong/neopets-battledome-analysis/services"
+	"github.com/darienchong/neopets-battledome-analysis/valuations"
 	"github.com/palantir/stacktrace"
 )
 
@@ -19,21 +20,42 @@ type DataComparisonViewer struct {
 	BattledomeItemsService *services.BattledomeItemsService
 	DataComparisonService  *services.DataComparisonService
 	StatisticsService      *services.StatisticsService
+	BootstrapService       *services.BootstrapService
 }
 
-func NewDataComparisonViewer(battledomeItemsService *services.BattledomeItemsService, dataComparisonService *services.DataComparisonService, statisticsService *services.StatisticsService) *DataComparisonViewer {
+func NewDataComparisonViewer(battledomeItemsService *services.BattledomeItemsService, dataComparisonService *services.DataComparisonService, statisticsService *services.StatisticsService, bootstrapService *services.BootstrapService) *DataComparisonViewer {
 	return &DataComparisonViewer{
 		BattledomeItemsService: battledomeItemsService,
 		DataComparisonService:  dataComparisonService,
 		StatisticsService:      statisticsService,
+		BootstrapService:       bootstrapService,
 	}
 }
 
+// e.g. "1,500 (alice: 1,200, bob: 300)", busiest contributor first
+func contributorSamples(items models.NormalisedBattledomeItems) string {
+	samplesByContributor := items.SamplesByContributor()
+	contributors := helpers.Keys(samplesByContributor)
+	slices.SortFunc(contributors, func(first string, second string) int {
+		if samplesByContributor[first] != samplesByContributor[second] {
+			return samplesByContributor[second] - samplesByContributor[first]
+		}
+		return strings.Compare(first, second)
+	})
+	breakdown := helpers.Map(contributors, func(contributor string) string {
+		return fmt.Sprintf("%s: %s", contributor, helpers.FormatInt(samplesByContributor[contributor]))
+	})
+	if len(breakdown) == 0 {
+		return helpers.FormatInt(items.TotalItemQuantity())
+	}
+	return fmt.Sprintf("%s (%s)", helpers.FormatInt(items.TotalItemQuantity()), strings.Join(breakdown, ", "))
+}
+
 func dryChance(dropRate float64, trials int) float64 {
 	return math.Pow(1-dropRate, float64(trials))
 }
 
-func (v *DataComparisonViewer) generateProfitableItemsTable(itemPriceCache caches.ItemPriceCache, data models.NormalisedBattledomeItems, isRealData bool) (*helpers.Table, error) {
+func (v *DataComparisonViewer) generateProfitableItemsTable(valuation valuations.ItemValuation, data models.NormalisedBattledomeItems, isRealData bool) (*helpers.Table, error) {
 	dataCopy := models.NormalisedBattledomeItems{}
 	for k, v := range data {
 		dataCopy[k] = v.Copy()
@@ -44,7 +66,7 @@ func (v *DataComparisonViewer) generateProfitableItemsTable(itemPriceCache cache
 		"Item Name",
 		"Drop Rate",
 		// Don't include Dry Chance in real data
-		"Price",
+		"Value",
 		"Expectation",
 		"%",
 	}, []string{
@@ -52,7 +74,7 @@ func (v *DataComparisonViewer) generateProfitableItemsTable(itemPriceCache cache
 		"Item Name",
 		"Drop Rate",
 		"Dry Chance",
-		"Price",
+		"Value",
 		"Expectation",
 		"%",
 	})
@@ -65,10 +87,10 @@ func (v *DataComparisonViewer) generateProfitableItemsTable(itemPriceCache cache
 	}
 
 	predictedProfit := helpers.Sum(helpers.Map(helpers.Values(dataCopy), func(item *models.BattledomeItem) float64 {
-		return item.Profit(itemPriceCache)
+		return item.Profit(valuation)
 	}))
 	profitableItems := helpers.OrderByDescending(helpers.Values(dataCopy), func(item *models.BattledomeItem) float64 {
-		return item.Profit(itemPriceCache)
+		return item.Profit(valuation)
 	})
 
 	runningIndex := 1
@@ -82,8 +104,8 @@ func (v *DataComparisonViewer) generateProfitableItemsTable(itemPriceCache cache
 		}
 
 		itemDropRate := item.DropRate(data)
-		expectedItemProfit := itemDropRate * itemPriceCache.Price(string(item.Name)) * constants.BattledomeDropsPerDay
-		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(int(item.Quantity), data.TotalItemQuantity(), constants.SignificanceLevel)
+		expectedItemProfit := itemDropRate * valuation.Value(string(item.Name)) * constants.BattledomeDropsPerDay
+		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.BinomialInterval(int(item.Quantity), data.TotalItemQuantity(), constants.SignificanceLevel)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "failed to generate drop rate confidence interval")
 		}
@@ -93,18 +115,18 @@ func (v *DataComparisonViewer) generateProfitableItemsTable(itemPriceCache cache
 				string(item.Name),
 				fmt.Sprintf("%s ∈ %s%%", helpers.FormatPercentage(itemDropRate), helpers.FormatPercentageRange("[%s, %s]", itemDropRateLeftBound, itemDropRateRightBound)),
 				// Don't include dry chance in real data
-				helpers.FormatFloat(itemPriceCache.Price(string(item.Name))) + " NP",
+				helpers.FormatFloat(valuation.Value(string(item.Name))) + " NP",
 				helpers.FormatFloat(expectedItemProfit) + " NP",
-				helpers.FormatPercentage(item.Profit(itemPriceCache)/predictedProfit) + "%",
+				helpers.FormatPercentage(item.Profit(valuation)/predictedProfit) + "%",
 			},
 			[]string{
 				strconv.Itoa(runningIndex),
 				string(item.Name),
 				helpers.FormatPercentage(item.DropRate(data)) + "%",
 				helpers.FormatPercentage(dryChance(item.DropRate(data), 30*constants.BattledomeDropsPerDay)) + "%",
-				helpers.FormatFloat(itemPriceCache.Price(string(item.Name))) + " NP",
+				helpers.FormatFloat(valuation.Value(string(item.Name))) + " NP",
 				helpers.FormatFloat(expectedItemProfit) + " NP",
-				helpers.FormatPercentage(item.Profit(itemPriceCache)/predictedProfit) + "%",
+				helpers.FormatPercentage(item.Profit(valuation)/predictedProfit) + "%",
 			},
 		)
 		table.AddRow(row)
@@ -115,7 +137,7 @@ func (v *DataComparisonViewer) generateProfitableItemsTable(itemPriceCache cache
 	return table, nil
 }
 
-func (v *DataComparisonViewer) generateArenaProfitableItemsTable(itemPriceCache caches.ItemPriceCache, data models.NormalisedBattledomeItems, generatedItems models.NormalisedBattledomeItems, isRealData bool) (*helpers.Table, error) {
+func (v *DataComparisonViewer) generateArenaProfitableItemsTable(valuation valuations.ItemValuation, data models.NormalisedBattledomeItems, generatedItems models.NormalisedBattledomeItems, isRealData bool) (*helpers.Table, error) {
 	dataCopy := models.NormalisedBattledomeItems{}
 	for k, v := range data {
 		dataCopy[k] = v.Copy()
@@ -126,7 +148,7 @@ func (v *DataComparisonViewer) generateArenaProfitableItemsTable(itemPriceCache
 		"Item Name",
 		"Drop Rate",
 		// Don't include Dry Chance in real data
-		"Price",
+		"Value",
 		"Expectation",
 		"%",
 	}, []string{
@@ -134,7 +156,7 @@ func (v *DataComparisonViewer) generateArenaProfitableItemsTable(itemPriceCache
 		"Item Name",
 		"Drop Rate",
 		"Dry Chance",
-		"Price",
+		"Value",
 		"Expectation",
 		"%",
 	})
@@ -147,15 +169,15 @@ func (v *DataComparisonViewer) generateArenaProfitableItemsTable(itemPriceCache
 	}
 
 	predictedProfit := helpers.Sum(helpers.Map(helpers.Values(dataCopy), func(item *models.BattledomeItem) float64 {
-		return item.Profit(itemPriceCache)
+		return item.Profit(valuation)
 	}))
 	arenaSpecificItems := helpers.Filter(helpers.Values(dataCopy), func(item *models.BattledomeItem) bool {
 		_, exists := generatedItems[item.Name]
-		_, isAdditionalArenaPrize := constants.AdditionalArenaSpecificDrops[string(item.Metadata.Arena)][string(item.Name)]
+		isAdditionalArenaPrize := helpers.BattledomeCatalogueInstance().IsExtraDrop(string(item.Metadata.Arena), string(item.Name))
 		return exists || isAdditionalArenaPrize
 	})
 	profitableItems := helpers.OrderByDescending(arenaSpecificItems, func(item *models.BattledomeItem) float64 {
-		return item.Profit(itemPriceCache)
+		return item.Profit(valuation)
 	})
 
 	runningIndex := 1
@@ -169,8 +191,8 @@ func (v *DataComparisonViewer) generateArenaProfitableItemsTable(itemPriceCache
 		}
 
 		itemDropRate := item.DropRate(data)
-		expectedItemProfit := itemDropRate * itemPriceCache.Price(string(item.Name)) * constants.BattledomeDropsPerDay
-		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(int(item.Quantity), data.TotalItemQuantity(), constants.SignificanceLevel)
+		expectedItemProfit := itemDropRate * valuation.Value(string(item.Name)) * constants.BattledomeDropsPerDay
+		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.BinomialInterval(int(item.Quantity), data.TotalItemQuantity(), constants.SignificanceLevel)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "failed to generate drop rate confidence interval")
 		}
@@ -180,18 +202,18 @@ func (v *DataComparisonViewer) generateArenaProfitableItemsTable(itemPriceCache
 				string(item.Name),
 				fmt.Sprintf("%s ∈ %s%%", helpers.FormatPercentage(itemDropRate), helpers.FormatPercentageRange("[%s, %s]", itemDropRateLeftBound, itemDropRateRightBound)),
 				// Don't include dry chance in real data
-				helpers.FormatFloat(itemPriceCache.Price(string(item.Name))) + " NP",
+				helpers.FormatFloat(valuation.Value(string(item.Name))) + " NP",
 				helpers.FormatFloat(expectedItemProfit) + " NP",
-				helpers.FormatPercentage(item.Profit(itemPriceCache)/predictedProfit) + "%",
+				helpers.FormatPercentage(item.Profit(valuation)/predictedProfit) + "%",
 			},
 			[]string{
 				strconv.Itoa(runningIndex),
 				string(item.Name),
 				helpers.FormatPercentage(item.DropRate(data)) + "%",
 				helpers.FormatPercentage(dryChance(item.DropRate(data), 30*constants.BattledomeDropsPerDay)) + "%",
-				helpers.FormatFloat(itemPriceCache.Price(string(item.Name))) + " NP",
+				helpers.FormatFloat(valuation.Value(string(item.Name))) + " NP",
 				helpers.FormatFloat(expectedItemProfit) + " NP",
-				helpers.FormatPercentage(item.Profit(itemPriceCache)/predictedProfit) + "%",
+				helpers.FormatPercentage(item.Profit(valuation)/predictedProfit) + "%",
 			},
 		)
 		table.AddRow(row)
@@ -202,90 +224,176 @@ func (v *DataComparisonViewer) generateArenaProfitableItemsTable(itemPriceCache
 	return table, nil
 }
 
-func (v *DataComparisonViewer) generateCodestoneDropRatesTable(realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems, codestoneList []string /* Keep as string to prevent circular dependency*/) (*helpers.Table, error) {
-	var tableName string
-	if slices.Contains(codestoneList, constants.BrownCodestones[0]) {
-		tableName = "Brown Codestone Drop Rates"
-	} else if slices.Contains(codestoneList, constants.RedCodestones[0]) {
-		tableName = "Red Codestone Drop Rates"
-	} else {
-		tableName = "Codestone Drop Rates"
+type itemGroupDropRate struct {
+	quantity int
+	dropRate float64
+	// Of the profit from every item in the data
+	profitShare float64
+}
+
+func groupDropRate(valuation valuations.ItemValuation, data models.NormalisedBattledomeItems, group *helpers.ItemGroup) itemGroupDropRate {
+	groupItems := helpers.Filter(helpers.Values(data), func(item *models.BattledomeItem) bool {
+		return group.Contains(string(item.Name))
+	})
+	quantity := helpers.Sum(helpers.Map(groupItems, func(item *models.BattledomeItem) int {
+		return int(item.Quantity)
+	}))
+	totalProfit := helpers.Sum(helpers.Map(helpers.Values(data), func(item *models.BattledomeItem) float64 {
+		return item.Profit(valuation)
+	}))
+	groupProfit := helpers.Sum(helpers.Map(groupItems, func(item *models.BattledomeItem) float64 {
+		return item.Profit(valuation)
+	}))
+
+	dropRate := itemGroupDropRate{quantity: quantity}
+	if totalItemQuantity := data.TotalItemQuantity(); totalItemQuantity > 0 {
+		dropRate.dropRate = float64(quantity) / float64(totalItemQuantity)
+	}
+	if totalProfit != 0 {
+		dropRate.profitShare = groupProfit / totalProfit
 	}
+	return dropRate
+}
 
-	table := helpers.NewNamedTable(tableName, []string{
-		"Item Name",
+// e.g. "1.23 ∈ [1.01, 1.48]%"
+func (v *DataComparisonViewer) formatRealDropRate(quantity int, data models.NormalisedBattledomeItems) (string, error) {
+	leftBound, rightBound, err := v.StatisticsService.BinomialInterval(quantity, data.TotalItemQuantity(), constants.SignificanceLevel)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "failed to generate drop rate confidence interval")
+	}
+	dropRate := 0.0
+	if data.TotalItemQuantity() > 0 {
+		dropRate = float64(quantity) / float64(data.TotalItemQuantity())
+	}
+	return fmt.Sprintf("%s ∈ %s%%", helpers.FormatPercentage(dropRate), helpers.FormatPercentageRange("[%s, %s]", leftBound, rightBound)), nil
+}
+
+func (v *DataComparisonViewer) generateItemGroupsTable(valuation valuations.ItemValuation, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems, groups []*helpers.ItemGroup) (*helpers.Table, error) {
+	table := helpers.NewNamedTable("Item group drop rates", []string{
+		"Group",
 		"Predicted",
 		"Real",
+		"Predicted % of Profit",
+		"Real % of Profit",
 	})
-	table.IsLastRowDistinct = true
 
-	realCodestones := helpers.ToMap(helpers.Filter(helpers.Values(realData), func(itemProfit *models.BattledomeItem) bool {
-		return slices.Contains(codestoneList, string(itemProfit.Name))
-	}), func(itemProfit *models.BattledomeItem) models.ItemName {
-		return itemProfit.Name
-	}, func(itemProfit *models.BattledomeItem) *models.BattledomeItem {
-		return itemProfit
-	})
-	generatedCodestones := helpers.ToMap(helpers.Filter(helpers.Values(generatedData), func(itemProfit *models.BattledomeItem) bool {
-		return slices.Contains(codestoneList, string(itemProfit.Name))
-	}), func(itemProfit *models.BattledomeItem) models.ItemName {
-		return itemProfit.Name
-	}, func(itemProfit *models.BattledomeItem) *models.BattledomeItem {
-		return itemProfit
-	})
-
-	realTotalCodestoneCount := helpers.Sum(helpers.Map(helpers.Values(realCodestones), func(item *models.BattledomeItem) float64 {
-		return float64(item.Quantity)
-	}))
-	generatedTotalCodestoneDropRate := helpers.Sum(helpers.Map(helpers.Values(generatedCodestones), func(profit *models.BattledomeItem) float64 {
-		return profit.DropRate(generatedData)
-	}))
+	for _, group := range groups {
+		generatedDropRate := groupDropRate(valuation, generatedData, group)
+		realDropRate := groupDropRate(valuation, realData, group)
+		formattedRealDropRate, err := v.formatRealDropRate(realDropRate.quantity, realData)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to format drop rate of %s", group.Name)
+		}
 
-	slices.Sort(codestoneList)
-	for _, codestoneName := range codestoneList {
-		realCodestones, existsInReal := realCodestones[models.ItemName(codestoneName)]
-		generatedCodestones, existsInGenerated := generatedCodestones[models.ItemName(codestoneName)]
-		var realDropRate float64 = 0.0
-		var realMinDropRate float64 = 0.0
-		var realMaxDropRate float64 = 0.0
-		var err error
+		table.AddRow([]string{
+			group.Name,
+			helpers.FormatPercentage(generatedDropRate.dropRate) + "%",
+			formattedRealDropRate,
+			helpers.FormatPercentage(generatedDropRate.profitShare) + "%",
+			helpers.FormatPercentage(realDropRate.profitShare) + "%",
+		})
+	}
 
-		var generatedCodestoneDropRate float64 = 0.0
+	return table, nil
+}
 
-		if existsInGenerated {
-			generatedCodestoneDropRate = generatedCodestones.DropRate(generatedData)
+// Every item named in the group, and any other item in the data that it matches
+func itemGroupItemNames(group *helpers.ItemGroup, datas ...models.NormalisedBattledomeItems) []string {
+	itemNames := map[string]bool{}
+	for _, itemName := range group.ItemNames() {
+		itemNames[itemName] = true
+	}
+	for _, data := range datas {
+		for itemName := range data {
+			if group.Contains(string(itemName)) {
+				itemNames[string(itemName)] = true
+			}
 		}
+	}
+	orderedItemNames := helpers.Keys(itemNames)
+	slices.Sort(orderedItemNames)
+	return orderedItemNames
+}
 
-		if existsInReal {
-			realDropRate = realCodestones.DropRate(realData)
-			realMinDropRate, realMaxDropRate, err = v.StatisticsService.ClopperPearsonInterval(int(realCodestones.Quantity), realData.TotalItemQuantity(), constants.SignificanceLevel)
-			if err != nil {
-				return nil, stacktrace.Propagate(err, "failed to generate drop rate confidence interval")
-			}
+func (v *DataComparisonViewer) generateItemGroupDropRatesTable(realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems, group *helpers.ItemGroup) (*helpers.Table, error) {
+	table := helpers.NewNamedTable(group.Name+" Drop Rates", []string{
+		"Item Name",
+		"Predicted",
+		"Real",
+	})
+	table.IsLastRowDistinct = true
+
+	realTotalCount := 0
+	generatedTotalDropRate := 0.0
+	for _, itemName := range itemGroupItemNames(group, realData, generatedData) {
+		generatedDropRate := 0.0
+		if generatedItem, exists := generatedData[models.ItemName(itemName)]; exists {
+			generatedDropRate = generatedItem.DropRate(generatedData)
 		}
+		realCount := 0
+		if realItem, exists := realData[models.ItemName(itemName)]; exists {
+			realCount = int(realItem.Quantity)
+		}
+		formattedRealDropRate, err := v.formatRealDropRate(realCount, realData)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to format drop rate of %q", itemName)
+		}
+		realTotalCount += realCount
+		generatedTotalDropRate += generatedDropRate
 
 		table.AddRow([]string{
-			codestoneName,
-			helpers.FormatPercentage(generatedCodestoneDropRate) + "%",
-			fmt.Sprintf("%s ∈ %s%%", helpers.FormatPercentage(realDropRate), helpers.FormatPercentageRange("[%s, %s]", realMinDropRate, realMaxDropRate)),
+			itemName,
+			helpers.FormatPercentage(generatedDropRate) + "%",
+			formattedRealDropRate,
 		})
 	}
 
-	realTotalMinDropRate, realTotalMaxDropRate, err := v.StatisticsService.ClopperPearsonInterval(int(realTotalCodestoneCount), realData.TotalItemQuantity(), constants.SignificanceLevel)
+	realTotalMinDropRate, realTotalMaxDropRate, err := v.StatisticsService.BinomialInterval(realTotalCount, realData.TotalItemQuantity(), constants.SignificanceLevel)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate drop rate confidence interval")
 	}
 
 	table.AddRow([]string{
 		"Sum",
-		helpers.FormatPercentage(generatedTotalCodestoneDropRate) + "%",
+		helpers.FormatPercentage(generatedTotalDropRate) + "%",
 		helpers.When(realTotalMinDropRate == realTotalMaxDropRate, helpers.FormatPercentage(realTotalMinDropRate)+"%", fmt.Sprintf("[%s, %s]%%", helpers.FormatPercentage(realTotalMinDropRate), helpers.FormatPercentage(realTotalMaxDropRate))),
 	})
 
 	return table, nil
 }
 
-func (v *DataComparisonViewer) ViewChallengerComparison(itemPriceCache caches.ItemPriceCache, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) ([]string, error) {
+// The summary of every item group, then the drop rates within each group that dropped anything, two to a row
+func (v *DataComparisonViewer) itemGroupLines(valuation valuations.ItemValuation, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems, tableSeparator string) ([]string, error) {
+	groups := helpers.ItemGroupsInstance().Groups
+	if len(groups) == 0 {
+		return []string{}, nil
+	}
+
+	itemGroupsTable, err := v.generateItemGroupsTable(valuation, realData, generatedData, groups)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to generate item groups table")
+	}
+	lines := itemGroupsTable.Lines()
+
+	groupTables := []*helpers.Table{}
+	for _, group := range groups {
+		if groupDropRate(valuation, realData, group).quantity == 0 && groupDropRate(valuation, generatedData, group).quantity == 0 {
+			continue
+		}
+		groupTable, err := v.generateItemGroupDropRatesTable(realData, generatedData, group)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to generate %s drop rates table", group.Name)
+		}
+		groupTables = append(groupTables, groupTable)
+	}
+	for i := 0; i < len(groupTables); i += 2 {
+		lines = append(lines, "\n")
+		lines = append(lines, groupTables[i].LinesWith(tableSeparator, groupTables[i+1:min(i+2, len(groupTables))]...)...)
+	}
+	return lines, nil
+}
+
+func (v *DataComparisonViewer) ViewChallengerComparison(valuation valuations.ItemValuation, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) ([]string, error) {
 	metadata, err := realData.Metadata()
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get metadata")
@@ -297,24 +405,28 @@ func (v *DataComparisonViewer) ViewChallengerComparison(itemPriceCache caches.It
 	})
 	profitComparisonTable.IsLastRowDistinct = true
 
-	generatedMeanProfit, err := generatedData.MeanDropsProfit(itemPriceCache)
+	generatedMeanProfit, err := generatedData.MeanDropsProfit(valuation)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get generated mean drops profit")
 	}
-	generatedProfitStdev, err := generatedData.DropsProfitStdev(itemPriceCache)
+	generatedProfitStdev, err := generatedData.DropsProfitStdev(valuation)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get generated drop profit stdev")
 	}
 
-	realMeanProfit, err := realData.MeanDropsProfit(itemPriceCache)
+	realMeanProfit, err := realData.MeanDropsProfit(valuation)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get real mean drops profit")
 	}
-	realProfitStdev, err := realData.DropsProfitStdev(itemPriceCache)
+	realProfitStdev, err := realData.DropsProfitStdev(valuation)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get real drop profit stdev")
 	}
 
+	profitComparisonTable.AddRow([]string{
+		"Samples",
+		contributorSamples(realData),
+	})
 	profitComparisonTable.AddRow([]string{
 		"Predicted",
 		fmt.Sprintf("%s ± %s NP", helpers.FormatFloat(generatedMeanProfit), helpers.FormatFloat(generatedProfitStdev)),
@@ -328,44 +440,45 @@ func (v *DataComparisonViewer) ViewChallengerComparison(itemPriceCache caches.It
 		fmt.Sprintf("%s NP", helpers.FormatFloat(realMeanProfit-generatedMeanProfit)),
 	})
 
-	realProfitableItemsTable, err := v.generateProfitableItemsTable(itemPriceCache, realData, true)
+	realProfitableItemsTable, err := v.generateProfitableItemsTable(valuation, realData, true)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate profitable items table for real data")
 	}
-	generatedProfitableItemsTable, err := v.generateProfitableItemsTable(itemPriceCache, generatedData, false)
+	generatedProfitableItemsTable, err := v.generateProfitableItemsTable(valuation, generatedData, false)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate profitable items table for generated data")
 	}
 
-	brownCodestoneDropRatesTable, err := v.generateCodestoneDropRatesTable(realData, generatedData, constants.BrownCodestones)
-	if err != nil {
-		return nil, stacktrace.Propagate(err, "failed to generate brown codestones drop rate table for real and generated data")
-	}
-
-	redCodestoneDropRatesTable, err := v.generateCodestoneDropRatesTable(realData, generatedData, constants.RedCodestones)
+	// generatedData includes the challenger's own prize pool if they have one, so it can't tell the two apart
+	generatedArenaData, err := v.BattledomeItemsService.GeneratedDropsByArena(metadata.Arena)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "failed to generate red codestones drop rate table for real and generated data")
+		return nil, stacktrace.Propagate(err, "failed to generate drops by arena for %q", metadata.Arena)
 	}
 
-	arenaSpecificDropsTable, err := v.generateArenaSpecificDropsTable(itemPriceCache, realData, generatedData)
+	arenaSpecificDropsTable, err := v.generateArenaSpecificDropsTable(valuation, realData, generatedArenaData)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate arena-specific drop rate table for real and generated data")
 	}
 
-	challengerSpecificDropsTable, err := v.generateChallengerSpecificDropsTable(itemPriceCache, realData, generatedData)
+	challengerSpecificDropsTable, err := v.generateChallengerSpecificDropsTable(valuation, realData, generatedArenaData)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed ot generate challenger-specific drop rate table for real and generated data")
 	}
 
 	tableSeparator := "  "
 
+	itemGroupLines, err := v.itemGroupLines(valuation, realData, generatedData, tableSeparator)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "failed to generate item group drop rate tables for real and generated data")
+	}
+
 	lines := []string{}
 	lines = append(lines, profitComparisonTable.Lines()...)
 	lines = append(lines, "\n")
 	lines = append(lines, fmt.Sprintf("Top %d most profitable items", constants.NumberOfItemsToPrint))
 	lines = append(lines, generatedProfitableItemsTable.LinesWith(tableSeparator, realProfitableItemsTable)...)
 	lines = append(lines, "\n")
-	lines = append(lines, brownCodestoneDropRatesTable.LinesWith(tableSeparator, redCodestoneDropRatesTable)...)
+	lines = append(lines, itemGroupLines...)
 	lines = append(lines, "\n")
 	lines = append(lines, arenaSpecificDropsTable.LinesWith(tableSeparator, challengerSpecificDropsTable)...)
 
@@ -373,18 +486,15 @@ func (v *DataComparisonViewer) ViewChallengerComparison(itemPriceCache caches.It
 }
 
 func isArenaSpecificDrop(item *models.BattledomeItem, items models.NormalisedBattledomeItems) bool {
-	itemName := item.Name
-	_, exists := items[itemName]
-	_, isArenaSpecificItem := constants.AdditionalArenaSpecificDrops[string(item.Metadata.Arena)][string(itemName)]
-	return exists || isArenaSpecificItem
+	return item.IsArenaDrop(items)
 }
 
-func (v *DataComparisonViewer) generateArenaSpecificDropsTable(itemPriceCache caches.ItemPriceCache, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) (*helpers.Table, error) {
+func (v *DataComparisonViewer) generateArenaSpecificDropsTable(valuation valuations.ItemValuation, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) (*helpers.Table, error) {
 	table := helpers.NewNamedTable("Arena-specific drops", []string{
 		"i",
 		"Item Name",
 		"Drop Rate",
-		"Price",
+		"Value",
 		"Expectation",
 		"%",
 	})
@@ -394,13 +504,13 @@ func (v *DataComparisonViewer) generateArenaSpecificDropsTable(itemPriceCache ca
 		return isArenaSpecificDrop(item, generatedData)
 	})
 	orderedRealItems := helpers.OrderByDescending(realItems, func(item *models.BattledomeItem) float64 {
-		return float64(item.Quantity) * itemPriceCache.Price(string(item.Name))
+		return float64(item.Quantity) * valuation.Value(string(item.Name))
 	})
 	totalExpectation := helpers.Sum(helpers.Map(helpers.Values(realData), func(item *models.BattledomeItem) float64 {
-		return item.DropRate(realData) * itemPriceCache.Price(string(item.Name)) * constants.BattledomeDropsPerDay
+		return item.DropRate(realData) * valuation.Value(string(item.Name)) * constants.BattledomeDropsPerDay
 	}))
 	arenaSpecificDropsExpectation := helpers.Sum(helpers.Map(realItems, func(item *models.BattledomeItem) float64 {
-		return item.DropRate(realData) * itemPriceCache.Price(string(item.Name)) * constants.BattledomeDropsPerDay
+		return item.DropRate(realData) * valuation.Value(string(item.Name)) * constants.BattledomeDropsPerDay
 	}))
 
 	totalArenaItemCount := helpers.Sum(helpers.Map(realItems, func(item *models.BattledomeItem) int {
@@ -410,7 +520,7 @@ func (v *DataComparisonViewer) generateArenaSpecificDropsTable(itemPriceCache ca
 
 		return int(item.Quantity)
 	}))
-	totalDropRateLeftBound, totalDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(totalArenaItemCount, realData.TotalItemQuantity(), constants.SignificanceLevel)
+	totalDropRateLeftBound, totalDropRateRightBound, err := v.StatisticsService.BinomialInterval(totalArenaItemCount, realData.TotalItemQuantity(), constants.SignificanceLevel)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate total drop rate bounds")
 	}
@@ -420,19 +530,19 @@ func (v *DataComparisonViewer) generateArenaSpecificDropsTable(itemPriceCache ca
 			break
 		}
 
-		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(int(item.Quantity), realData.TotalItemQuantity(), constants.SignificanceLevel)
+		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.BinomialInterval(int(item.Quantity), realData.TotalItemQuantity(), constants.SignificanceLevel)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "failed to generate item drop bounds")
 		}
 		itemDropRate := item.DropRate(realData)
-		itemPrice := itemPriceCache.Price(string(item.Name))
-		itemExpectation := itemDropRate * itemPrice * constants.BattledomeDropsPerDay
+		itemValue := valuation.Value(string(item.Name))
+		itemExpectation := itemDropRate * itemValue * constants.BattledomeDropsPerDay
 
 		table.AddRow([]string{
 			strconv.Itoa(i + 1),
 			string(item.Name),
 			helpers.When(itemDropRateLeftBound == itemDropRateRightBound, fmt.Sprintf("%s%%", helpers.FormatPercentage(itemDropRateLeftBound)), fmt.Sprintf("[%s, %s]%%", helpers.FormatPercentage(itemDropRateLeftBound), helpers.FormatPercentage(itemDropRateRightBound))),
-			helpers.FormatFloat(itemPrice) + " NP",
+			helpers.FormatFloat(itemValue) + " NP",
 			helpers.FormatFloat(itemExpectation) + " NP",
 			helpers.FormatPercentage(itemExpectation/totalExpectation) + "%",
 		})
@@ -452,12 +562,12 @@ func (v *DataComparisonViewer) generateArenaSpecificDropsTable(itemPriceCache ca
 	return table, nil
 }
 
-func (v *DataComparisonViewer) generateChallengerSpecificDropsTable(itemPriceCache caches.ItemPriceCache, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) (*helpers.Table, error) {
+func (v *DataComparisonViewer) generateChallengerSpecificDropsTable(valuation valuations.ItemValuation, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) (*helpers.Table, error) {
 	table := helpers.NewNamedTable("Challenger-specific drops", []string{
 		"i",
 		"Item Name",
 		"Drop Rate",
-		"Price",
+		"Value",
 		"Expectation",
 		"%",
 	})
@@ -467,13 +577,13 @@ func (v *DataComparisonViewer) generateChallengerSpecificDropsTable(itemPriceCac
 		return !isArenaSpecificDrop(item, generatedData)
 	})
 	orderedRealItems := helpers.OrderByDescending(realItems, func(item *models.BattledomeItem) float64 {
-		return float64(item.Quantity) * itemPriceCache.Price(string(item.Name))
+		return float64(item.Quantity) * valuation.Value(string(item.Name))
 	})
 	totalExpectation := helpers.Sum(helpers.Map(helpers.Values(realData), func(item *models.BattledomeItem) float64 {
-		return item.DropRate(realData) * itemPriceCache.Price(string(item.Name)) * constants.BattledomeDropsPerDay
+		return item.DropRate(realData) * valuation.Value(string(item.Name)) * constants.BattledomeDropsPerDay
 	}))
 	challengerSpecificDropsExpectation := helpers.Sum(helpers.Map(realItems, func(item *models.BattledomeItem) float64 {
-		return item.DropRate(realData) * itemPriceCache.Price(string(item.Name)) * constants.BattledomeDropsPerDay
+		return item.DropRate(realData) * valuation.Value(string(item.Name)) * constants.BattledomeDropsPerDay
 	}))
 
 	totalChallengerItemCount := helpers.Sum(helpers.Map(realItems, func(item *models.BattledomeItem) int {
@@ -483,7 +593,7 @@ func (v *DataComparisonViewer) generateChallengerSpecificDropsTable(itemPriceCac
 
 		return int(item.Quantity)
 	}))
-	totalDropRateLeftBound, totalDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(totalChallengerItemCount, realData.TotalItemQuantity(), constants.SignificanceLevel)
+	totalDropRateLeftBound, totalDropRateRightBound, err := v.StatisticsService.BinomialInterval(totalChallengerItemCount, realData.TotalItemQuantity(), constants.SignificanceLevel)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate total drop rate bounds")
 	}
@@ -493,19 +603,19 @@ func (v *DataComparisonViewer) generateChallengerSpecificDropsTable(itemPriceCac
 			break
 		}
 
-		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(int(item.Quantity), realData.TotalItemQuantity(), constants.SignificanceLevel)
+		itemDropRateLeftBound, itemDropRateRightBound, err := v.StatisticsService.BinomialInterval(int(item.Quantity), realData.TotalItemQuantity(), constants.SignificanceLevel)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "failed to generate item drop bounds")
 		}
 		itemDropRate := item.DropRate(realData)
-		itemPrice := itemPriceCache.Price(string(item.Name))
-		itemExpectation := itemDropRate * itemPrice * constants.BattledomeDropsPerDay
+		itemValue := valuation.Value(string(item.Name))
+		itemExpectation := itemDropRate * itemValue * constants.BattledomeDropsPerDay
 
 		table.AddRow([]string{
 			strconv.Itoa(i + 1),
 			string(item.Name),
 			helpers.When(itemDropRateLeftBound == itemDropRateRightBound, fmt.Sprintf("%s%%", helpers.FormatPercentage(itemDropRateLeftBound)), fmt.Sprintf("[%s, %s]%%", helpers.FormatPercentage(itemDropRateLeftBound), helpers.FormatPercentage(itemDropRateRightBound))),
-			helpers.FormatFloat(itemPrice) + " NP",
+			helpers.FormatFloat(itemValue) + " NP",
 			helpers.FormatFloat(itemExpectation) + " NP",
 			helpers.FormatPercentage(itemExpectation/totalExpectation) + "%",
 		})
@@ -525,9 +635,9 @@ func (v *DataComparisonViewer) generateChallengerSpecificDropsTable(itemPriceCac
 	return table, nil
 }
 
-func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.ItemPriceCache, challengerItems []models.NormalisedBattledomeItems) ([]string, error) {
+func (v *DataComparisonViewer) ViewChallengerComparisons(valuation valuations.ItemValuation, challengerItems []models.NormalisedBattledomeItems) ([]string, error) {
 	challengerItems = helpers.OrderByDescending(challengerItems, func(items models.NormalisedBattledomeItems) float64 {
-		meanDropsProfit, err := items.MeanDropsProfit(itemPriceCache)
+		meanDropsProfit, err := items.MeanDropsProfit(valuation)
 		if err != nil {
 			return 0.0
 		}
@@ -544,16 +654,19 @@ func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.I
 		"Predicted Profit",
 	})
 
-	arenaAndChallengerDropRateTable := helpers.NewNamedTable("Arena/challenger-specific drop rate comparison", []string{
+	itemGroups := helpers.ItemGroupsInstance().Groups
+	dropRateHeaders := []string{
 		"i",
 		"Arena",
 		"Challenger",
 		"Difficulty",
 		"Arena Drop Rate",
-		"Brown Codestone Drop Rate",
-		"Red Codestone Drop Rate",
-		"Challenger Drop Rate",
-	})
+	}
+	dropRateHeaders = append(dropRateHeaders, helpers.Map(itemGroups, func(group *helpers.ItemGroup) string {
+		return group.Name + " Drop Rate"
+	})...)
+	dropRateHeaders = append(dropRateHeaders, "Challenger Drop Rate")
+	arenaAndChallengerDropRateTable := helpers.NewNamedTable("Arena/challenger-specific drop rate comparison", dropRateHeaders)
 
 	for i, items := range challengerItems {
 		metadata, err := items.Metadata()
@@ -561,61 +674,40 @@ func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.I
 			return nil, helpers.PropagateWithSerialisedValue(err, "failed to get metadata from %s", "failed to get metadata from items; additionally encountered an error when trying to serialise the items for logging: %s", items)
 		}
 
-		actualProfit, err := items.MeanDropsProfit(itemPriceCache)
+		actualProfit, err := items.MeanDropsProfit(valuation)
 		if err != nil {
 			return nil, helpers.PropagateWithSerialisedValue(err, "failed to get mean drops profit from %s", "failed to get mean drops profit from items; additionally encountered an error when trying to serialise the items for logging: %s", items)
 		}
 
-		actualProfitLeftBound, actualProfitRightBound, err := items.ProfitConfidenceInterval(itemPriceCache)
+		actualProfitLeftBound, actualProfitRightBound, err := v.BootstrapService.ProfitConfidenceInterval(valuation, items)
 		if err != nil {
 			return nil, helpers.PropagateWithSerialisedValue(err, "failed to get profit confidence interval from %s", "failed to get profit confidence interval from items; additionally encountered an error when trying to serialise the items for logging: %s", items)
 		}
 
-		totalRealBrownCodestoneQuantity := helpers.Sum(
-			helpers.Map(
-				helpers.Filter(
-					helpers.Values(items),
-					func(item *models.BattledomeItem) bool {
-						return slices.Contains(constants.BrownCodestones, string(item.Name))
-					}),
-				func(item *models.BattledomeItem) int {
-					return int(item.Quantity)
-				},
-			),
-		)
-		totalRealRedCodestoneQuantity := helpers.Sum(
-			helpers.Map(
-				helpers.Filter(
-					helpers.Values(items),
-					func(item *models.BattledomeItem) bool {
-						return slices.Contains(constants.RedCodestones, string(item.Name))
-					}),
-				func(item *models.BattledomeItem) int {
-					return int(item.Quantity)
-				},
-			),
-		)
-		totalRealItemQuantity := items.TotalItemQuantity()
-		brownCodestoneDropRateLeftBound, brownCodestoneDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(totalRealBrownCodestoneQuantity, totalRealItemQuantity, constants.SignificanceLevel)
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "failed to generate confidence interval for brown codestone drop rates for %q", metadata.Arena)
-		}
-		redCodestoneDropRateLeftBound, redCodestoneDropRateRightBound, err := v.StatisticsService.ClopperPearsonInterval(totalRealRedCodestoneQuantity, totalRealItemQuantity, constants.SignificanceLevel)
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "failed to generate confidence interval for red codestone drop rates for %q", metadata.Arena)
+		itemGroupDropRates := []string{}
+		for _, group := range itemGroups {
+			formattedDropRate, err := v.formatRealDropRate(groupDropRate(valuation, items, group).quantity, items)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "failed to format %s drop rate for %q", group.Name, metadata.String())
+			}
+			itemGroupDropRates = append(itemGroupDropRates, formattedDropRate)
 		}
 
 		generatedItems, err := v.BattledomeItemsService.GeneratedDropsByArena(metadata.Arena)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "failed to generate drops by arena for %q", metadata.Arena)
 		}
+		generatedFightItems, err := v.BattledomeItemsService.GeneratedDropsByMetadata(metadata)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "failed to generate drops for %q", metadata.String())
+		}
 
-		generatedProfit, err := generatedItems.MeanDropsProfit(itemPriceCache)
+		generatedProfit, err := generatedFightItems.MeanDropsProfit(valuation)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "failed to get mean drops profit for generated items")
 		}
 
-		generatedProfitLeftBound, generatedProfitRightBound, err := generatedItems.ProfitConfidenceInterval(itemPriceCache)
+		generatedProfitLeftBound, generatedProfitRightBound, err := v.BootstrapService.ProfitConfidenceInterval(valuation, generatedFightItems)
 		if err != nil {
 			return nil, helpers.PropagateWithSerialisedValue(err, "failed to get profit confidence interval from %s", "failed to get profit confidence interval from items; additionally encountered an error when trying to serialise the items for logging: %s", items)
 		}
@@ -625,7 +717,7 @@ func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.I
 			string(metadata.Arena),
 			string(metadata.Challenger),
 			string(metadata.Difficulty),
-			helpers.FormatInt(items.TotalItemQuantity()),
+			contributorSamples(items),
 			fmt.Sprintf("%s ∈ %s NP", helpers.FormatFloat(actualProfit), helpers.FormatFloatRange("[%s, %s]", actualProfitLeftBound, actualProfitRightBound)),
 			fmt.Sprintf("%s ∈ %s NP", helpers.FormatFloat(generatedProfit), helpers.FormatFloatRange("[%s, %s]", generatedProfitLeftBound, generatedProfitRightBound)),
 		})
@@ -638,7 +730,7 @@ func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.I
 				}
 
 				_, exists := generatedItems[item.Name]
-				_, isArenaSpecificItem := constants.AdditionalArenaSpecificDrops[string(item.Metadata.Arena)][string(item.Name)]
+				isArenaSpecificItem := helpers.BattledomeCatalogueInstance().IsExtraDrop(string(item.Metadata.Arena), string(item.Name))
 				if !exists && !isArenaSpecificItem {
 					return 0
 				}
@@ -655,7 +747,7 @@ func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.I
 				}
 
 				_, exists := generatedItems[item.Name]
-				_, isArenaSpecificItem := constants.AdditionalArenaSpecificDrops[string(item.Metadata.Arena)][string(item.Name)]
+				isArenaSpecificItem := helpers.BattledomeCatalogueInstance().IsExtraDrop(string(item.Metadata.Arena), string(item.Name))
 				if exists || isArenaSpecificItem {
 					return 0
 				}
@@ -664,16 +756,16 @@ func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.I
 			},
 		))
 
-		arenaAndChallengerDropRateTable.AddRow([]string{
+		row := []string{
 			strconv.Itoa(i + 1),
 			string(metadata.Arena),
 			string(metadata.Challenger),
 			string(metadata.Difficulty),
 			helpers.FormatPercentage(float64(arenaDropsCount)/float64(arenaDropsCount+challengerDropsCount)) + "%",
-			fmt.Sprintf("%s ∈ [%s, %s]%%", helpers.FormatPercentage(float64(totalRealBrownCodestoneQuantity)/float64(totalRealItemQuantity)), helpers.FormatPercentage(float64(brownCodestoneDropRateLeftBound)), helpers.FormatPercentage(float64(brownCodestoneDropRateRightBound))),
-			fmt.Sprintf("%s ∈ [%s, %s]%%", helpers.FormatPercentage(float64(totalRealRedCodestoneQuantity)/float64(totalRealItemQuantity)), helpers.FormatPercentage(float64(redCodestoneDropRateLeftBound)), helpers.FormatPercentage(float64(redCodestoneDropRateRightBound))),
-			helpers.FormatPercentage(float64(challengerDropsCount)/float64(arenaDropsCount+challengerDropsCount)) + "%",
-		})
+		}
+		row = append(row, itemGroupDropRates...)
+		row = append(row, helpers.FormatPercentage(float64(challengerDropsCount)/float64(arenaDropsCount+challengerDropsCount))+"%")
+		arenaAndChallengerDropRateTable.AddRow(row)
 	}
 
 	lines := profitComparisonTable.Lines()
@@ -682,7 +774,7 @@ func (v *DataComparisonViewer) ViewChallengerComparisons(itemPriceCache caches.I
 	return lines, nil
 }
 
-func (viewer *DataComparisonViewer) ViewArenaComparison(itemPriceCache caches.ItemPriceCache, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) ([]string, error) {
+func (viewer *DataComparisonViewer) ViewArenaComparison(valuation valuations.ItemValuation, realData models.NormalisedBattledomeItems, generatedData models.NormalisedBattledomeItems) ([]string, error) {
 	metadata, err := generatedData.Metadata()
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get metadata")
@@ -701,20 +793,20 @@ func (viewer *DataComparisonViewer) ViewArenaComparison(itemPriceCache caches.It
 	})
 	profitComparisonTable.IsLastRowDistinct = true
 
-	generatedMeanProfit, err := generatedData.MeanDropsProfit(itemPriceCache)
+	generatedMeanProfit, err := generatedData.MeanDropsProfit(valuation)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get mean drops profit")
 	}
-	generatedProfitLeftBound, generatedProfitRightBound, err := generatedData.ProfitConfidenceInterval(itemPriceCache)
+	generatedProfitLeftBound, generatedProfitRightBound, err := viewer.BootstrapService.ProfitConfidenceInterval(valuation, generatedData)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get profit confidence interval")
 	}
 
 	var realMeanProfit float64 = 0.0
 	if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-		realMeanProfit, err = realData.ArenaMeanDropsProfit(itemPriceCache, generatedData)
+		realMeanProfit, err = realData.ArenaMeanDropsProfit(valuation, generatedData)
 	} else {
-		realMeanProfit, err = realData.MeanDropsProfit(itemPriceCache)
+		realMeanProfit, err = realData.MeanDropsProfit(valuation)
 	}
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get mean drops profit")
@@ -723,14 +815,18 @@ func (viewer *DataComparisonViewer) ViewArenaComparison(itemPriceCache caches.It
 	var realProfitLeftBound float64 = 0.0
 	var realProfitRightBound float64 = 0.0
 	if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-		realProfitLeftBound, realProfitRightBound, err = realData.ArenaProfitConfidenceInterval(itemPriceCache, generatedData)
+		realProfitLeftBound, realProfitRightBound, err = viewer.BootstrapService.ArenaProfitConfidenceInterval(valuation, realData, generatedData)
 	} else {
-		realProfitLeftBound, realProfitRightBound, err = realData.ProfitConfidenceInterval(itemPriceCache)
+		realProfitLeftBound, realProfitRightBound, err = viewer.BootstrapService.ProfitConfidenceInterval(valuation, realData)
 	}
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to get profit confidence interval")
 	}
 
+	profitComparisonTable.AddRow([]string{
+		"Samples",
+		contributorSamples(realData),
+	})
 	profitComparisonTable.AddRow([]string{
 		"Predicted",
 		fmt.Sprintf("%s ∈ %s NP", helpers.FormatFloat(generatedMeanProfit), helpers.FormatFloatRange("[%s, %s]", generatedProfitLeftBound, generatedProfitRightBound)),
@@ -746,9 +842,9 @@ func (viewer *DataComparisonViewer) ViewArenaComparison(itemPriceCache caches.It
 
 	var realProfitableItemsTable *helpers.Table
 	if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-		realProfitableItemsTable, err = viewer.generateArenaProfitableItemsTable(itemPriceCache, realData, generatedData, true)
+		realProfitableItemsTable, err = viewer.generateArenaProfitableItemsTable(valuation, realData, generatedData, true)
 	} else {
-		realProfitableItemsTable, err = viewer.generateProfitableItemsTable(itemPriceCache, realData, true)
+		realProfitableItemsTable, err = viewer.generateProfitableItemsTable(valuation, realData, true)
 	}
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate profitable items table")
@@ -756,41 +852,34 @@ func (viewer *DataComparisonViewer) ViewArenaComparison(itemPriceCache caches.It
 
 	var generatedProfitableItemsTable *helpers.Table
 	if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-		generatedProfitableItemsTable, err = viewer.generateArenaProfitableItemsTable(itemPriceCache, generatedData, generatedData, false)
+		generatedProfitableItemsTable, err = viewer.generateArenaProfitableItemsTable(valuation, generatedData, generatedData, false)
 	} else {
-		generatedProfitableItemsTable, err = viewer.generateProfitableItemsTable(itemPriceCache, generatedData, false)
+		generatedProfitableItemsTable, err = viewer.generateProfitableItemsTable(valuation, generatedData, false)
 	}
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "failed to generate profitable items table")
 	}
 
-	var brownCodestoneDropRatesTable *helpers.Table
-	brownCodestoneDropRatesTable, err = viewer.generateCodestoneDropRatesTable(realData, generatedData, constants.BrownCodestones)
-	if err != nil {
-		return nil, stacktrace.Propagate(err, "failed to generate brown codestones drop rates table")
-	}
+	tableSeparator := "\t"
 
-	var redCodestoneDropRatesTable *helpers.Table
-	redCodestoneDropRatesTable, err = viewer.generateCodestoneDropRatesTable(realData, generatedData, constants.RedCodestones)
+	itemGroupLines, err := viewer.itemGroupLines(valuation, realData, generatedData, tableSeparator)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "failed to generate red codestones drop rates table")
+		return nil, stacktrace.Propagate(err, "failed to generate item group drop rate tables")
 	}
 
-	tableSeparator := "\t"
-
 	lines := []string{}
 	lines = append(lines, profitComparisonTable.Lines()...)
 	lines = append(lines, "\n")
 	lines = append(lines, fmt.Sprintf("Top %d most profitable items in %s", constants.NumberOfItemsToPrint, metadata.Arena))
 	lines = append(lines, generatedProfitableItemsTable.LinesWith(tableSeparator, realProfitableItemsTable)...)
 	lines = append(lines, "\n")
-	lines = append(lines, fmt.Sprintf("Codestone drop rates in %s", metadata.Arena))
-	lines = append(lines, brownCodestoneDropRatesTable.LinesWith(tableSeparator, redCodestoneDropRatesTable)...)
+	lines = append(lines, fmt.Sprintf("Item group drop rates in %s", metadata.Arena))
+	lines = append(lines, itemGroupLines...)
 	return lines, nil
 }
 
-func (viewer *DataComparisonViewer) ViewBriefArenaComparisons(itemPriceCache caches.ItemPriceCache, realData map[models.Arena]models.NormalisedBattledomeItems, generatedData map[models.Arena]models.NormalisedBattledomeItems) ([]string, error) {
-	orderedArenas := helpers.OrderByDescending(constants.Arenas, func(arena string) float64 {
+func (viewer *DataComparisonViewer) ViewBriefArenaComparisons(valuation valuations.ItemValuation, realData map[models.Arena]models.NormalisedBattledomeItems, generatedData map[models.Arena]models.NormalisedBattledomeItems) ([]string, error) {
+	orderedArenas := helpers.OrderByDescending(helpers.BattledomeCatalogueInstance().ArenaNames(), func(arena string) float64 {
 		normalisedItems, exists := realData[models.Arena(arena)]
 		if !exists || normalisedItems.TotalItemQuantity() == 0 {
 			return 0.0
@@ -799,9 +888,9 @@ func (viewer *DataComparisonViewer) ViewBriefArenaComparisons(itemPriceCache cac
 		var profit float64 = 0.0
 		var err error
 		if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-			profit, err = normalisedItems.ArenaMeanDropsProfit(itemPriceCache, generatedData[models.Arena(arena)])
+			profit, err = normalisedItems.ArenaMeanDropsProfit(valuation, generatedData[models.Arena(arena)])
 		} else {
-			profit, err = normalisedItems.MeanDropsProfit(itemPriceCache)
+			profit, err = normalisedItems.MeanDropsProfit(valuation)
 		}
 		if err != nil {
 			return 0.0
@@ -819,6 +908,7 @@ func (viewer *DataComparisonViewer) ViewBriefArenaComparisons(itemPriceCache cac
 	table := helpers.NewNamedTable(tableName, []string{
 		"i",
 		"Arena",
+		"Samples",
 		"Predicted",
 		"Actual",
 	})
@@ -833,18 +923,18 @@ func (viewer *DataComparisonViewer) ViewBriefArenaComparisons(itemPriceCache cac
 		realArenaData, exists := realData[models.Arena(arena)]
 		if exists {
 			if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-				realProfitLeftBound, realProfitRightBound, err = realArenaData.ArenaProfitConfidenceInterval(itemPriceCache, generatedData[models.Arena(arena)])
+				realProfitLeftBound, realProfitRightBound, err = viewer.BootstrapService.ArenaProfitConfidenceInterval(valuation, realArenaData, generatedData[models.Arena(arena)])
 			} else {
-				realProfitLeftBound, realProfitRightBound, err = realArenaData.ProfitConfidenceInterval(itemPriceCache)
+				realProfitLeftBound, realProfitRightBound, err = viewer.BootstrapService.ProfitConfidenceInterval(valuation, realArenaData)
 			}
 			if err != nil {
 				return nil, stacktrace.Propagate(err, "failed to get real profit confidence interval")
 			}
 
 			if constants.ShouldIgnoreChallengerDropsInArenaComparison {
-				realMeanProfit, err = realArenaData.ArenaMeanDropsProfit(itemPriceCache, generatedData[models.Arena(arena)])
+				realMeanProfit, err = realArenaData.ArenaMeanDropsProfit(valuation, generatedData[models.Arena(arena)])
 			} else {
-				realMeanProfit, err = realArenaData.MeanDropsProfit(itemPriceCache)
+				realMeanProfit, err = realArenaData.MeanDropsProfit(valuation)
 			}
 			if err != nil {
 				return nil, stacktrace.Propagate(err, "failed to get real mean profit")
@@ -857,11 +947,11 @@ func (viewer *DataComparisonViewer) ViewBriefArenaComparisons(itemPriceCache cac
 
 		generatedArenaData, exists := generatedData[models.Arena(arena)]
 		if exists {
-			generatedProfitLeftBound, generatedProfitRightBound, err = generatedArenaData.ProfitConfidenceInterval(itemPriceCache)
+			generatedProfitLeftBound, generatedProfitRightBound, err = viewer.BootstrapService.ProfitConfidenceInterval(valuation, generatedArenaData)
 			if err != nil {
 				return nil, stacktrace.Propagate(err, "failed to get generated profit confidence interval")
 			}
-			generatedMeanProfit, err = generatedArenaData.MeanDropsProfit(itemPriceCache)
+			generatedMeanProfit, err = generatedArenaData.MeanDropsProfit(valuation)
 			if err != nil {
 				return nil, stacktrace.Propagate(err, "failed to get generated mean profit")
 			}
@@ -870,6 +960,7 @@ func (viewer *DataComparisonViewer) ViewBriefArenaComparisons(itemPriceCache cac
 		table.AddRow([]string{
 			strconv.Itoa(i + 1),
 			arena,
+			contributorSamples(realData[models.Arena(arena)]),
 			fmt.Sprintf("%s ∈ %s NP", helpers.FormatFloat(generatedMeanProfit), helpers.FormatFloatRange("[%s, %s]", generatedProfitLeftBound, generatedProfitRightBound)),
 			fmt.Sprintf("%s ∈ %s NP", helpers.FormatFloat(realMeanProfit), helpers.FormatFloatRange("[%s, %s]", realProfitLeftBound, realProfitRightBound)),
 		})
@@ -0,0 +1,82 @@
+package viewers
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type DryStreakViewer struct{}
+
+func NewDryStreakViewer() *DryStreakViewer {
+	return &DryStreakViewer{}
+}
+
+// e.g. "450 drops (30 days)"
+func formatStreak(drops int, days float64) string {
+	return fmt.Sprintf("%s drops (%s days)", helpers.FormatInt(drops), helpers.FormatFloat(days))
+}
+
+func (v *DryStreakViewer) generateStreakTable(report *models.DryStreakReport) *helpers.Table {
+	table := helpers.NewNamedTable(fmt.Sprintf("%s from %s", report.Item, report.Metadata.FightName()), []string{
+		"Metric",
+		"Value",
+	})
+	table.AddRow([]string{"Drops recorded", helpers.FormatInt(report.Drops)})
+	table.AddRow([]string{"Times dropped", helpers.FormatInt(report.Occurrences)})
+	table.AddRow([]string{"Current streak", formatStreak(report.CurrentStreak, report.CurrentStreakDays())})
+	table.AddRow([]string{"Longest streak", formatStreak(report.LongestStreak, report.LongestStreakDays())})
+	return table
+}
+
+func (v *DryStreakViewer) generateOddsTable(report *models.DryStreakReport) *helpers.Table {
+	table := helpers.NewNamedTable("Days to first drop", []string{
+		"Metric",
+		"Predicted",
+		"Actual",
+	})
+	table.IsLastRowDistinct = true
+
+	addRow := func(metric string, format func(odds *models.DryStreakOdds) string) {
+		row := []string{metric}
+		for _, odds := range []*models.DryStreakOdds{report.Predicted, report.Actual} {
+			if odds == nil {
+				row = append(row, "-")
+				continue
+			}
+			row = append(row, format(odds))
+		}
+		table.AddRow(row)
+	}
+
+	addRow("Drop rate", func(odds *models.DryStreakOdds) string {
+		return helpers.FormatPercentage(odds.Rate) + "%"
+	})
+	addRow("Chance per day", func(odds *models.DryStreakOdds) string {
+		return helpers.FormatPercentage(odds.DailyRate) + "%"
+	})
+	addRow("Expected days", func(odds *models.DryStreakOdds) string {
+		return helpers.FormatFloat(odds.ExpectedDays)
+	})
+	if report.Predicted != nil || report.Actual != nil {
+		percentiles := helpers.When(report.Predicted != nil, report.Predicted, report.Actual).Percentiles
+		for i, percentile := range percentiles {
+			addRow(fmt.Sprintf("%g%% chance of a drop within", math.Round(percentile.Percentile*1000)/10), func(odds *models.DryStreakOdds) string {
+				return helpers.FormatInt(odds.Percentiles[i].Days) + " days"
+			})
+		}
+	}
+	addRow("Chance of a streak this long", func(odds *models.DryStreakOdds) string {
+		return helpers.FormatPercentage(odds.ChanceOfCurrentStreak) + "%"
+	})
+	return table
+}
+
+func (v *DryStreakViewer) ViewDryStreak(report *models.DryStreakReport) []string {
+	lines := v.generateStreakTable(report).Lines()
+	lines = append(lines, "\n")
+	lines = append(lines, v.generateOddsTable(report).Lines()...)
+	return lines
+}
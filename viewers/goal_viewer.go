@@ -0,0 +1,79 @@
+package viewers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type GoalViewer struct{}
+
+func NewGoalViewer() *GoalViewer {
+	return &GoalViewer{}
+}
+
+func formatWishlistEntry(entry models.WishlistEntry) string {
+	return fmt.Sprintf("%s%s x%d", helpers.When(entry.IsAny, "any ", ""), entry.Name, entry.Quantity)
+}
+
+// Unfinished simulations count as taking the simulated number of days, so the mean is a lower bound if any didn't finish
+func formatExpectedDays(plan models.GoalPlan) string {
+	return helpers.When(plan.ChanceOfFinishing < 1, ">= ", "") + helpers.FormatFloat(plan.ExpectedDays)
+}
+
+func (v *GoalViewer) generatePlanTable(report *models.GoalReport) *helpers.Table {
+	headers := []string{
+		"i",
+		"Fight",
+		"Samples",
+		"Expected Days",
+	}
+	for _, percentile := range report.Percentiles {
+		headers = append(headers, fmt.Sprintf("P%g", math.Round(percentile*1000)/10))
+	}
+	headers = append(headers, fmt.Sprintf("Finished within %s days", helpers.FormatInt(report.MaxDays)))
+	table := helpers.NewNamedTable(fmt.Sprintf("Days to finish the wishlist (%s drop rates)", report.Rates), headers)
+
+	for i, plan := range report.Plans {
+		row := []string{
+			helpers.FormatInt(i + 1),
+			plan.Metadata.FightName(),
+			helpers.When(plan.Samples > 0, helpers.FormatInt(plan.Samples), "-"),
+		}
+		if !plan.IsPossible() {
+			row = append(row, "never")
+			for range report.Percentiles {
+				row = append(row, "-")
+			}
+			row = append(row, helpers.When(len(plan.MissingItems) > 0, "never drops "+strings.Join(plan.MissingItems, ", "), "0%"))
+			table.AddRow(row)
+			continue
+		}
+		row = append(row, formatExpectedDays(plan))
+		for _, percentile := range plan.Percentiles {
+			row = append(row, helpers.When(percentile.Days > 0, helpers.FormatInt(percentile.Days), fmt.Sprintf("> %s", helpers.FormatInt(report.MaxDays))))
+		}
+		row = append(row, helpers.FormatPercentage(plan.ChanceOfFinishing)+"%")
+		table.AddRow(row)
+	}
+	return table
+}
+
+func (v *GoalViewer) ViewGoal(report *models.GoalReport) []string {
+	lines := []string{"Wishlist: " + strings.Join(helpers.Map(report.Wishlist, formatWishlistEntry), ", ")}
+	lines = append(lines, v.generatePlanTable(report).Lines()...)
+	if len(report.Plans) == 0 || !report.Plans[0].IsPossible() {
+		lines = append(lines, "No fight can finish the wishlist.")
+		return lines
+	}
+	fastest := report.Plans[0]
+	lines = append(lines, fmt.Sprintf("Fastest: %s, %s days on average (finishes within %s days %s%% of the time)",
+		fastest.Metadata.FightName(),
+		formatExpectedDays(fastest),
+		helpers.FormatInt(report.MaxDays),
+		helpers.FormatPercentage(fastest.ChanceOfFinishing)))
+	return lines
+}
@@ -0,0 +1,103 @@
+package viewers
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type IntervalCoverageViewer struct{}
+
+func NewIntervalCoverageViewer() *IntervalCoverageViewer {
+	return &IntervalCoverageViewer{}
+}
+
+func formatCoverage(coverage models.BinomialIntervalCoverage) string {
+	return fmt.Sprintf("%s%% (%s%% wide)", helpers.FormatPercentage(coverage.Coverage), helpers.FormatPercentage(coverage.MeanWidth))
+}
+
+// One table per drop rate, with a row for each number of days and a column for each method
+func (v *IntervalCoverageViewer) generateRateTable(rate float64, coverages []models.BinomialIntervalCoverage, methods []string) *helpers.Table {
+	table := helpers.NewNamedTable(fmt.Sprintf("Drop rate of %s%%", helpers.FormatPercentage(rate)), append([]string{"Days", "Drops"}, methods...))
+	trials := []int{}
+	for _, coverage := range coverages {
+		if coverage.Rate == rate && !slices.Contains(trials, coverage.Trials) {
+			trials = append(trials, coverage.Trials)
+		}
+	}
+	for _, n := range trials {
+		row := []string{
+			helpers.FormatInt(n / constants.BattledomeDropsPerDay),
+			helpers.FormatInt(n),
+		}
+		for _, method := range methods {
+			index := slices.IndexFunc(coverages, func(coverage models.BinomialIntervalCoverage) bool {
+				return coverage.Rate == rate && coverage.Trials == n && coverage.Method == method
+			})
+			row = append(row, formatCoverage(coverages[index]))
+		}
+		table.AddRow(row)
+	}
+	return table
+}
+
+// How each method does across every drop rate and number of days
+func (v *IntervalCoverageViewer) generateSummaryTable(coverages []models.BinomialIntervalCoverage, methods []string) *helpers.Table {
+	table := helpers.NewNamedTable("Summary", []string{
+		"Method",
+		"Mean Coverage",
+		"Lowest Coverage",
+		"Rate Below Interval",
+		"Rate Above Interval",
+	})
+	for _, method := range methods {
+		methodCoverages := helpers.Filter(coverages, func(coverage models.BinomialIntervalCoverage) bool {
+			return coverage.Method == method
+		})
+		mean := func(value func(coverage models.BinomialIntervalCoverage) float64) float64 {
+			return helpers.Sum(helpers.Map(methodCoverages, value)) / float64(len(methodCoverages))
+		}
+		table.AddRow([]string{
+			method,
+			helpers.FormatPercentage(mean(func(coverage models.BinomialIntervalCoverage) float64 {
+				return coverage.Coverage
+			})) + "%",
+			helpers.FormatPercentage(slices.Min(helpers.Map(methodCoverages, func(coverage models.BinomialIntervalCoverage) float64 {
+				return coverage.Coverage
+			}))) + "%",
+			helpers.FormatPercentage(mean(func(coverage models.BinomialIntervalCoverage) float64 {
+				return coverage.BelowInterval
+			})) + "%",
+			helpers.FormatPercentage(mean(func(coverage models.BinomialIntervalCoverage) float64 {
+				return coverage.AboveInterval
+			})) + "%",
+		})
+	}
+	return table
+}
+
+// coverages must hold every combination of method, drop rate and number of drops
+func (v *IntervalCoverageViewer) ViewCoverage(coverages []models.BinomialIntervalCoverage) []string {
+	methods := []string{}
+	rates := []float64{}
+	for _, coverage := range coverages {
+		if !slices.Contains(methods, coverage.Method) {
+			methods = append(methods, coverage.Method)
+		}
+		if !slices.Contains(rates, coverage.Rate) {
+			rates = append(rates, coverage.Rate)
+		}
+	}
+
+	lines := []string{fmt.Sprintf("Chance that a %s%% interval contains the true drop rate, and its mean width", helpers.FormatPercentage(1-constants.SignificanceLevel))}
+	for _, rate := range rates {
+		lines = append(lines, "\n")
+		lines = append(lines, v.generateRateTable(rate, coverages, methods).Lines()...)
+	}
+	lines = append(lines, "\n")
+	lines = append(lines, v.generateSummaryTable(coverages, methods).Lines()...)
+	return lines
+}
@@ -0,0 +1,107 @@
+package viewers
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type ProfitRiskViewer struct{}
+
+func NewProfitRiskViewer() *ProfitRiskViewer {
+	return &ProfitRiskViewer{}
+}
+
+func formatProfit(profit float64) string {
+	return helpers.FormatFloat(profit) + " NP"
+}
+
+// e.g. "P5" for 0.05
+func percentileLabel(percentile float64) string {
+	return fmt.Sprintf("P%g", math.Round(percentile*1000)/10)
+}
+
+func (v *ProfitRiskViewer) generateRiskTable(report *models.ProfitRiskReport) *helpers.Table {
+	name := models.BattledomeItemMetadata{Arena: report.Arena, Challenger: report.Challenger, Difficulty: report.Difficulty}.FightName()
+	day := fmt.Sprintf("%d day", report.Actual.Day.Days)
+	season := fmt.Sprintf("%d days", report.Actual.Season.Days)
+	table := helpers.NewNamedTable(fmt.Sprintf("Profit risk for %s", name), []string{
+		"Metric",
+		"Predicted (" + day + ")",
+		"Actual (" + day + ")",
+		"Predicted (" + season + ")",
+		"Actual (" + season + ")",
+	})
+	table.IsLastRowDistinct = true
+
+	risks := []models.ProfitRisk{report.Predicted.Day, report.Actual.Day, report.Predicted.Season, report.Actual.Season}
+	addRow := func(metric string, format func(risk models.ProfitRisk) string) {
+		table.AddRow(append([]string{metric}, helpers.Map(risks, format)...))
+	}
+
+	addRow("Mean", func(risk models.ProfitRisk) string {
+		return formatProfit(risk.Mean)
+	})
+	addRow("Median", func(risk models.ProfitRisk) string {
+		return formatProfit(risk.Median)
+	})
+	for i, percentile := range constants.RiskPercentiles {
+		addRow(percentileLabel(percentile), func(risk models.ProfitRisk) string {
+			return formatProfit(risk.Percentiles[i].Profit)
+		})
+	}
+	addRow("Chance of less than", func(risk models.ProfitRisk) string {
+		return fmt.Sprintf("%s%% (%s)", helpers.FormatPercentage(risk.ProbabilityBelowThreshold), formatProfit(risk.Threshold))
+	})
+	addRow(fmt.Sprintf("Mean of worst %s%%", helpers.FormatPercentage(constants.SignificanceLevel)), func(risk models.ProfitRisk) string {
+		return formatProfit(risk.ExpectedShortfall)
+	})
+	table.AddRow([]string{
+		fmt.Sprintf("Top %s%% of drops' share of profit", helpers.FormatPercentage(constants.TopDropsFraction)),
+		helpers.FormatPercentage(report.Predicted.TopDropsProfitShare) + "%",
+		helpers.FormatPercentage(report.Actual.TopDropsProfitShare) + "%",
+		"",
+		"",
+	})
+	return table
+}
+
+// One line per bin, with a bar as long as the bin is tall relative to the tallest
+func histogramLines(title string, bins []models.HistogramBin) []string {
+	lines := []string{title}
+	if len(bins) == 0 {
+		return append(lines, "  (no data)")
+	}
+
+	tallest := max(1, slices.Max(helpers.Map(bins, func(bin models.HistogramBin) int {
+		return bin.Count
+	})))
+	total := helpers.Sum(helpers.Map(bins, func(bin models.HistogramBin) int {
+		return bin.Count
+	}))
+	ranges := helpers.Map(bins, func(bin models.HistogramBin) string {
+		return fmt.Sprintf("%s – %s NP", helpers.FormatFloat(bin.From), helpers.FormatFloat(bin.To))
+	})
+	rangeWidth := slices.Max(helpers.Map(ranges, func(binRange string) int {
+		return len([]rune(binRange))
+	}))
+	for i, bin := range bins {
+		barLength := bin.Count * constants.HistogramBarMaxLength / tallest
+		lines = append(lines, fmt.Sprintf("  %*s | %-*s %s%%", rangeWidth, ranges[i], constants.HistogramBarMaxLength, strings.Repeat("█", barLength), helpers.FormatPercentage(float64(bin.Count)/float64(total))))
+	}
+	return lines
+}
+
+func (v *ProfitRiskViewer) ViewRisk(report *models.ProfitRiskReport) []string {
+	lines := v.generateRiskTable(report).Lines()
+	lines = append(lines, "\n")
+	lines = append(lines, histogramLines(fmt.Sprintf("Actual profit over %d day", report.Actual.Day.Days), report.Actual.Day.Histogram)...)
+	lines = append(lines, "\n")
+	lines = append(lines, histogramLines(fmt.Sprintf("Actual profit over %d days", report.Actual.Season.Days), report.Actual.Season.Histogram)...)
+	return lines
+}
@@ -0,0 +1,96 @@
+package viewers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/constants"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type RecommendationViewer struct{}
+
+func NewRecommendationViewer() *RecommendationViewer {
+	return &RecommendationViewer{}
+}
+
+var criterionDescriptions = map[string]string{
+	"mean":      "mean profit",
+	"lower":     "lower bound of the mean profit's confidence interval",
+	"posterior": "posterior mean profit",
+}
+
+func (v *RecommendationViewer) generateShortlistTable(report *models.RecommendationReport) *helpers.Table {
+	table := helpers.NewNamedTable(fmt.Sprintf("Recommended fights by %s", criterionDescriptions[report.Criterion]), []string{
+		"i",
+		"Fight",
+		"Samples",
+		"Mean Profit",
+		"Posterior Profit",
+		"Score",
+	})
+	for i, recommendation := range report.Recommendations {
+		table.AddRow([]string{
+			helpers.FormatInt(i + 1),
+			recommendation.Metadata.FightName(),
+			helpers.FormatInt(recommendation.Samples),
+			fmt.Sprintf("%s ∈ %s NP", helpers.FormatFloat(recommendation.MeanProfit), helpers.FormatFloatRange("[%s, %s]", recommendation.LeftBound, recommendation.RightBound)),
+			helpers.FormatFloat(recommendation.PosteriorProfit) + " NP",
+			helpers.FormatFloat(recommendation.Score) + " NP",
+		})
+	}
+	return table
+}
+
+// Why the fight is where it is on the shortlist
+func (v *RecommendationViewer) explain(report *models.RecommendationReport, i int) string {
+	recommendation := report.Recommendations[i]
+	reasons := []string{}
+	switch report.Criterion {
+	case "mean":
+		reasons = append(reasons, fmt.Sprintf("makes %s NP a day on average", helpers.FormatFloat(recommendation.MeanProfit)))
+	case "lower":
+		reasons = append(reasons, fmt.Sprintf("makes at least %s NP a day on average at the low end of its %g%% confidence interval", helpers.FormatFloat(recommendation.LeftBound), 100*(1-constants.SignificanceLevel)))
+	case "posterior":
+		reasons = append(reasons, fmt.Sprintf("should make %s NP a day once its mean of %s NP is shrunk towards the %s NP of every fight",
+			helpers.FormatFloat(recommendation.PosteriorProfit),
+			helpers.FormatFloat(recommendation.MeanProfit),
+			helpers.FormatFloat(report.PriorProfit)))
+	}
+	if i > 0 {
+		reasons = append(reasons, fmt.Sprintf("%s NP a day less than #1", helpers.FormatFloat(report.Recommendations[0].Score-recommendation.Score)))
+	}
+	reasons = append(reasons, fmt.Sprintf("%s drops recorded", helpers.FormatInt(recommendation.Samples)))
+	if recommendation.TopItem != "" {
+		reasons = append(reasons, fmt.Sprintf("%s%% of its profit is from %s", helpers.FormatPercentage(recommendation.TopItemShare), recommendation.TopItem))
+	}
+	if recommendation.Unlock != "" {
+		reasons = append(reasons, "needs "+recommendation.Unlock)
+	}
+	return fmt.Sprintf("%d. %s: %s", i+1, recommendation.Metadata.FightName(), strings.Join(reasons, "; "))
+}
+
+func (v *RecommendationViewer) ViewRecommendations(report *models.RecommendationReport) []string {
+	lines := []string{fmt.Sprintf("%d fights met the constraints; %d were ruled out by challenger, %d by difficulty and %d for having fewer than %s drops",
+		report.Candidates,
+		report.UnbeatableChallenger,
+		report.LockedDifficulty,
+		report.TooFewSamples,
+		helpers.FormatInt(report.MinimumSamples))}
+	if len(report.UnknownChallengers) > 0 {
+		lines = append(lines, "No drops are recorded for "+strings.Join(helpers.Map(report.UnknownChallengers, func(challenger models.Challenger) string {
+			return string(challenger)
+		}), ", ")+"; check their spelling")
+	}
+	if len(report.Recommendations) == 0 {
+		lines = append(lines, "No fight meets the constraints.")
+		return lines
+	}
+
+	lines = append(lines, v.generateShortlistTable(report).Lines()...)
+	for i := range report.Recommendations {
+		lines = append(lines, v.explain(report, i))
+	}
+	return lines
+}
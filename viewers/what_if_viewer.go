@@ -0,0 +1,70 @@
+package viewers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/darienchong/neopets-battledome-analysis/caches"
+	"github.com/darienchong/neopets-battledome-analysis/helpers"
+	"github.com/darienchong/neopets-battledome-analysis/models"
+)
+
+type WhatIfViewer struct{}
+
+func NewWhatIfViewer() *WhatIfViewer {
+	return &WhatIfViewer{}
+}
+
+// e.g. "4 → 1 (▲3)"
+func formatRankChange(ranking models.WhatIfRanking) string {
+	change := ranking.RankChange()
+	switch {
+	case change > 0:
+		return fmt.Sprintf("%d → %d (▲%d)", ranking.BaselineRank, ranking.ScenarioRank, change)
+	case change < 0:
+		return fmt.Sprintf("%d → %d (▼%d)", ranking.BaselineRank, ranking.ScenarioRank, -change)
+	default:
+		return fmt.Sprintf("%d", ranking.ScenarioRank)
+	}
+}
+
+func formatProfitDelta(ranking models.WhatIfRanking) string {
+	delta := ranking.ProfitDelta()
+	formatted := fmt.Sprintf("%s%s NP", helpers.When(delta < 0, "-", "+"), helpers.FormatFloat(max(delta, -delta)))
+	if ranking.BaselineProfit == 0 {
+		return formatted
+	}
+	return fmt.Sprintf("%s (%s%s%%)", formatted, helpers.When(delta < 0, "-", "+"), helpers.FormatPercentage(max(delta, -delta)/ranking.BaselineProfit))
+}
+
+func (v *WhatIfViewer) generateRankingTable(name string, rankings []models.WhatIfRanking) *helpers.Table {
+	table := helpers.NewNamedTable(name, []string{
+		"Rank",
+		"Fight",
+		"Samples",
+		"Baseline Profit",
+		"Scenario Profit",
+		"Change",
+	})
+	for _, ranking := range rankings {
+		table.AddRow([]string{
+			formatRankChange(ranking),
+			ranking.Metadata.FightName(),
+			helpers.FormatInt(ranking.Samples),
+			helpers.FormatFloat(ranking.BaselineProfit) + " NP",
+			helpers.FormatFloat(ranking.ScenarioProfit) + " NP",
+			formatProfitDelta(ranking),
+		})
+	}
+	return table
+}
+
+func (v *WhatIfViewer) ViewWhatIf(adjustments []caches.PriceAdjustment, report *models.WhatIfReport) []string {
+	lines := []string{"Scenario: " + strings.Join(helpers.Map(adjustments, func(adjustment caches.PriceAdjustment) string {
+		return adjustment.String()
+	}), ", ")}
+	lines = append(lines, v.generateRankingTable("Arenas by mean profit a day", report.Arenas).Lines()...)
+	lines = append(lines, "\n")
+	lines = append(lines, v.generateRankingTable("Fights by mean profit a day", report.Fights).Lines()...)
+	return lines
+}